@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/db/migration"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/infrastructure/cache"
 	"xyz-multifinance-api/internal/infrastructure/database"
+	"xyz-multifinance-api/internal/infrastructure/notification"
+	"xyz-multifinance-api/internal/infrastructure/ratelimit"
 	internalredis "xyz-multifinance-api/internal/infrastructure/redis"
 	"xyz-multifinance-api/internal/repository"
 	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/jwtkeys"
+	"xyz-multifinance-api/pkg/lifecycle"
+	"xyz-multifinance-api/pkg/metrics"
 	"xyz-multifinance-api/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 
 	apphttp "xyz-multifinance-api/internal/delivery/http"
 )
@@ -31,45 +45,245 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to get underlying sql.DB from GORM: %v", err)
 	}
-	defer sqlDB.Close()
 
+	// Instances share revoked tokens, lockouts, OTPs, locks, rate limits and API usage through Redis, so it is
+	// needed whatever CACHE_BACKEND is. Only the redis backend also keeps the cache there.
 	redisClient, err := internalredis.InitRedisClient(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize Redis client: %v", err)
+		log.Fatalf("Failed to connect to Redis at %s, which is required with every CACHE_BACKEND: %v", cfg.RedisAddr, err)
+	}
+	var cacheStore domain.CacheStore
+	switch cfg.CacheBackend {
+	case "memory":
+		cacheStore = cache.NewMemoryCacheStore(cfg.CacheMaxEntries, cfg.CacheMaxBytes)
+	case "none":
+		cacheStore = cache.NewNoopCacheStore()
+	default:
+		cacheStore = internalredis.NewRedisCacheStore(redisClient)
+	}
+	log.Printf("Using %s cache backend", cfg.CacheBackend)
+	cachePayloads, err := cache.NewPayloads(cache.PayloadOptions{CompressAbove: cfg.CacheCompressAbove, EncryptionKey: cfg.CacheEncryptionKey})
+	if err != nil {
+		log.Fatalf("Failed to initialize cache payload encoding: %v", err)
+	}
+
+	keySet := jwtkeys.NewHMACKeySet(cfg.JWTSecret)
+	if cfg.JWTKeysDir != "" {
+		keySet, err = jwtkeys.LoadKeySet(cfg.JWTKeysDir, cfg.JWTAlgorithm, cfg.JWTKeyRotation, cfg.AccessTokenExpiry)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
 	}
-	cacheStore := internalredis.NewRedisCacheStore(redisClient) // NEW: Create CacheStore instance
-	defer cacheStore.Close()
 
 	router := gin.Default()
+	// Login lockouts, rate limits and the audit trail key on the client IP, so only known proxies may set it
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	router.Use(middleware.RequestID(), middleware.RequestMetrics(), middleware.RequestDeadline(middleware.DeadlineConfig{
+		Default: cfg.RequestTimeout,
+		Routes:  cfg.RequestTimeoutRoutes,
+	}))
 
-	customerRepo := repository.NewCustomerRepository(gormDB, cacheStore)
-	creditLimitRepo := repository.NewCreditLimitRepository(gormDB, cacheStore)
+	customerRepo := repository.NewCustomerRepository(gormDB, cacheStore, cachePayloads)
+	creditLimitRepo := repository.NewCreditLimitRepository(gormDB, cacheStore, cachePayloads)
 	transactionRepo := repository.NewTransactionRepository(gormDB)
+	erasureCertificateRepo := repository.NewErasureCertificateRepository(gormDB)
+	dataExportRepo := repository.NewDataExportRepository(gormDB)
+	staffUserRepo := repository.NewStaffUserRepository(gormDB)
+	staffRecoveryCodeRepo := repository.NewStaffRecoveryCodeRepository(gormDB)
+	auditRepo := repository.NewAuditRepository(gormDB)
+	authSessionRepo := repository.NewAuthSessionRepository(gormDB)
+	apiClientRepo := repository.NewAPIClientRepository(gormDB)
+	apiUsageRepo := repository.NewAPIUsageRepository(gormDB)
+	unitOfWork := repository.NewUnitOfWork(gormDB, cacheStore, cachePayloads)
+	tokenDenylist := internalredis.NewRedisTokenDenylist(redisClient)
+	loginAttemptTracker := internalredis.NewRedisLoginAttemptTracker(redisClient)
+	locker := internalredis.NewRedisLocker(redisClient)
+	customerNotifier := notification.NewLogNotifier()
+	otpService := usecase.NewOTPService(internalredis.NewRedisOTPStore(redisClient), notification.NewLogOTPSender(cfg.OTPOutboxFile), cfg)
+
+	authUseCase := usecase.NewAuthUseCase(customerRepo, staffUserRepo, staffRecoveryCodeRepo, authSessionRepo, tokenDenylist, loginAttemptTracker, customerNotifier, otpService, keySet, cfg)
+	customerUseCase := usecase.NewCustomerUseCase(customerRepo, otpService)
+	creditLimitUseCase := usecase.NewCreditLimitUseCase(creditLimitRepo, customerRepo, locker)
+	transactionUseCase := usecase.NewTransactionUseCase(unitOfWork, transactionRepo, customerRepo, creditLimitRepo, otpService, cfg)
+	erasureUseCase := usecase.NewErasureUseCase(unitOfWork, erasureCertificateRepo, authSessionRepo, tokenDenylist, cacheStore, locker, cfg)
+	staffUseCase := usecase.NewStaffUseCase(staffUserRepo, staffRecoveryCodeRepo, authSessionRepo, tokenDenylist, cfg)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+	apiClientUseCase := usecase.NewAPIClientUseCase(apiClientRepo, apiUsageRepo, internalredis.NewRedisUsageMeter(redisClient), locker, cfg)
+	dataExportUseCase := usecase.NewDataExportUseCase(dataExportRepo, customerRepo, creditLimitRepo, transactionRepo, erasureCertificateRepo, auditRepo, locker, cfg)
+
+	workers := lifecycle.NewWorkers()
+	workers.Go(dataExportUseCase.RunWorker)
+	// Instances sharing JWT_KEYS_DIR take turns, so each interval produces one new key
+	keySet.SetRotationGuard(func(rotate func() error) error {
+		return usecase.RunExclusive(context.Background(), locker, "jwt_key_rotation", time.Minute, func(context.Context) error { return rotate() })
+	})
+	workers.Go(keySet.RunRotation)
+	workers.Go(apiClientUseCase.RunUsageFlusher)
+
+	rateLimiter := ratelimit.NewFallbackRateLimiter(
+		internalredis.NewRedisRateLimiter(redisClient),
+		cfg.RateLimitFailureMode,
+		func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.RateLimitProbeInterval)
+			defer cancel()
+			return redisClient.Ping(ctx).Err()
+		},
+		cfg.RateLimitProbeInterval,
+	)
+	workers.Go(rateLimiter.RunHealthProbe)
 
-	authUseCase := usecase.NewAuthUseCase(customerRepo, cfg)
-	customerUseCase := usecase.NewCustomerUseCase(customerRepo)
-	creditLimitUseCase := usecase.NewCreditLimitUseCase(creditLimitRepo, customerRepo)
-	transactionUseCase := usecase.NewTransactionUseCase(gormDB, transactionRepo, customerRepo, creditLimitRepo, cacheStore)
+	authPolicy := domain.RateLimitPolicy{Name: "auth", RequestsPerSecond: float64(cfg.AuthRateLimitPerMinute) / 60, Burst: cfg.AuthRateLimitBurst}
+	rateLimit := middleware.RateLimitMiddleware(middleware.RateLimiterConfig{
+		Default: domain.RateLimitPolicy{Name: "default", RequestsPerSecond: float64(cfg.RateLimitPerSecond), Burst: cfg.RateLimitBurst},
+		Routes: map[string]domain.RateLimitPolicy{
+			"POST /api/v1/auth/login":            authPolicy,
+			"POST /api/v1/auth/staff/login":      authPolicy,
+			"POST /api/v1/auth/staff/mfa/verify": authPolicy,
+			"POST /api/v1/auth/password/forgot":  authPolicy,
+			"POST /api/v1/auth/password/reset":   authPolicy,
+			"POST /api/v1/transactions": {
+				Name:              "transaction",
+				RequestsPerSecond: float64(cfg.TxnRateLimitPerMinute) / 60,
+				Burst:             cfg.TxnRateLimitBurst,
+			},
+		},
+	}, rateLimiter)
 
-	apphttp.NewAuthHandler(router, authUseCase)
+	readiness, err := newReadiness(cfg, sqlDB, redisClient)
+	if err != nil {
+		log.Fatalf("Failed to set up readiness checks: %v", err)
+	}
+	apphttp.NewHealthHandler(router, readiness)
+	apphttp.NewJWKSHandler(router, keySet)
+
+	publicV1 := router.Group("/api/v1")
+	// Throttled before the audit trail, so a flood of anonymous requests never reaches the audit chain
+	publicV1.Use(rateLimit, middleware.AuditTrail(auditUseCase), middleware.UsageMetering(apiClientUseCase))
+	{
+		apphttp.NewAuthHandler(publicV1, authUseCase)
+	}
 
 	protectedV1 := router.Group("/api/v1")
 	protectedV1.Use(
-		middleware.JWTAuthMiddleware(cfg),
-		middleware.RateLimitMiddleware(middleware.RateLimiterConfig{
-			RequestsPerSecond: cfg.RateLimitPerSecond,
-			Burst:             cfg.RateLimitBurst,
-		}, redisClient),
+		middleware.AuditTrail(auditUseCase),
+		middleware.UsageMetering(apiClientUseCase),
+		middleware.JWTAuthMiddleware(keySet, tokenDenylist),
+		rateLimit,
 	)
 	{
 		apphttp.NewCustomerHandler(protectedV1, customerUseCase)
 		apphttp.NewCreditLimitHandler(protectedV1, creditLimitUseCase)
 		apphttp.NewTransactionHandler(protectedV1, transactionUseCase)
+		apphttp.NewErasureHandler(protectedV1, erasureUseCase)
+		apphttp.NewDataExportHandler(protectedV1, dataExportUseCase)
+		apphttp.NewStaffHandler(protectedV1, staffUseCase)
+		apphttp.NewSessionHandler(protectedV1, authUseCase)
+		apphttp.NewAuditHandler(protectedV1, auditUseCase)
+		apphttp.NewAPIClientHandler(protectedV1, apiClientUseCase)
 	}
 
-	serverAddress := fmt.Sprintf(":%s", cfg.APIPort)
-	log.Printf("Starting server on %s...", serverAddress)
-	if err := router.Run(serverAddress); err != nil && err != http.ErrServerClosed {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.APIPort),
+		Handler:           router,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	// Metrics are served on their own listener so the public port never exposes them
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+			WriteTimeout:      cfg.HTTPWriteTimeout,
+			IdleTimeout:       cfg.HTTPIdleTimeout,
+		}
+	}
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, 2)
+	go func() {
+		log.Printf("Starting server on %s...", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+	if metricsServer != nil {
+		go func() {
+			log.Printf("Serving metrics on %s", metricsServer.Addr)
+			serverErr <- metricsServer.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Server failed to start: %v", err)
+	case <-signalCtx.Done():
+	}
+	stopSignals() // A second signal terminates right away
+
+	shutdown(cfg, server, metricsServer, readiness, workers, sqlDB, redisClient)
+}
+
+// Readiness probes share one round of checks per readinessCacheFor
+const readinessCacheFor = 2 * time.Second
+
+func newReadiness(cfg *config.Config, sqlDB *sql.DB, redisClient *redis.Client) (*lifecycle.Readiness, error) {
+	checks := []lifecycle.Check{
+		{Name: "mysql", Timeout: time.Second, Run: sqlDB.PingContext},
+		{Name: "redis", Timeout: 500 * time.Millisecond, Run: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+	}
+
+	if cfg.ReadinessMigrations {
+		latest, err := migration.LatestVersion()
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, lifecycle.Check{Name: "migrations", Timeout: time.Second, Run: func(ctx context.Context) error {
+			return database.CheckMigrations(ctx, sqlDB, latest)
+		}})
+	}
+
+	return lifecycle.NewReadiness(readinessCacheFor, checks...), nil
+}
+
+// shutdown stops the instance in dependency order: new traffic first, then in-flight requests, then the
+// background workers, which still write to MySQL and Redis, and finally the connections themselves
+func shutdown(cfg *config.Config, server, metricsServer *http.Server, readiness *lifecycle.Readiness, workers *lifecycle.Workers, sqlDB *sql.DB, redisClient *redis.Client) {
+	log.Printf("Shutting down, reporting not-ready for %s", cfg.ShutdownDelay)
+	readiness.StartDraining()
+	time.Sleep(cfg.ShutdownDelay)
+
+	serverCtx, cancelServer := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelServer()
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Printf("In-flight requests did not finish in time: %v", err)
+		server.Close()
+	}
+	if metricsServer != nil {
+		metricsServer.Close() // Scrapes are short, a cut one is retried on the next interval
+	}
+
+	// Slow requests must not eat into the time the workers need for their last batch
+	workersCtx, cancelWorkers := context.WithTimeout(context.Background(), cfg.WorkerShutdownTimeout)
+	defer cancelWorkers()
+	if err := workers.Stop(workersCtx); err != nil {
+		log.Printf("Background workers did not stop in time: %v", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		log.Printf("Failed to close database pool: %v", err)
+	}
+	if err := redisClient.Close(); err != nil {
+		log.Printf("Failed to close Redis client: %v", err)
 	}
+	log.Printf("Shutdown complete")
 }
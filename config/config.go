@@ -1,28 +1,76 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBUser             string
-	DBPassword         string
-	DBHost             string
-	DBPort             string
-	DBName             string
-	APIPort            string
-	RedisAddr          string
-	JWTSecret          string
-	JWTRefreshSecret   string
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
-	RateLimitPerSecond int
-	RateLimitBurst     int
+	DBUser                  string
+	DBPassword              string
+	DBHost                  string
+	DBPort                  string
+	DBName                  string
+	APIPort                 string
+	TrustedProxies          []string // IPs or CIDRs whose X-Forwarded-For is believed, none by default
+	MetricsAddr             string   // Internal listener for /metrics, off the public API port. Empty disables it.
+	RedisAddr               string
+	JWTSecret               string
+	JWTRefreshSecret        string
+	JWTKeysDir              string
+	JWTAlgorithm            string
+	JWTKeyRotation          time.Duration
+	AccessTokenExpiry       time.Duration
+	RefreshTokenExpiry      time.Duration
+	LoginMaxAttempts        int
+	LoginIPMaxAttempts      int
+	LoginLockoutDuration    time.Duration
+	OTPSecret               string
+	OTPTTL                  time.Duration
+	OTPMaxAttempts          int
+	OTPResendCooldown       time.Duration
+	OTPOutboxFile           string
+	TransactionOTPThreshold float64
+	MFAEncryptionKey        string
+	MFAIssuer               string
+	MFAPendingTokenExpiry   time.Duration
+	RateLimitPerSecond      int
+	RateLimitBurst          int
+	RateLimitFailureMode    string
+	RateLimitProbeInterval  time.Duration
+	AuthRateLimitPerMinute  int
+	AuthRateLimitBurst      int
+	TxnRateLimitPerMinute   int
+	TxnRateLimitBurst       int
+	UsageFlushInterval      time.Duration
+	RequestTimeout          time.Duration
+	RequestTimeoutRoutes    map[string]time.Duration // Keyed by method and route pattern, e.g. "GET /api/v1/admin/audit-logs/verify"
+	HTTPReadTimeout         time.Duration
+	HTTPReadHeaderTimeout   time.Duration
+	HTTPWriteTimeout        time.Duration
+	HTTPIdleTimeout         time.Duration
+	ShutdownDelay           time.Duration
+	ShutdownTimeout         time.Duration
+	WorkerShutdownTimeout   time.Duration
+	ReadinessMigrations     bool // Readiness requires the schema to be at the version of the embedded migrations
+	CacheBackend            string
+	CacheMaxEntries         int
+	CacheMaxBytes           int
+	CacheCompressAbove      int
+	CacheEncryptionKey      string
+	ExportDir               string
+	ExportLinkSecret        string
+	ExportLinkExpiry        time.Duration
+	ExportSweepInterval     time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -42,6 +90,53 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("invalid REFRESH_TOKEN_EXPIRY_DAYS: %w", err)
 	}
 
+	jwtKeyRotationStr := getEnv("JWT_KEY_ROTATION_HOURS", "0")
+	jwtKeyRotationHours, err := strconv.Atoi(jwtKeyRotationStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEY_ROTATION_HOURS: %w", err)
+	}
+
+	loginMaxAttempts, err := strconv.Atoi(getEnv("LOGIN_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_MAX_ATTEMPTS: %w", err)
+	}
+
+	loginIPMaxAttempts, err := strconv.Atoi(getEnv("LOGIN_IP_MAX_ATTEMPTS", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_IP_MAX_ATTEMPTS: %w", err)
+	}
+
+	loginLockoutMinutes, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_MINUTES: %w", err)
+	}
+
+	otpTTLMinutes, err := strconv.Atoi(getEnv("OTP_TTL_MINUTES", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTP_TTL_MINUTES: %w", err)
+	}
+
+	otpMaxAttempts, err := strconv.Atoi(getEnv("OTP_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTP_MAX_ATTEMPTS: %w", err)
+	}
+
+	otpResendCooldownSeconds, err := strconv.Atoi(getEnv("OTP_RESEND_COOLDOWN_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTP_RESEND_COOLDOWN_SECONDS: %w", err)
+	}
+
+	// Zero turns transaction confirmation off
+	transactionOTPThreshold, err := strconv.ParseFloat(getEnv("TRANSACTION_OTP_THRESHOLD", "0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_OTP_THRESHOLD: %w", err)
+	}
+
+	mfaPendingTokenMinutes, err := strconv.Atoi(getEnv("MFA_PENDING_TOKEN_EXPIRY_MINUTES", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MFA_PENDING_TOKEN_EXPIRY_MINUTES: %w", err)
+	}
+
 	rateLimitPerSecondStr := getEnv("RATE_LIMIT_PER_SECOND", "10")
 	rateLimitPerSecond, err := strconv.Atoi(rateLimitPerSecondStr)
 	if err != nil {
@@ -54,20 +149,267 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("invalid RATE_LIMIT_BURST: %w", err)
 	}
 
+	rateLimitProbeSeconds, err := strconv.Atoi(getEnv("RATE_LIMIT_PROBE_INTERVAL_SECONDS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_PROBE_INTERVAL_SECONDS: %w", err)
+	}
+
+	// Stricter buckets for credential endpoints and transaction creation
+	authRateLimitPerMinute, err := strconv.Atoi(getEnv("AUTH_RATE_LIMIT_PER_MINUTE", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_RATE_LIMIT_PER_MINUTE: %w", err)
+	}
+
+	authRateLimitBurst, err := strconv.Atoi(getEnv("AUTH_RATE_LIMIT_BURST", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_RATE_LIMIT_BURST: %w", err)
+	}
+
+	txnRateLimitPerMinute, err := strconv.Atoi(getEnv("TRANSACTION_RATE_LIMIT_PER_MINUTE", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_RATE_LIMIT_PER_MINUTE: %w", err)
+	}
+
+	txnRateLimitBurst, err := strconv.Atoi(getEnv("TRANSACTION_RATE_LIMIT_BURST", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSACTION_RATE_LIMIT_BURST: %w", err)
+	}
+
+	usageFlushSeconds, err := strconv.Atoi(getEnv("USAGE_FLUSH_INTERVAL_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid USAGE_FLUSH_INTERVAL_SECONDS: %w", err)
+	}
+
+	requestTimeoutSeconds, err := strconv.Atoi(getEnv("REQUEST_TIMEOUT_SECONDS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// Routes that need another deadline than REQUEST_TIMEOUT_SECONDS
+	requestTimeoutRoutes, err := parseRouteTimeouts(getEnv("REQUEST_TIMEOUT_ROUTES", "GET /api/v1/admin/audit-logs/verify=120"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT_ROUTES: %w", err)
+	}
+
+	httpReadTimeoutSeconds, err := strconv.Atoi(getEnv("HTTP_READ_TIMEOUT_SECONDS", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// Slow clients trickling headers hold a connection without ever reaching a handler
+	httpReadHeaderTimeoutSeconds, err := strconv.Atoi(getEnv("HTTP_READ_HEADER_TIMEOUT_SECONDS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_HEADER_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// Covers the whole handler, so it has to outlast the longest request deadline
+	httpWriteTimeoutSeconds, err := strconv.Atoi(getEnv("HTTP_WRITE_TIMEOUT_SECONDS", "150"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_WRITE_TIMEOUT_SECONDS: %w", err)
+	}
+
+	httpIdleTimeoutSeconds, err := strconv.Atoi(getEnv("HTTP_IDLE_TIMEOUT_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_IDLE_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// How long the instance reports not-ready before it stops accepting connections
+	shutdownDelaySeconds, err := strconv.Atoi(getEnv("SHUTDOWN_DELAY_SECONDS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_DELAY_SECONDS: %w", err)
+	}
+
+	shutdownTimeoutSeconds, err := strconv.Atoi(getEnv("SHUTDOWN_TIMEOUT_SECONDS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// Workers get their own budget after the server has drained, e.g. for the last usage flush
+	workerShutdownTimeoutSeconds, err := strconv.Atoi(getEnv("WORKER_SHUTDOWN_TIMEOUT_SECONDS", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORKER_SHUTDOWN_TIMEOUT_SECONDS: %w", err)
+	}
+
+	// Turn off where migrations are not applied with golang-migrate, as there is no schema version to read
+	readinessMigrations, err := strconv.ParseBool(getEnv("READINESS_CHECK_MIGRATIONS", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid READINESS_CHECK_MIGRATIONS: %w", err)
+	}
+
+	// Limits of the in-process cache backend
+	cacheMaxEntries, err := strconv.Atoi(getEnv("CACHE_MAX_ENTRIES", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MAX_ENTRIES: %w", err)
+	}
+
+	cacheMaxMB, err := strconv.Atoi(getEnv("CACHE_MAX_MB", "64"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MAX_MB: %w", err)
+	}
+
+	// Zero stores cache payloads uncompressed
+	cacheCompressAbove, err := strconv.Atoi(getEnv("CACHE_COMPRESS_ABOVE_BYTES", "1024"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_COMPRESS_ABOVE_BYTES: %w", err)
+	}
+
+	exportLinkExpiryStr := getEnv("EXPORT_LINK_EXPIRY_HOURS", "24")
+	exportLinkExpiryHours, err := strconv.Atoi(exportLinkExpiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXPORT_LINK_EXPIRY_HOURS: %w", err)
+	}
+
+	// How often pending exports are picked up from the database and expired archives deleted
+	exportSweepSeconds, err := strconv.Atoi(getEnv("EXPORT_SWEEP_INTERVAL_SECONDS", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXPORT_SWEEP_INTERVAL_SECONDS: %w", err)
+	}
+
 	cfg := &Config{
-		DBUser:             getEnv("DB_USER", "root"),
-		DBPassword:         getEnv("DB_PASSWORD", ""),
-		DBHost:             getEnv("DB_HOST", "127.0.0.1"),
-		DBPort:             getEnv("DB_PORT", "3306"),
-		DBName:             getEnv("DB_NAME", "xyz_multifinance"),
-		APIPort:            getEnv("API_PORT", "8080"),
-		RedisAddr:          getEnv("REDIS_ADDR", "localhost:6379"),
-		JWTSecret:          getEnv("JWT_SECRET", "qwoaoiscmoaqoiwdmomocmosmc"),
-		JWTRefreshSecret:   getEnv("JWT_REFRESH_SECRET", "owqopkdfmvzxmcdvcpqpwo"),
-		AccessTokenExpiry:  time.Duration(accessTokenExpiryMinutes) * time.Minute,
-		RefreshTokenExpiry: time.Duration(refreshTokenExpiryDays) * 24 * time.Hour,
-		RateLimitPerSecond: rateLimitPerSecond,
-		RateLimitBurst:     rateLimitBurst,
+		DBUser:                  getEnv("DB_USER", "root"),
+		DBPassword:              getEnv("DB_PASSWORD", ""),
+		DBHost:                  getEnv("DB_HOST", "127.0.0.1"),
+		DBPort:                  getEnv("DB_PORT", "3306"),
+		DBName:                  getEnv("DB_NAME", "xyz_multifinance"),
+		APIPort:                 getEnv("API_PORT", "8080"),
+		TrustedProxies:          parseList(getEnv("TRUSTED_PROXIES", "")),
+		MetricsAddr:             getEnv("METRICS_ADDR", ":9090"),
+		RedisAddr:               getEnv("REDIS_ADDR", "localhost:6379"),
+		JWTSecret:               getEnv("JWT_SECRET", ""),
+		JWTRefreshSecret:        getEnv("JWT_REFRESH_SECRET", ""),
+		JWTKeysDir:              getEnv("JWT_KEYS_DIR", ""),
+		JWTAlgorithm:            getEnv("JWT_ALGORITHM", "EdDSA"),
+		JWTKeyRotation:          time.Duration(jwtKeyRotationHours) * time.Hour,
+		AccessTokenExpiry:       time.Duration(accessTokenExpiryMinutes) * time.Minute,
+		RefreshTokenExpiry:      time.Duration(refreshTokenExpiryDays) * 24 * time.Hour,
+		LoginMaxAttempts:        loginMaxAttempts,
+		LoginIPMaxAttempts:      loginIPMaxAttempts,
+		LoginLockoutDuration:    time.Duration(loginLockoutMinutes) * time.Minute,
+		OTPSecret:               getEnv("OTP_SECRET", ""),
+		OTPTTL:                  time.Duration(otpTTLMinutes) * time.Minute,
+		OTPMaxAttempts:          otpMaxAttempts,
+		OTPResendCooldown:       time.Duration(otpResendCooldownSeconds) * time.Second,
+		OTPOutboxFile:           getEnv("OTP_OUTBOX_FILE", ""),
+		TransactionOTPThreshold: transactionOTPThreshold,
+		MFAEncryptionKey:        getEnv("MFA_ENCRYPTION_KEY", ""),
+		MFAIssuer:               getEnv("MFA_ISSUER", "XYZ Multifinance"),
+		MFAPendingTokenExpiry:   time.Duration(mfaPendingTokenMinutes) * time.Minute,
+		RateLimitPerSecond:      rateLimitPerSecond,
+		RateLimitBurst:          rateLimitBurst,
+		RateLimitFailureMode:    getEnv("RATE_LIMIT_FAILURE_MODE", "local"),
+		RateLimitProbeInterval:  time.Duration(rateLimitProbeSeconds) * time.Second,
+		AuthRateLimitPerMinute:  authRateLimitPerMinute,
+		AuthRateLimitBurst:      authRateLimitBurst,
+		TxnRateLimitPerMinute:   txnRateLimitPerMinute,
+		TxnRateLimitBurst:       txnRateLimitBurst,
+		UsageFlushInterval:      time.Duration(usageFlushSeconds) * time.Second,
+		RequestTimeout:          time.Duration(requestTimeoutSeconds) * time.Second,
+		RequestTimeoutRoutes:    requestTimeoutRoutes,
+		HTTPReadTimeout:         time.Duration(httpReadTimeoutSeconds) * time.Second,
+		HTTPReadHeaderTimeout:   time.Duration(httpReadHeaderTimeoutSeconds) * time.Second,
+		HTTPWriteTimeout:        time.Duration(httpWriteTimeoutSeconds) * time.Second,
+		HTTPIdleTimeout:         time.Duration(httpIdleTimeoutSeconds) * time.Second,
+		ShutdownDelay:           time.Duration(shutdownDelaySeconds) * time.Second,
+		ShutdownTimeout:         time.Duration(shutdownTimeoutSeconds) * time.Second,
+		WorkerShutdownTimeout:   time.Duration(workerShutdownTimeoutSeconds) * time.Second,
+		ReadinessMigrations:     readinessMigrations,
+		CacheBackend:            getEnv("CACHE_BACKEND", "redis"),
+		CacheMaxEntries:         cacheMaxEntries,
+		CacheMaxBytes:           cacheMaxMB * 1024 * 1024,
+		CacheCompressAbove:      cacheCompressAbove,
+		CacheEncryptionKey:      getEnv("CACHE_ENCRYPTION_KEY", ""),
+		ExportDir:               getEnv("EXPORT_DIR", "exports"),
+		ExportLinkSecret:        getEnv("EXPORT_LINK_SECRET", ""),
+		ExportLinkExpiry:        time.Duration(exportLinkExpiryHours) * time.Hour,
+		ExportSweepInterval:     time.Duration(exportSweepSeconds) * time.Second,
+	}
+
+	// Download links are signed with a key derived from the JWT secret when no dedicated one is configured,
+	// so a leaked link key cannot sign access tokens
+	if cfg.ExportLinkSecret == "" && cfg.JWTSecret != "" {
+		cfg.ExportLinkSecret = deriveKey(cfg.JWTSecret, "export-link")
+	}
+
+	// OTP hashes and cached customer data use keys derived from the refresh secret when no dedicated
+	// ones are configured. Rotating the refresh secret then only voids pending codes and cache entries.
+	if cfg.OTPSecret == "" {
+		cfg.OTPSecret = deriveKey(cfg.JWTRefreshSecret, "otp")
+	}
+	if cfg.CacheEncryptionKey == "" {
+		cfg.CacheEncryptionKey = deriveKey(cfg.JWTRefreshSecret, "cache")
+	}
+
+	// Access tokens use the shared secret only when no key directory is configured
+	if cfg.JWTKeysDir == "" && cfg.JWTSecret == "" {
+		return nil, fmt.Errorf("either JWT_KEYS_DIR or JWT_SECRET must be set")
+	}
+	if cfg.JWTAlgorithm != "RS256" && cfg.JWTAlgorithm != "EdDSA" {
+		return nil, fmt.Errorf("invalid JWT_ALGORITHM %q, use RS256 or EdDSA", cfg.JWTAlgorithm)
+	}
+	if cfg.JWTRefreshSecret == "" {
+		return nil, fmt.Errorf("JWT_REFRESH_SECRET must be set")
+	}
+	// TOTP secrets cannot be read back without their key, so it must never change along with another secret
+	if cfg.MFAEncryptionKey == "" {
+		return nil, fmt.Errorf("MFA_ENCRYPTION_KEY must be set")
+	}
+	if cfg.ExportLinkSecret == "" {
+		return nil, fmt.Errorf("EXPORT_LINK_SECRET must be set when JWT_SECRET is not")
+	}
+
+	if cfg.RateLimitFailureMode != "open" && cfg.RateLimitFailureMode != "closed" && cfg.RateLimitFailureMode != "local" {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_FAILURE_MODE %q, use open, closed or local", cfg.RateLimitFailureMode)
+	}
+	if cfg.RateLimitProbeInterval <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_PROBE_INTERVAL_SECONDS must be positive")
+	}
+	if cfg.ExportSweepInterval <= 0 {
+		return nil, fmt.Errorf("EXPORT_SWEEP_INTERVAL_SECONDS must be positive")
+	}
+	if cfg.UsageFlushInterval <= 0 {
+		return nil, fmt.Errorf("USAGE_FLUSH_INTERVAL_SECONDS must be positive")
+	}
+	if cfg.RequestTimeout <= 0 {
+		return nil, fmt.Errorf("REQUEST_TIMEOUT_SECONDS must be positive")
+	}
+	if cfg.HTTPReadTimeout <= 0 || cfg.HTTPReadHeaderTimeout <= 0 || cfg.HTTPWriteTimeout <= 0 || cfg.HTTPIdleTimeout <= 0 {
+		return nil, fmt.Errorf("HTTP_READ_TIMEOUT_SECONDS, HTTP_READ_HEADER_TIMEOUT_SECONDS, HTTP_WRITE_TIMEOUT_SECONDS and HTTP_IDLE_TIMEOUT_SECONDS must be positive")
+	}
+	// A request still running when the write timeout hits gets no response at all, not even its 504
+	for route, timeout := range cfg.RequestTimeoutRoutes {
+		if timeout >= cfg.HTTPWriteTimeout {
+			return nil, fmt.Errorf("HTTP_WRITE_TIMEOUT_SECONDS must be longer than the %s request timeout", route)
+		}
+	}
+	if cfg.RequestTimeout >= cfg.HTTPWriteTimeout {
+		return nil, fmt.Errorf("HTTP_WRITE_TIMEOUT_SECONDS must be longer than REQUEST_TIMEOUT_SECONDS")
+	}
+	if cfg.ShutdownDelay < 0 || cfg.ShutdownTimeout <= 0 || cfg.WorkerShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("SHUTDOWN_DELAY_SECONDS must not be negative, SHUTDOWN_TIMEOUT_SECONDS and WORKER_SHUTDOWN_TIMEOUT_SECONDS must be positive")
+	}
+	if cfg.MetricsAddr != "" {
+		if _, port, err := net.SplitHostPort(cfg.MetricsAddr); err != nil || port == cfg.APIPort {
+			return nil, fmt.Errorf("METRICS_ADDR must be a host:port apart from API_PORT, got %q", cfg.MetricsAddr)
+		}
+	}
+	if cfg.RedisAddr == "" {
+		return nil, fmt.Errorf("REDIS_ADDR must be set, revoked tokens, login lockouts, OTPs, locks, rate limits and API usage are kept in Redis with every CACHE_BACKEND")
+	}
+	if cfg.CacheBackend != "redis" && cfg.CacheBackend != "memory" && cfg.CacheBackend != "none" {
+		return nil, fmt.Errorf("invalid CACHE_BACKEND %q, use redis, memory or none", cfg.CacheBackend)
+	}
+	if cfg.CacheMaxEntries <= 0 || cfg.CacheMaxBytes <= 0 {
+		return nil, fmt.Errorf("CACHE_MAX_ENTRIES and CACHE_MAX_MB must be positive")
+	}
+	if cfg.CacheCompressAbove < 0 {
+		return nil, fmt.Errorf("CACHE_COMPRESS_ABOVE_BYTES must not be negative")
+	}
+
+	// A zero rate would never refill and a zero burst would reject everything
+	for _, limit := range []int{cfg.RateLimitPerSecond, cfg.RateLimitBurst, cfg.AuthRateLimitPerMinute, cfg.AuthRateLimitBurst, cfg.TxnRateLimitPerMinute, cfg.TxnRateLimitBurst} {
+		if limit <= 0 {
+			return nil, fmt.Errorf("rate limits and bursts must be positive")
+		}
 	}
 
 	if cfg.DBUser == "" || cfg.DBPassword == "" || cfg.DBHost == "" || cfg.DBPort == "" || cfg.DBName == "" || cfg.APIPort == "" {
@@ -77,6 +419,48 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// parseRouteTimeouts reads comma-separated "METHOD /route=seconds" entries
+func parseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, secondsStr, ok := strings.Cut(entry, "=")
+		if !ok || len(strings.Fields(route)) != 2 {
+			return nil, fmt.Errorf("entry %q is not METHOD /route=seconds", entry)
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(secondsStr))
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("entry %q needs a positive number of seconds", entry)
+		}
+		timeouts[strings.Join(strings.Fields(route), " ")] = time.Duration(seconds) * time.Second
+	}
+	return timeouts, nil
+}
+
+// deriveKey derives a key for one purpose from secret, so the secret itself is never used for it
+func deriveKey(secret, purpose string) string {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, "xyz-multifinance-api "+purpose, 32)
+	if err != nil {
+		panic(err) // Only fails for lengths SHA-256 cannot produce
+	}
+	return hex.EncodeToString(key)
+}
+
+// parseList reads a comma-separated list, skipping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exist := os.LookupEnv(key); exist {
 		return value
@@ -0,0 +1,12 @@
+USE `xyz_multifinance`;
+
+DROP TABLE IF EXISTS `erasure_certificates`;
+
+ALTER TABLE `transactions`
+DROP INDEX `idx_customer_maturity`,
+DROP COLUMN `maturity_date`,
+DROP COLUMN `tenor_months`;
+
+ALTER TABLE `customers`
+DROP INDEX `idx_customers_erased_at`,
+DROP COLUMN `erased_at`;
@@ -0,0 +1,20 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `customers`
+ADD COLUMN `erased_at` TIMESTAMP NULL AFTER `selfie_photo`,
+ADD INDEX `idx_customers_erased_at` (`erased_at`);
+
+ALTER TABLE `transactions`
+ADD COLUMN `tenor_months` INT NOT NULL DEFAULT 0 AFTER `contract_number`,
+ADD COLUMN `maturity_date` TIMESTAMP NULL AFTER `asset_name`,
+ADD INDEX `idx_customer_maturity` (`customer_id`, `maturity_date`);
+
+CREATE TABLE IF NOT EXISTS `erasure_certificates` (
+  `id` CHAR(36) PRIMARY KEY,
+  `customer_id` CHAR(36) NOT NULL UNIQUE,
+  `subject_digest` CHAR(64) NOT NULL,
+  `erased_fields` VARCHAR(255) NOT NULL,
+  `erased_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`)
+);
@@ -0,0 +1,15 @@
+USE `xyz_multifinance`;
+
+CREATE TABLE IF NOT EXISTS `data_exports` (
+  `id` CHAR(36) PRIMARY KEY,
+  `customer_id` CHAR(36) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `file_path` VARCHAR(255),
+  `error_message` VARCHAR(255),
+  `completed_at` TIMESTAMP NULL,
+  `expires_at` TIMESTAMP NULL,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_data_exports_customer_id` (`customer_id`),
+  FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `customers`
+DROP INDEX `idx_customers_kyc_status`,
+DROP INDEX `idx_customers_salary_id`,
+DROP INDEX `idx_customers_full_name_id`,
+DROP INDEX `idx_customers_created_at_id`,
+DROP COLUMN `kyc_status`;
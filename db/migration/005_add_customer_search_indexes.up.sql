@@ -0,0 +1,11 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `customers`
+ADD COLUMN `kyc_status` VARCHAR(20) NOT NULL DEFAULT 'pending' AFTER `selfie_photo`;
+
+-- Keyset pagination indexes, id breaks ties within each sort column
+ALTER TABLE `customers`
+ADD INDEX `idx_customers_created_at_id` (`created_at`, `id`),
+ADD INDEX `idx_customers_full_name_id` (`full_name`, `id`),
+ADD INDEX `idx_customers_salary_id` (`salary`, `id`),
+ADD INDEX `idx_customers_kyc_status` (`kyc_status`);
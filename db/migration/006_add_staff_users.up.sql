@@ -0,0 +1,16 @@
+USE `xyz_multifinance`;
+
+CREATE TABLE IF NOT EXISTS `staff_users` (
+  `id` CHAR(36) PRIMARY KEY,
+  `username` VARCHAR(50) NOT NULL UNIQUE,
+  `email` VARCHAR(100) NOT NULL UNIQUE,
+  `full_name` VARCHAR(100) NOT NULL,
+  `password` VARCHAR(255) NOT NULL,
+  `role` VARCHAR(30) NOT NULL,
+  `branch_code` VARCHAR(20) NOT NULL,
+  `enabled` BOOLEAN NOT NULL DEFAULT TRUE,
+  `last_login_at` TIMESTAMP NULL,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_staff_users_branch_code` (`branch_code`)
+);
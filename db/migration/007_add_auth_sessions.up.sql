@@ -0,0 +1,15 @@
+USE `xyz_multifinance`;
+
+CREATE TABLE IF NOT EXISTS `auth_sessions` (
+  `id` CHAR(36) PRIMARY KEY,
+  `subject_type` VARCHAR(20) NOT NULL,
+  `subject_id` CHAR(36) NOT NULL,
+  `current_token_id` CHAR(36) NOT NULL,
+  `user_agent` VARCHAR(255) NOT NULL DEFAULT '',
+  `ip_address` VARCHAR(45) NOT NULL DEFAULT '',
+  `expires_at` TIMESTAMP NOT NULL,
+  `revoked_at` TIMESTAMP NULL,
+  `last_refreshed_at` TIMESTAMP NULL,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX `idx_session_subject` (`subject_type`, `subject_id`)
+);
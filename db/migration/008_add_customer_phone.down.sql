@@ -0,0 +1,5 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `customers`
+DROP COLUMN `phone_verified_at`,
+DROP COLUMN `phone_number`;
@@ -0,0 +1,5 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `customers`
+ADD COLUMN `phone_number` VARCHAR(20) NULL AFTER `kyc_status`,
+ADD COLUMN `phone_verified_at` TIMESTAMP NULL AFTER `phone_number`;
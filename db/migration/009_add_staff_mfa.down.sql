@@ -0,0 +1,11 @@
+USE `xyz_multifinance`;
+
+DROP TABLE IF EXISTS `staff_recovery_codes`;
+
+ALTER TABLE `auth_sessions`
+DROP COLUMN `mfa_verified`;
+
+ALTER TABLE `staff_users`
+DROP COLUMN `mfa_last_step`,
+DROP COLUMN `mfa_enabled_at`,
+DROP COLUMN `mfa_secret`;
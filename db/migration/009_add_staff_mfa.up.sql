@@ -0,0 +1,19 @@
+USE `xyz_multifinance`;
+
+ALTER TABLE `staff_users`
+ADD COLUMN `mfa_secret` VARCHAR(255) NOT NULL DEFAULT '' AFTER `enabled`,
+ADD COLUMN `mfa_enabled_at` TIMESTAMP NULL AFTER `mfa_secret`,
+ADD COLUMN `mfa_last_step` BIGINT NOT NULL DEFAULT 0 AFTER `mfa_enabled_at`;
+
+ALTER TABLE `auth_sessions`
+ADD COLUMN `mfa_verified` BOOLEAN NOT NULL DEFAULT FALSE AFTER `ip_address`;
+
+CREATE TABLE IF NOT EXISTS `staff_recovery_codes` (
+  `id` CHAR(36) PRIMARY KEY,
+  `staff_id` CHAR(36) NOT NULL,
+  `code_hash` CHAR(64) NOT NULL,
+  `used_at` TIMESTAMP NULL,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE INDEX `idx_recovery_code_staff_hash` (`staff_id`, `code_hash`),
+  FOREIGN KEY (`staff_id`) REFERENCES `staff_users`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+USE `xyz_multifinance`;
+
+DROP TABLE IF EXISTS `audit_entries`;
@@ -0,0 +1,24 @@
+USE `xyz_multifinance`;
+
+-- Rows are only ever appended. Grant the API user INSERT and SELECT on this table, not UPDATE or DELETE.
+CREATE TABLE IF NOT EXISTS `audit_entries` (
+  `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  `actor_type` VARCHAR(20) NOT NULL,
+  `actor_id` VARCHAR(36) NOT NULL DEFAULT '',
+  `role` VARCHAR(30) NOT NULL DEFAULT '',
+  `ip_address` VARCHAR(45) NOT NULL DEFAULT '',
+  `request_id` VARCHAR(64) NOT NULL DEFAULT '',
+  `action` VARCHAR(100) NOT NULL,
+  `entity_type` VARCHAR(50) NOT NULL DEFAULT '',
+  `entity_id` VARCHAR(100) NOT NULL DEFAULT '',
+  `before` LONGTEXT NULL,
+  `after` LONGTEXT NULL,
+  `result` VARCHAR(20) NOT NULL,
+  `status_code` INT NOT NULL,
+  `created_at` DATETIME(6) NOT NULL,
+  `prev_hash` CHAR(64) NOT NULL,
+  `hash` CHAR(64) NOT NULL,
+  INDEX `idx_audit_actor` (`actor_type`, `actor_id`),
+  INDEX `idx_audit_entity` (`entity_type`, `entity_id`),
+  INDEX `idx_audit_entries_created_at` (`created_at`)
+);
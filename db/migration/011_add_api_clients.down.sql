@@ -0,0 +1,4 @@
+USE `xyz_multifinance`;
+
+DROP TABLE IF EXISTS `api_usage_daily`;
+DROP TABLE IF EXISTS `api_clients`;
@@ -0,0 +1,22 @@
+USE `xyz_multifinance`;
+
+CREATE TABLE IF NOT EXISTS `api_clients` (
+  `id` CHAR(36) PRIMARY KEY,
+  `name` VARCHAR(100) NOT NULL,
+  `key_hash` CHAR(64) NOT NULL UNIQUE,
+  `plan` VARCHAR(20) NOT NULL,
+  `monthly_quota` BIGINT NOT NULL DEFAULT 0,
+  `enabled` BOOLEAN NOT NULL DEFAULT TRUE,
+  `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- One row per client, UTC day and endpoint, filled by the usage flusher
+CREATE TABLE IF NOT EXISTS `api_usage_daily` (
+  `client_id` CHAR(36) NOT NULL,
+  `usage_date` DATE NOT NULL,
+  `endpoint` VARCHAR(150) NOT NULL,
+  `request_count` BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (`client_id`, `usage_date`, `endpoint`),
+  CONSTRAINT `fk_api_usage_client` FOREIGN KEY (`client_id`) REFERENCES `api_clients` (`id`) ON DELETE CASCADE
+);
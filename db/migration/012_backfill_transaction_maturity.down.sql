@@ -0,0 +1,4 @@
+USE `xyz_multifinance`;
+
+-- Backfilled tenors and maturity dates cannot be told apart from recorded ones, so they are kept
+SELECT 1;
@@ -0,0 +1,17 @@
+USE `xyz_multifinance`;
+
+-- Contracts created before 003 have no tenor. Derive it from the installment schedule and round it up to an
+-- offered tenor (1, 2, 3 or 6 months). Contracts without an installment amount get the longest tenor.
+UPDATE `transactions`
+SET `tenor_months` = CASE
+  WHEN `installment_amount` <= 0 THEN 6
+  WHEN CEIL((`otr_amount` + `interest_amount`) / `installment_amount`) <= 1 THEN 1
+  WHEN CEIL((`otr_amount` + `interest_amount`) / `installment_amount`) <= 2 THEN 2
+  WHEN CEIL((`otr_amount` + `interest_amount`) / `installment_amount`) <= 3 THEN 3
+  ELSE 6
+END
+WHERE `tenor_months` = 0;
+
+UPDATE `transactions`
+SET `maturity_date` = DATE_ADD(`created_at`, INTERVAL `tenor_months` MONTH)
+WHERE `maturity_date` IS NULL;
@@ -0,0 +1,8 @@
+USE `xyz_multifinance`;
+
+-- Drained usage buffers already written to api_usage_daily, so a replayed drain is not counted twice
+CREATE TABLE IF NOT EXISTS `api_usage_batches` (
+  `id` CHAR(36) PRIMARY KEY,
+  `flushed_at` TIMESTAMP NOT NULL,
+  INDEX `idx_api_usage_batches_flushed_at` (`flushed_at`)
+);
@@ -0,0 +1,6 @@
+USE `xyz_multifinance`;
+
+-- The genesis entry is only removed while nothing has been chained to it
+DELETE FROM `audit_entries`
+WHERE `action` = 'audit.genesis' AND `prev_hash` = ''
+  AND (SELECT COUNT(*) FROM (SELECT `id` FROM `audit_entries`) AS `chained`) = 1;
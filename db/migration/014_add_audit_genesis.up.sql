@@ -0,0 +1,9 @@
+USE `xyz_multifinance`;
+
+-- Appends lock the chain head. On an empty table the first appends would only take gap locks and could
+-- deadlock, so the chain starts from a fixed genesis entry. Its hash is AuditEntry.ComputeHash of these values.
+INSERT INTO `audit_entries` (`actor_type`, `action`, `result`, `status_code`, `created_at`, `prev_hash`, `hash`)
+SELECT 'system', 'audit.genesis', 'success', 0, '2025-01-01 00:00:00.000000', '',
+  '1740f3e622492f6e1f843d773e6fe92710a01731daa1934cf560fabd7827c5a3'
+FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM `audit_entries`);
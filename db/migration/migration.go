@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// The up migrations are embedded, so a build knows the schema version it needs without the files on disk
+//
+//go:embed *.up.sql
+var upMigrations embed.FS
+
+// LatestVersion returns the number of the newest up migration, e.g. 11 for 011_add_api_clients.up.sql
+func LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(upMigrations, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s does not start with a version number", entry.Name())
+		}
+		latest = max(latest, uint(version))
+	}
+	return latest, nil
+}
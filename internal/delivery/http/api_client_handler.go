@@ -0,0 +1,72 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIClientHandler struct {
+	useCase usecase.APIClientUseCase
+}
+
+func NewAPIClientHandler(router *gin.RouterGroup, apiClientUseCase usecase.APIClientUseCase) {
+	handler := &APIClientHandler{useCase: apiClientUseCase}
+
+	manageAccess := middleware.RequirePermission(domain.PermAPIClientManage)
+
+	router.POST("/admin/api-clients", manageAccess, handler.CreateAPIClient)
+	router.GET("/admin/api-clients/:client_id/usage", manageAccess, handler.GetUsageReport)
+}
+
+func (h *APIClientHandler) CreateAPIClient(ctx *gin.Context) {
+	req := new(model.CreateAPIClientRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	middleware.SetAuditAction(ctx, "api_client.create")
+	clientRes, err := h.useCase.CreateClient(ctx.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	// The snapshot leaves out the API key
+	middleware.SetAuditEntity(ctx, "api_client", clientRes.ID)
+	middleware.SetAuditSnapshots(ctx, nil, clientRes.APIClientResponse)
+	ctx.JSON(http.StatusCreated, clientRes)
+}
+
+func (h *APIClientHandler) GetUsageReport(ctx *gin.Context) {
+	req := new(model.APIUsageReportRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	reportRes, err := h.useCase.GetUsageReport(ctx.Request.Context(), ctx.Param("client_id"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "API client not found"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reportRes)
+}
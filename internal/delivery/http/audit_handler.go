@@ -0,0 +1,55 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	useCase usecase.AuditUseCase
+}
+
+func NewAuditHandler(router *gin.RouterGroup, auditUseCase usecase.AuditUseCase) {
+	handler := &AuditHandler{useCase: auditUseCase}
+
+	readAccess := middleware.RequirePermission(domain.PermAuditRead)
+
+	router.GET("/admin/audit-logs", readAccess, handler.ListAuditLogs)
+	router.GET("/admin/audit-logs/verify", readAccess, handler.VerifyAuditChain)
+}
+
+func (h *AuditHandler) ListAuditLogs(ctx *gin.Context) {
+	req := new(model.ListAuditLogsRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	entriesRes, err := h.useCase.ListEntries(ctx.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entriesRes)
+}
+
+func (h *AuditHandler) VerifyAuditChain(ctx *gin.Context) {
+	verificationRes, err := h.useCase.VerifyChain(ctx.Request.Context())
+	if err != nil {
+		middleware.AbortWithServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verificationRes)
+}
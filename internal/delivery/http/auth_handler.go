@@ -1,10 +1,14 @@
 package http
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
 	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,14 +17,19 @@ type AuthHandler struct {
 	useCase usecase.AuthUseCase
 }
 
-func NewAuthHandler(router *gin.Engine, authUseCase usecase.AuthUseCase) {
+func NewAuthHandler(router *gin.RouterGroup, authUseCase usecase.AuthUseCase) {
 	handler := &AuthHandler{useCase: authUseCase}
 
-	authGroup := router.Group("/api/v1/auth")
+	authGroup := router.Group("/auth")
 	{
 		authGroup.POST("/register", handler.Register)
 		authGroup.POST("/login", handler.Login)
+		authGroup.POST("/staff/login", handler.StaffLogin)
+		authGroup.POST("/staff/mfa/verify", handler.VerifyStaffMFA)
 		authGroup.POST("/refresh", handler.RefreshToken)
+		authGroup.POST("/logout", handler.Logout)
+		authGroup.POST("/password/forgot", handler.ForgotPassword)
+		authGroup.POST("/password/reset", handler.ResetPassword)
 	}
 }
 
@@ -31,14 +40,75 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.useCase.Login(&req)
+	req.UserAgent = ctx.Request.UserAgent()
+	req.IPAddress = ctx.ClientIP()
+
+	res, err := h.useCase.Login(ctx.Request.Context(), &req)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid NIK or password"})
-			return
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "Too many failed login attempts, try again later")
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) StaffLogin(ctx *gin.Context) {
+	var req model.StaffLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	req.UserAgent = ctx.Request.UserAgent()
+	req.IPAddress = ctx.ClientIP()
+
+	res, err := h.useCase.StaffLogin(ctx.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid login or password"})
+		case errors.Is(err, domain.ErrAccountDisabled):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "Too many failed login attempts, try again later")
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) VerifyStaffMFA(ctx *gin.Context) {
+	var req model.StaffMFAVerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	req.UserAgent = ctx.Request.UserAgent()
+	req.IPAddress = ctx.ClientIP()
+
+	res, err := h.useCase.VerifyStaffMFA(ctx.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code or expired MFA token"})
+		case errors.Is(err, domain.ErrAccountDisabled):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "Too many failed verification attempts, try again later")
+		default:
+			middleware.AbortWithServerError(ctx, err)
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		ctx.Error(err)
 		return
 	}
 
@@ -53,7 +123,8 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 
-	customerResp, err := h.useCase.Register(req)
+	middleware.SetAuditAction(ctx, "customer.register")
+	customerResp, err := h.useCase.Register(ctx.Request.Context(), req)
 
 	if err != nil {
 		switch err {
@@ -62,12 +133,13 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 		case domain.ErrAlreadyExists:
 			ctx.JSON(http.StatusConflict, gin.H{"error": "customer with this NIK already exists"})
 		default:
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
 
+	middleware.SetAuditEntity(ctx, "customer", customerResp.ID)
+	middleware.SetAuditSnapshots(ctx, nil, customerResp)
 	ctx.JSON(http.StatusCreated, customerResp)
 }
 
@@ -78,16 +150,85 @@ func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.useCase.RefreshToken(&req)
+	res, err := h.useCase.RefreshToken(ctx.Request.Context(), &req)
 	if err != nil {
-		if err == domain.ErrInvalidInput {
+		if errors.Is(err, domain.ErrInvalidInput) {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		ctx.Error(err)
+		middleware.AbortWithServerError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (h *AuthHandler) Logout(ctx *gin.Context) {
+	var req model.LogoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.useCase.Logout(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+			return
+		}
+		middleware.AbortWithServerError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Login lockouts use the same response whether the account, the IP or a non-existent login is blocked
+func tooManyAttempts(ctx *gin.Context, err error, message string) {
+	var retryErr *domain.RetryAfterError
+	if errors.As(err, &retryErr) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": message})
+}
+
+func (h *AuthHandler) ForgotPassword(ctx *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.useCase.ForgotPassword(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input provided"})
+			return
+		}
+		middleware.AbortWithServerError(ctx, err)
+		return
+	}
+
+	// Same answer whether or not the NIK exists
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "If the NIK belongs to an account with a verified phone number, a reset code has been sent"})
+}
+
+func (h *AuthHandler) ResetPassword(ctx *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.useCase.ResetPassword(ctx.Request.Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired code"})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "Too many wrong codes, request a new one")
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
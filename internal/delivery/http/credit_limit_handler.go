@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
 	"xyz-multifinance-api/internal/domain"
@@ -19,9 +20,11 @@ type CreditLimitHandler struct {
 func NewCreditLimitHandler(router *gin.RouterGroup, creditLimitUseCase usecase.CreditLimitUseCase) {
 	handler := &CreditLimitHandler{useCase: creditLimitUseCase}
 
-	router.POST("/credit-limits", handler.SetCustomerCreditLimit)
-	router.GET("/customers/:customer_id/credit-limits", handler.GetCustomerCreditLimits)
-	router.GET("/customers/:customer_id/credit-limits/:tenor_months", handler.GetCustomerCreditLimitByTenor)
+	readAccess := middleware.RequireCustomerAccess(domain.PermCreditLimitRead, domain.PermCreditLimitReadOwn)
+
+	router.POST("/credit-limits", middleware.RequirePermission(domain.PermCreditLimitWrite), handler.SetCustomerCreditLimit)
+	router.GET("/customers/:customer_id/credit-limits", readAccess, handler.GetCustomerCreditLimits)
+	router.GET("/customers/:customer_id/credit-limits/:tenor_months", readAccess, handler.GetCustomerCreditLimitByTenor)
 }
 
 func (h *CreditLimitHandler) SetCustomerCreditLimit(ctx *gin.Context) {
@@ -31,7 +34,12 @@ func (h *CreditLimitHandler) SetCustomerCreditLimit(ctx *gin.Context) {
 		return
 	}
 
-	creditLimitRes, err := h.useCase.SetCustomerCreditLimit(req)
+	// Entries are filed under the customer so their full limit history is one query
+	middleware.SetAuditAction(ctx, "credit_limit.set")
+	middleware.SetAuditEntity(ctx, "customer", req.CustomerID)
+	before, _ := h.useCase.GetCustomerCreditLimitByTenor(ctx.Request.Context(), req.CustomerID, req.TenorMonths)
+
+	creditLimitRes, err := h.useCase.SetCustomerCreditLimit(ctx.Request.Context(), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidInput):
@@ -40,13 +48,15 @@ func (h *CreditLimitHandler) SetCustomerCreditLimit(ctx *gin.Context) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		case errors.Is(err, domain.ErrAlreadyExists):
 			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrResourceBusy):
+			resourceBusy(ctx, err)
 		default:
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
 
+	middleware.SetAuditSnapshots(ctx, before, creditLimitRes)
 	ctx.JSON(http.StatusCreated, creditLimitRes)
 }
 
@@ -61,13 +71,12 @@ func (h *CreditLimitHandler) GetCustomerCreditLimits(ctx *gin.Context) {
 		customerID = authCustomerID
 	}
 
-	creditLimitsRes, err := h.useCase.GetCustomerCreditLimits(customerID)
+	creditLimitsRes, err := h.useCase.GetCustomerCreditLimits(ctx.Request.Context(), customerID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) { // Customer not found
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -94,16 +103,24 @@ func (h *CreditLimitHandler) GetCustomerCreditLimitByTenor(ctx *gin.Context) {
 		return
 	}
 
-	creditLimitRes, err := h.useCase.GetCustomerCreditLimitByTenor(customerID, tenorMonths)
+	creditLimitRes, err := h.useCase.GetCustomerCreditLimitByTenor(ctx.Request.Context(), customerID, tenorMonths)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
 
 	ctx.JSON(http.StatusOK, creditLimitRes)
 }
+
+// Another request or job is working on the same customer, the client can retry shortly
+func resourceBusy(ctx *gin.Context, err error) {
+	var retryErr *domain.RetryAfterError
+	if errors.As(err, &retryErr) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+	ctx.JSON(http.StatusConflict, gin.H{"error": domain.ErrResourceBusy.Error()})
+}
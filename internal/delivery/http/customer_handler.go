@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
 	"xyz-multifinance-api/internal/usecase"
 	"xyz-multifinance-api/pkg/middleware"
 
@@ -16,8 +18,11 @@ type CustomerHandler struct {
 func NewCustomerHandler(router *gin.RouterGroup, useCase usecase.CustomerUseCase) {
 	handler := &CustomerHandler{useCase: useCase}
 
-	router.GET("/customers/:customer_id", handler.GetCustomerByID)
-	router.GET("/customers/nik/:nik", handler.GetCustomerByNIK)
+	router.GET("/customers/:customer_id", middleware.RequireCustomerAccess(domain.PermCustomerRead, domain.PermCustomerReadOwn), handler.GetCustomerByID)
+	router.GET("/customers/nik/:nik", middleware.RequirePermission(domain.PermCustomerRead, domain.PermCustomerReadOwn), handler.GetCustomerByNIK)
+	router.GET("/admin/customers", middleware.RequirePermission(domain.PermCustomerSearch), handler.SearchCustomers)
+	router.POST("/customers/me/phone", middleware.RequirePermission(domain.PermCustomerUpdateOwn), handler.RequestPhoneVerification)
+	router.POST("/customers/me/phone/verify", middleware.RequirePermission(domain.PermCustomerUpdateOwn), handler.VerifyPhone)
 }
 
 func (h *CustomerHandler) GetCustomerByID(ctx *gin.Context) {
@@ -27,13 +32,12 @@ func (h *CustomerHandler) GetCustomerByID(ctx *gin.Context) {
 		return
 	}
 
-	customerRes, err := h.useCase.GetCustomerProfileByID(id)
+	customerRes, err := h.useCase.GetCustomerProfileByID(ctx.Request.Context(), id)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -48,13 +52,21 @@ func (h *CustomerHandler) GetCustomerByNIK(ctx *gin.Context) {
 		return
 	}
 
-	customerRes, err := h.useCase.GetCustomerProfileByNIK(nik)
+	// Customers may only look up their own NIK
+	if !middleware.HasPermission(ctx, domain.PermCustomerRead) {
+		authNIK, exists := middleware.GetCustomerNIKFromContext(ctx)
+		if !exists || authNIK != nik {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
+	}
+
+	customerRes, err := h.useCase.GetCustomerProfileByNIK(ctx.Request.Context(), nik)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -69,13 +81,93 @@ func (h *CustomerHandler) GetMyCustomerProfile(ctx *gin.Context) {
 		return
 	}
 
-	customerRes, err := h.useCase.GetCustomerProfileByID(customerID)
+	customerRes, err := h.useCase.GetCustomerProfileByID(ctx.Request.Context(), customerID)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found (from token)"})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, customerRes)
+}
+
+func (h *CustomerHandler) SearchCustomers(ctx *gin.Context) {
+	req := new(model.SearchCustomersRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	customersRes, err := h.useCase.SearchCustomers(ctx.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, customersRes)
+}
+
+func (h *CustomerHandler) RequestPhoneVerification(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	req := new(model.RequestPhoneVerificationRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.useCase.RequestPhoneVerification(ctx.Request.Context(), customerID, req); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found (from token)"})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "a code was sent recently, try again later")
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "verification code sent"})
+}
+
+func (h *CustomerHandler) VerifyPhone(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	req := new(model.VerifyPhoneRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	customerRes, err := h.useCase.VerifyPhone(ctx.Request.Context(), customerID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired code"})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "too many wrong codes, request a new one")
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found (from token)"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportHandler struct {
+	useCase usecase.DataExportUseCase
+}
+
+func NewDataExportHandler(router *gin.RouterGroup, dataExportUseCase usecase.DataExportUseCase) {
+	handler := &DataExportHandler{useCase: dataExportUseCase}
+
+	exportAccess := middleware.RequirePermission(domain.PermCustomerExportOwn)
+
+	router.POST("/customers/me/exports", exportAccess, handler.RequestMyExport)
+	router.GET("/customers/me/exports/:export_id", exportAccess, handler.GetMyExport)
+	router.GET("/customers/me/exports/:export_id/download", exportAccess, handler.DownloadMyExport)
+}
+
+func (h *DataExportHandler) RequestMyExport(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	exportRes, err := h.useCase.RequestExport(ctx.Request.Context(), customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found (from token)"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, exportRes)
+}
+
+func (h *DataExportHandler) GetMyExport(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	exportRes, err := h.useCase.GetExport(ctx.Request.Context(), customerID, ctx.Param("export_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "data export not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, exportRes)
+}
+
+func (h *DataExportHandler) DownloadMyExport(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	req := new(model.DataExportDownloadRequest)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid download link", "details": err.Error()})
+		return
+	}
+
+	exportID := ctx.Param("export_id")
+	filePath, err := h.useCase.GetExportArchive(ctx.Request.Context(), customerID, exportID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "invalid download link"})
+		case errors.Is(err, domain.ErrExpired):
+			ctx.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "data export not found"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.FileAttachment(filePath, "data-export-"+exportID+".zip")
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ErasureHandler struct {
+	useCase usecase.ErasureUseCase
+}
+
+func NewErasureHandler(router *gin.RouterGroup, erasureUseCase usecase.ErasureUseCase) {
+	handler := &ErasureHandler{useCase: erasureUseCase}
+
+	router.POST("/customers/me/erasure", middleware.RequirePermission(domain.PermCustomerEraseOwn), handler.EraseMyData)
+	router.GET("/customers/:customer_id/erasure-certificate", middleware.RequireCustomerAccess(domain.PermErasureRead, domain.PermErasureReadOwn), handler.GetErasureCertificate)
+}
+
+func (h *ErasureHandler) EraseMyData(ctx *gin.Context) {
+	customerID, exists := middleware.GetCustomerIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Customer ID not found in token."})
+		return
+	}
+
+	certificateRes, err := h.useCase.EraseCustomerData(ctx.Request.Context(), customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found (from token)"})
+		case errors.Is(err, domain.ErrActiveContracts): // Retention obligation still applies
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrAlreadyErased):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrResourceBusy):
+			resourceBusy(ctx, err)
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, certificateRes)
+}
+
+func (h *ErasureHandler) GetErasureCertificate(ctx *gin.Context) {
+	customerID := ctx.Param("customer_id")
+	if customerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "customer ID is required"})
+		return
+	}
+
+	certificateRes, err := h.useCase.GetErasureCertificate(ctx.Request.Context(), customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "erasure certificate not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, certificateRes)
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+	"xyz-multifinance-api/pkg/lifecycle"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	readiness *lifecycle.Readiness
+}
+
+func NewHealthHandler(router *gin.Engine, readiness *lifecycle.Readiness) {
+	handler := &HealthHandler{readiness: readiness}
+
+	router.GET("/healthz", handler.Live)
+	router.GET("/readyz", handler.Ready)
+}
+
+// Live only reports that the process serves requests. Dependencies are left to Ready, so an outage
+// of MySQL or Redis does not get every instance restarted.
+func (h *HealthHandler) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready turns 503 while a dependency is down and once shutdown has started, so the load balancer
+// stops sending new requests
+func (h *HealthHandler) Ready(ctx *gin.Context) {
+	report := h.readiness.Report(ctx.Request.Context())
+	if !report.Ready() {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"net/http"
+	"xyz-multifinance-api/pkg/jwtkeys"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	keySet *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(router *gin.Engine, keySet *jwtkeys.KeySet) {
+	handler := &JWKSHandler{keySet: keySet}
+
+	router.GET("/.well-known/jwks.json", handler.GetJWKS)
+}
+
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	// Short cache so partners pick up a rotated key well before the old one is retired
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.keySet.JWKS())
+}
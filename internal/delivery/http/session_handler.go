@@ -0,0 +1,61 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	useCase usecase.AuthUseCase
+}
+
+func NewSessionHandler(router *gin.RouterGroup, authUseCase usecase.AuthUseCase) {
+	handler := &SessionHandler{useCase: authUseCase}
+
+	sessionAccess := middleware.RequireCustomerAccess(domain.PermSessionManage, domain.PermSessionManageOwn)
+	router.GET("/customers/:customer_id/sessions", sessionAccess, handler.ListSessions)
+	router.DELETE("/customers/:customer_id/sessions/:session_id", sessionAccess, handler.RevokeSession)
+	router.POST("/admin/customers/:customer_id/unlock", middleware.RequirePermission(domain.PermCustomerUnlock), handler.UnlockLogin)
+}
+
+func (h *SessionHandler) ListSessions(ctx *gin.Context) {
+	sessions, err := h.useCase.ListCustomerSessions(ctx.Request.Context(), ctx.Param("customer_id"))
+	if err != nil {
+		middleware.AbortWithServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) RevokeSession(ctx *gin.Context) {
+	err := h.useCase.RevokeCustomerSession(ctx.Request.Context(), ctx.Param("customer_id"), ctx.Param("session_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *SessionHandler) UnlockLogin(ctx *gin.Context) {
+	if err := h.useCase.UnlockCustomerLogin(ctx.Request.Context(), ctx.Param("customer_id")); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
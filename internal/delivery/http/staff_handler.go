@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StaffHandler struct {
+	useCase usecase.StaffUseCase
+}
+
+func NewStaffHandler(router *gin.RouterGroup, staffUseCase usecase.StaffUseCase) {
+	handler := &StaffHandler{useCase: staffUseCase}
+
+	manageAccess := middleware.RequirePermission(domain.PermStaffManage)
+	mfaAccess := middleware.RequirePermission(domain.PermStaffMFAOwn)
+
+	router.POST("/admin/staff", manageAccess, handler.CreateStaff)
+	router.GET("/admin/staff/:staff_id", manageAccess, handler.GetStaff)
+	router.PATCH("/admin/staff/:staff_id/status", manageAccess, handler.SetStaffStatus)
+	router.DELETE("/admin/staff/:staff_id/mfa", manageAccess, handler.ResetMFA)
+
+	router.POST("/staff/me/mfa/enroll", mfaAccess, handler.EnrollMFA)
+	router.POST("/staff/me/mfa/activate", mfaAccess, handler.ActivateMFA)
+	router.POST("/staff/me/mfa/recovery-codes", mfaAccess, handler.RegenerateRecoveryCodes)
+}
+
+func (h *StaffHandler) CreateStaff(ctx *gin.Context) {
+	req := new(model.CreateStaffRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	middleware.SetAuditAction(ctx, "staff.create")
+	staffRes, err := h.useCase.CreateStaff(ctx.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		case errors.Is(err, domain.ErrAlreadyExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	middleware.SetAuditEntity(ctx, "staff", staffRes.ID)
+	middleware.SetAuditSnapshots(ctx, nil, staffRes)
+	ctx.JSON(http.StatusCreated, staffRes)
+}
+
+func (h *StaffHandler) GetStaff(ctx *gin.Context) {
+	staffRes, err := h.useCase.GetStaff(ctx.Request.Context(), ctx.Param("staff_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "staff user not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, staffRes)
+}
+
+func (h *StaffHandler) SetStaffStatus(ctx *gin.Context) {
+	req := new(model.UpdateStaffStatusRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	middleware.SetAuditAction(ctx, "staff.set_status")
+	before, _ := h.useCase.GetStaff(ctx.Request.Context(), ctx.Param("staff_id"))
+
+	staffRes, err := h.useCase.SetStaffEnabled(ctx.Request.Context(), ctx.Param("staff_id"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "staff user not found"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	middleware.SetAuditSnapshots(ctx, before, staffRes)
+	ctx.JSON(http.StatusOK, staffRes)
+}
+
+func (h *StaffHandler) ResetMFA(ctx *gin.Context) {
+	middleware.SetAuditAction(ctx, "staff.reset_mfa")
+	staffRes, err := h.useCase.ResetMFA(ctx.Request.Context(), ctx.Param("staff_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "staff user not found"})
+		} else {
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, staffRes)
+}
+
+func (h *StaffHandler) EnrollMFA(ctx *gin.Context) {
+	staffID, exists := middleware.GetStaffIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "staff account required"})
+		return
+	}
+
+	enrollment, err := h.useCase.EnrollMFA(ctx.Request.Context(), staffID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAlreadyExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "MFA is already enabled"})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "staff user not found"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+func (h *StaffHandler) ActivateMFA(ctx *gin.Context) {
+	h.handleRecoveryCodes(ctx, h.useCase.ActivateMFA)
+}
+
+func (h *StaffHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	h.handleRecoveryCodes(ctx, h.useCase.RegenerateRecoveryCodes)
+}
+
+// Activation and regeneration both take a current code and answer with new recovery codes
+func (h *StaffHandler) handleRecoveryCodes(ctx *gin.Context, action func(context.Context, string, *model.MFACodeRequest) (*model.RecoveryCodesResponse, error)) {
+	staffID, exists := middleware.GetStaffIDFromContext(ctx)
+	if !exists {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "staff account required"})
+		return
+	}
+
+	req := new(model.MFACodeRequest)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	codesRes, err := action(ctx.Request.Context(), staffID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid input provided", "details": err.Error()})
+		case errors.Is(err, domain.ErrAlreadyExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "MFA is already enabled"})
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "staff user not found"})
+		default:
+			middleware.AbortWithServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, codesRes)
+}
@@ -18,10 +18,10 @@ type TransactionHandler struct {
 func NewTransactionHandler(router *gin.RouterGroup, transactionUseCase usecase.TransactionUseCase) {
 	handler := &TransactionHandler{useCase: transactionUseCase}
 
-	router.POST("/transactions", handler.CreateTransaction)
-	router.GET("/transactions/contract/:contract_number", handler.GetTransactionByContractNumber)
-	router.GET("/customers/:customer_id/transactions", handler.GetTransactionsByCustomerID)
-	router.GET("/customers/me/transactions", handler.GetMyTransactions)
+	router.POST("/transactions", middleware.RequirePermission(domain.PermTransactionCreate, domain.PermTransactionCreateOwn), handler.CreateTransaction)
+	router.GET("/transactions/contract/:contract_number", middleware.RequirePermission(domain.PermTransactionRead, domain.PermTransactionReadOwn), handler.GetTransactionByContractNumber)
+	router.GET("/customers/:customer_id/transactions", middleware.RequireCustomerAccess(domain.PermTransactionRead, domain.PermTransactionReadOwn), handler.GetTransactionsByCustomerID)
+	router.GET("/customers/me/transactions", middleware.RequirePermission(domain.PermTransactionReadOwn), handler.GetMyTransactions)
 }
 
 func (h *TransactionHandler) CreateTransaction(ctx *gin.Context) {
@@ -31,7 +31,15 @@ func (h *TransactionHandler) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
-	transactionRes, err := h.useCase.CreateTransaction(req)
+	if !middleware.CanAccessCustomer(ctx, req.CustomerID, domain.PermTransactionCreate, domain.PermTransactionCreateOwn) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	middleware.SetAuditAction(ctx, "transaction.create")
+	middleware.SetAuditEntity(ctx, "customer", req.CustomerID)
+
+	transactionRes, err := h.useCase.CreateTransaction(ctx.Request.Context(), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidInput):
@@ -42,13 +50,22 @@ func (h *TransactionHandler) CreateTransaction(ctx *gin.Context) {
 			ctx.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()}) // 402 Payment Required
 		case errors.Is(err, domain.ErrAlreadyExists): // Contract number already exist
 			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrAlreadyErased): // Customer data was erased
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrOTPRequired): // Resubmit the same request with otp_code
+			ctx.JSON(http.StatusAccepted, gin.H{"status": "otp_required", "message": "a confirmation code was sent to the customer's phone"})
+		case errors.Is(err, domain.ErrPhoneNotVerified):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrTooManyAttempts):
+			tooManyAttempts(ctx, err, "too many confirmation attempts, try again later")
 		default:
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
 
+	middleware.SetAuditEntity(ctx, "transaction", transactionRes.ContractNumber)
+	middleware.SetAuditSnapshots(ctx, nil, transactionRes)
 	ctx.JSON(http.StatusCreated, transactionRes)
 }
 
@@ -59,17 +76,22 @@ func (h *TransactionHandler) GetTransactionByContractNumber(ctx *gin.Context) {
 		return
 	}
 
-	transactionRes, err := h.useCase.GetTransactionByContractNumber(contractNumber)
+	transactionRes, err := h.useCase.GetTransactionByContractNumber(ctx.Request.Context(), contractNumber)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
 
+	// Someone else's contract is reported as missing so contract numbers cannot be probed
+	if !middleware.CanAccessCustomer(ctx, transactionRes.CustomerID, domain.PermTransactionRead, domain.PermTransactionReadOwn) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, transactionRes)
 }
 
@@ -80,13 +102,12 @@ func (h *TransactionHandler) GetTransactionsByCustomerID(ctx *gin.Context) {
 		return
 	}
 
-	transactionsRes, err := h.useCase.GetTransactionsByCustomerID(customerID)
+	transactionsRes, err := h.useCase.GetTransactionsByCustomerID(ctx.Request.Context(), customerID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) { // Customer does not exist
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -105,13 +126,12 @@ func (h *TransactionHandler) GetMyTransactions(ctx *gin.Context) {
 		return
 	}
 
-	transactionsRes, err := h.useCase.GetTransactionsByCustomerID(customerID)
+	transactionsRes, err := h.useCase.GetTransactionsByCustomerID(ctx.Request.Context(), customerID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "transactions not found for this user"})
 		} else {
-			ctx.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			middleware.AbortWithServerError(ctx, err)
 		}
 		return
 	}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	PlanBasic      = "basic"
+	PlanBusiness   = "business"
+	PlanEnterprise = "enterprise"
+)
+
+// Monthly request quotas of the partner plans, zero means unlimited
+var planMonthlyQuotas = map[string]int64{
+	PlanBasic:      100000,
+	PlanBusiness:   1000000,
+	PlanEnterprise: 0,
+}
+
+func MonthlyQuotaForPlan(plan string) (int64, bool) {
+	quota, ok := planMonthlyQuotas[plan]
+	return quota, ok
+}
+
+// APIClient is a partner integration identified by its API key. Only the SHA-256 of the key is stored.
+type APIClient struct {
+	ID           string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	Name         string    `gorm:"type:varchar(100)" json:"name"`
+	KeyHash      string    `gorm:"unique;type:char(64)" json:"-"`
+	Plan         string    `gorm:"type:varchar(20)" json:"plan"`
+	MonthlyQuota int64     `json:"monthly_quota"` // Copied from the plan at creation, adjustable per contract
+	Enabled      bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// APIUsage is the request count of one client on one endpoint and day (UTC)
+type APIUsage struct {
+	ClientID     string    `gorm:"primaryKey;type:char(36)" json:"client_id"`
+	UsageDate    time.Time `gorm:"primaryKey;type:date" json:"usage_date"`
+	Endpoint     string    `gorm:"primaryKey;type:varchar(150)" json:"endpoint"`
+	RequestCount int64     `json:"request_count"`
+}
+
+func (APIUsage) TableName() string {
+	return "api_usage_daily"
+}
+
+// APIUsageBatch records a drained usage buffer that was stored, so storing it again adds nothing
+type APIUsageBatch struct {
+	ID        string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	FlushedAt time.Time `gorm:"index" json:"flushed_at"`
+}
+
+func (APIUsageBatch) TableName() string {
+	return "api_usage_batches"
+}
+
+type APIClientRepository interface {
+	Create(ctx context.Context, client *APIClient) error
+	FindByID(ctx context.Context, id string) (*APIClient, error)
+	FindByKeyHash(ctx context.Context, keyHash string) (*APIClient, error)
+}
+
+type APIUsageRepository interface {
+	// AddUsage adds the counts onto existing rows, creating missing ones. The counts of a batch are added
+	// once, storing the same batch again does nothing.
+	AddUsage(ctx context.Context, batchID string, usage []APIUsage) error
+	FindByClient(ctx context.Context, clientID string, from, to time.Time) ([]APIUsage, error)
+	// CountSince sums a client's requests from the given day on
+	CountSince(ctx context.Context, clientID string, from time.Time) (int64, error)
+}
+
+// UsageSeedUnknown asks UsageMeter.Consume to report a missing monthly total instead of starting one
+const UsageSeedUnknown int64 = -1
+
+// UsageMeter counts requests in Redis. Counts are moved to MySQL by draining the pending buffer.
+type UsageMeter interface {
+	// Consume counts one request against the monthly quota and returns the month's total.
+	// It returns false without counting when the quota is already used up. Zero quota is unlimited.
+	// When Redis has no total for the month, e.g. after a Redis restart, it starts from seed. Without a seed
+	// (UsageSeedUnknown) it returns ErrNotFound, so the caller can count the month in MySQL and call again.
+	Consume(ctx context.Context, clientID, endpoint string, at time.Time, quota, seed int64) (bool, int64, error)
+	// Drain hands the counts recorded since the last drain to store and forgets them once store succeeds.
+	// A failed store keeps them for the next drain, which hands them over again under the same batch ID.
+	Drain(ctx context.Context, store func(batchID string, usage []APIUsage) error) error
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	AuditResultSuccess = "success"
+	AuditResultDenied  = "denied" // Rejected by authentication or authorization
+	AuditResultFailure = "failure"
+)
+
+// AuditEntry records one mutating request. Each entry's Hash covers its content and the previous entry's hash,
+// so editing or deleting a row breaks the chain from that point on.
+type AuditEntry struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorType  string    `gorm:"type:varchar(20);index:idx_audit_actor" json:"actor_type"` // customer, staff or anonymous
+	ActorID    string    `gorm:"type:varchar(36);index:idx_audit_actor" json:"actor_id,omitempty"`
+	Role       string    `gorm:"type:varchar(30)" json:"role,omitempty"`
+	IPAddress  string    `gorm:"type:varchar(45)" json:"ip_address"`
+	RequestID  string    `gorm:"type:varchar(64)" json:"request_id"`
+	Action     string    `gorm:"type:varchar(100)" json:"action"`
+	EntityType string    `gorm:"type:varchar(50);index:idx_audit_entity" json:"entity_type,omitempty"`
+	EntityID   string    `gorm:"type:varchar(100);index:idx_audit_entity" json:"entity_id,omitempty"`
+	Before     string    `gorm:"type:longtext" json:"before,omitempty"` // JSON snapshot, stored as text so the hashed bytes survive
+	After      string    `gorm:"type:longtext" json:"after,omitempty"`
+	Result     string    `gorm:"type:varchar(20)" json:"result"`
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `gorm:"type:datetime(6);index" json:"created_at"`
+	PrevHash   string    `gorm:"type:char(64)" json:"prev_hash"`
+	Hash       string    `gorm:"type:char(64)" json:"hash"`
+}
+
+// ComputeHash hashes every field except ID and Hash. CreatedAt must already be truncated to the microseconds the column keeps.
+func (e *AuditEntry) ComputeHash() string {
+	content, _ := json.Marshal([]string{
+		e.PrevHash, e.ActorType, e.ActorID, e.Role, e.IPAddress, e.RequestID, e.Action,
+		e.EntityType, e.EntityID, e.Before, e.After, e.Result, strconv.Itoa(e.StatusCode),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   uint64 // Keyset cursor, entries are returned newest first
+	Limit      int
+}
+
+type AuditRepository interface {
+	// Append links the entry to the current chain head and stores it
+	Append(ctx context.Context, entry *AuditEntry) error
+	Find(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+	// FindAfter returns up to limit entries with an ID above afterID in chain order
+	FindAfter(ctx context.Context, afterID uint64, limit int) ([]AuditEntry, error)
+}
+
+// AuditLogger is how the HTTP layer hands finished audit entries to the audit subsystem
+type AuditLogger interface {
+	Record(ctx context.Context, entry *AuditEntry) error
+}
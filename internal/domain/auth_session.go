@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	SubjectTypeCustomer = "customer"
+	SubjectTypeStaff    = "staff"
+)
+
+// AuthSession is one refresh token family. Only the latest refresh token of a session is accepted.
+type AuthSession struct {
+	ID              string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	SubjectType     string     `gorm:"type:varchar(20);index:idx_session_subject" json:"subject_type"`
+	SubjectID       string     `gorm:"type:char(36);index:idx_session_subject" json:"subject_id"`
+	CurrentTokenID  string     `gorm:"type:char(36)" json:"-"`
+	UserAgent       string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress       string     `gorm:"type:varchar(45)" json:"ip_address"`
+	MFAVerified     bool       `gorm:"not null;default:false" json:"mfa_verified"` // Carried over to tokens issued on refresh
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type AuthSessionRepository interface {
+	Create(ctx context.Context, session *AuthSession) error
+	FindByID(ctx context.Context, id string) (*AuthSession, error)
+	// Rotate swaps the current refresh token ID, returning false when currentTokenID is stale or the session is revoked
+	Rotate(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	FindActiveBySubject(ctx context.Context, subjectType, subjectID string, now time.Time) ([]AuthSession, error)
+}
+
+// TokenDenylist holds revoked session IDs until every access token issued for them has expired
+type TokenDenylist interface {
+	Deny(ctx context.Context, sessionID string, ttl time.Duration) error
+	IsDenied(ctx context.Context, sessionID string) (bool, error)
+}
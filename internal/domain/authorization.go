@@ -0,0 +1,82 @@
+package domain
+
+import "slices"
+
+const (
+	RoleCustomer      = "customer"
+	RoleCreditOfficer = "credit_officer"
+	RoleCollector     = "collector"
+	RoleAdmin         = "admin"
+	RoleMerchant      = "merchant"
+)
+
+// Permissions ending in ":own" only apply to resources belonging to the authenticated customer
+const (
+	PermCustomerRead         = "customer:read"
+	PermCustomerReadOwn      = "customer:read:own"
+	PermCustomerUpdateOwn    = "customer:update:own"
+	PermCustomerSearch       = "customer:search"
+	PermCustomerEraseOwn     = "customer:erase:own"
+	PermCustomerExportOwn    = "customer:export:own"
+	PermErasureRead          = "erasure:read"
+	PermErasureReadOwn       = "erasure:read:own"
+	PermCreditLimitRead      = "credit_limit:read"
+	PermCreditLimitReadOwn   = "credit_limit:read:own"
+	PermCreditLimitWrite     = "credit_limit:write"
+	PermTransactionRead      = "transaction:read"
+	PermTransactionReadOwn   = "transaction:read:own"
+	PermTransactionCreate    = "transaction:create"
+	PermTransactionCreateOwn = "transaction:create:own"
+	PermStaffManage          = "staff:manage"
+	PermSessionManage        = "session:manage"
+	PermSessionManageOwn     = "session:manage:own"
+	PermCustomerUnlock       = "customer:unlock"
+	PermStaffMFAOwn          = "staff:mfa:own"
+	PermAuditRead            = "audit:read"
+	PermAPIClientManage      = "api_client:manage"
+)
+
+// Granting any of these requires a session that completed the second factor
+var mfaRequiredPermissions = []string{PermCreditLimitWrite}
+
+var rolePermissions = map[string][]string{
+	RoleCustomer: {
+		PermCustomerReadOwn, PermCustomerUpdateOwn, PermCustomerEraseOwn, PermCustomerExportOwn, PermErasureReadOwn,
+		PermCreditLimitReadOwn, PermTransactionReadOwn, PermTransactionCreateOwn, PermSessionManageOwn,
+	},
+	RoleCreditOfficer: {
+		PermCustomerRead, PermCustomerSearch, PermCustomerUnlock, PermErasureRead,
+		PermCreditLimitRead, PermCreditLimitWrite, PermTransactionRead, PermStaffMFAOwn,
+	},
+	RoleCollector: {
+		PermCustomerRead, PermCustomerSearch, PermCreditLimitRead, PermTransactionRead, PermStaffMFAOwn,
+	},
+	RoleAdmin: {
+		PermCustomerRead, PermCustomerSearch, PermErasureRead,
+		PermCreditLimitRead, PermCreditLimitWrite, PermTransactionRead, PermTransactionCreate,
+		PermStaffManage, PermSessionManage, PermCustomerUnlock, PermStaffMFAOwn, PermAuditRead,
+		PermAPIClientManage,
+	},
+	RoleMerchant: {
+		PermTransactionCreate, PermStaffMFAOwn,
+	},
+}
+
+func PermissionsForRole(role string) []string {
+	permissions := rolePermissions[role]
+	return append([]string(nil), permissions...)
+}
+
+func PermissionRequiresMFA(permission string) bool {
+	return slices.Contains(mfaRequiredPermissions, permission)
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Every role except customer belongs to a staff account
+func IsStaffRole(role string) bool {
+	return role != RoleCustomer && IsValidRole(role)
+}
@@ -1,10 +1,38 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned when a key is not cached. Any other error means the cache itself failed.
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheNoExpiry is the TTL reported for keys that never expire
+const CacheNoExpiry time.Duration = -1
 
 type CacheStore interface {
-	Set(key string, value interface{}, expiration time.Duration) error
-	Get(key string) (string, error)
-	Del(key string) error
+	// Set stores value with the given TTL, zero means no expiry
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// SetWithTags also adds the key to each tag, so InvalidateTag can drop it together with the tag's other keys
+	SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error
+	// SetNX stores value only when the key is not set yet and reports whether it did
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	// MGet returns the cached keys only, misses are left out of the map
+	MGet(ctx context.Context, keys ...string) (map[string]string, error)
+	// Incr adds one to an integer value, starting from zero when the key is not set, and keeps its TTL
+	Incr(ctx context.Context, key string) (int64, error)
+	// TTL returns ErrCacheMiss for unknown keys and CacheNoExpiry for keys without a TTL
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Del(ctx context.Context, keys ...string) error
+	// InvalidateTag deletes every key stored with the tag
+	InvalidateTag(ctx context.Context, tag string) error
 	Close() error
 }
+
+// CustomerCacheTag groups every cached entry that belongs to a customer
+func CustomerCacheTag(customerID string) string {
+	return "customer:" + customerID
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type CreditLimit struct {
 	ID          string    `gorm:"primaryKey;type:char(36)" json:"id"`                              // UUID CHAR(36)
@@ -12,8 +15,10 @@ type CreditLimit struct {
 }
 
 type CreditLimitRepository interface {
-	CreateCreditLimit(creditLimit *CreditLimit) error
-	GetCreditLimitByCustomerAndTenor(customerID string, tenorMonths int) (*CreditLimit, error)
-	UpdateCreditLimit(creditLimit *CreditLimit) error
-	GetCreditLimitsByCustomerID(customerID string) ([]CreditLimit, error)
+	CreateCreditLimit(ctx context.Context, creditLimit *CreditLimit) error
+	GetCreditLimitByCustomerAndTenor(ctx context.Context, customerID string, tenorMonths int) (*CreditLimit, error)
+	// GetCreditLimitForUpdate reads the row from the database and locks it until the surrounding transaction ends
+	GetCreditLimitForUpdate(ctx context.Context, customerID string, tenorMonths int) (*CreditLimit, error)
+	UpdateCreditLimit(ctx context.Context, creditLimit *CreditLimit) error
+	GetCreditLimitsByCustomerID(ctx context.Context, customerID string) ([]CreditLimit, error)
 }
@@ -1,24 +1,69 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+const (
+	KYCStatusPending  = "pending"
+	KYCStatusVerified = "verified"
+	KYCStatusRejected = "rejected"
+)
 
 type Customer struct {
-	ID          string    `gorm:"primaryKey;type:char(36)" json:"id"`
-	NIK         string    `gorm:"unique;type:varchar(16)" json:"nik"`
-	FullName    string    `gorm:"type:varchar(100)" json:"full_name"`
-	Password    string    `gorm:"type:varchar(255)"`
-	LegalName   string    `gorm:"type:varchar(100)" json:"legal_name"`
-	BirthPlace  string    `gorm:"type:varchar(100)" json:"birth_place"`
-	BirthDate   time.Time `gorm:"type:date" json:"birth_date"`
-	Salary      float64   `gorm:"type:decimal(15,2)" json:"salary"`
-	KTPPhoto    string    `gorm:"type:text" json:"ktp_photo_url"`
-	SelfiePhoto string    `gorm:"type:text" json:"selfie_photo_url"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	NIK             string     `gorm:"unique;type:varchar(16)" json:"nik"`
+	FullName        string     `gorm:"type:varchar(100)" json:"full_name"`
+	Password        string     `gorm:"type:varchar(255)"`
+	LegalName       string     `gorm:"type:varchar(100)" json:"legal_name"`
+	BirthPlace      string     `gorm:"type:varchar(100)" json:"birth_place"`
+	BirthDate       time.Time  `gorm:"type:date" json:"birth_date"`
+	Salary          float64    `gorm:"type:decimal(15,2)" json:"salary"`
+	KTPPhoto        string     `gorm:"type:text" json:"ktp_photo_url"`
+	SelfiePhoto     string     `gorm:"type:text" json:"selfie_photo_url"`
+	KYCStatus       string     `gorm:"type:varchar(20);default:pending;index" json:"kyc_status"`
+	PhoneNumber     string     `gorm:"type:varchar(20)" json:"phone_number"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`      // OTPs are only sent to verified numbers
+	ErasedAt        *time.Time `gorm:"index" json:"erased_at,omitempty"` // Set once PII has been pseudonymized
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// Customers are cached without their password hash: FindByID and FindByNIK may return an empty Password.
+// Use FindCredentialsByNIK to check a password and UpdatePassword to change it.
 type CustomerRepository interface {
-	Create(customer *Customer) error
-	FindByID(id string) (*Customer, error)
-	FindByNIK(nik string) (*Customer, error)
+	Create(ctx context.Context, customer *Customer) error
+	FindByID(ctx context.Context, id string) (*Customer, error)
+	// FindByIDForUpdate reads the row from the database and locks it until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id string) (*Customer, error)
+	FindByNIK(ctx context.Context, nik string) (*Customer, error)
+	FindCredentialsByNIK(ctx context.Context, nik string) (*Customer, error)
+	Update(ctx context.Context, customer *Customer) error // Never changes the password
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	Search(ctx context.Context, filter CustomerFilter) ([]Customer, error)
+}
+
+// Keyset position of the last row on the previous page
+type CustomerCursor struct {
+	ID        string
+	CreatedAt time.Time
+	FullName  string
+	Salary    float64
+}
+
+type CustomerFilter struct {
+	Name               string // Partial match on full or legal name
+	NIKPrefix          string
+	RegisteredFrom     *time.Time
+	RegisteredTo       *time.Time
+	MinSalary          *float64
+	MaxSalary          *float64
+	KYCStatus          string
+	HasActiveContracts *bool
+	ActiveAsOf         time.Time
+	SortBy             string // created_at, full_name or salary
+	SortDesc           bool
+	After              *CustomerCursor
+	Limit              int
 }
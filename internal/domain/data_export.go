@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusCompleted  = "completed"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired" // The archive was deleted once its link expired
+)
+
+type DataExport struct {
+	ID           string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	CustomerID   string     `gorm:"type:char(36);index" json:"customer_id"`
+	Status       string     `gorm:"type:varchar(20)" json:"status"`
+	FilePath     string     `gorm:"type:varchar(255)" json:"-"`
+	ErrorMessage string     `gorm:"type:varchar(255)" json:"error_message,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // Download link is refused after this point
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *DataExport) error
+	FindByID(ctx context.Context, id string) (*DataExport, error)
+	Update(ctx context.Context, export *DataExport) error
+	// FindUnfinished returns pending or processing exports last updated before the given time, oldest first
+	FindUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]DataExport, error)
+	// FindExpired returns completed exports whose link expired before the given time
+	FindExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]DataExport, error)
+}
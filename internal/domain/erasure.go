@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ErasureCertificate struct {
+	ID            string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	CustomerID    string    `gorm:"type:char(36);uniqueIndex" json:"customer_id"`
+	SubjectDigest string    `gorm:"type:char(64)" json:"subject_digest"` // SHA-256 of customer ID and original NIK, proves which subject was erased
+	ErasedFields  string    `gorm:"type:varchar(255)" json:"erased_fields"`
+	ErasedAt      time.Time `json:"erased_at"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type ErasureCertificateRepository interface {
+	Create(ctx context.Context, certificate *ErasureCertificate) error
+	FindByCustomerID(ctx context.Context, customerID string) (*ErasureCertificate, error)
+}
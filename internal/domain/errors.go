@@ -1,11 +1,38 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrNotFound            = errors.New("not found")
-	ErrAlreadyExists       = errors.New("already exists")
-	ErrInvalidInput        = errors.New("invalid input")
-	ErrInternalServerError = errors.New("internal server error")
-	ErrInsufficientCredit  = errors.New("insufficient credit")
+	ErrNotFound               = errors.New("not found")
+	ErrAlreadyExists          = errors.New("already exists")
+	ErrInvalidInput           = errors.New("invalid input")
+	ErrInternalServerError    = errors.New("internal server error")
+	ErrInsufficientCredit     = errors.New("insufficient credit")
+	ErrActiveContracts        = errors.New("customer has active contracts")
+	ErrAlreadyErased          = errors.New("customer data already erased")
+	ErrExpired                = errors.New("expired")
+	ErrAccountDisabled        = errors.New("account disabled")
+	ErrTooManyAttempts        = errors.New("too many attempts")
+	ErrOTPRequired            = errors.New("otp confirmation required")
+	ErrPhoneNotVerified       = errors.New("phone number not verified")
+	ErrRateLimiterUnavailable = errors.New("rate limiter unavailable")
+	ErrQuotaExceeded          = errors.New("quota exceeded")
+	ErrResourceBusy           = errors.New("another operation on this resource is in progress")
 )
+
+// RetryAfterError tells the caller how long to wait before trying again
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrLockHeld is returned by TryAcquire when another owner holds the lock
+	ErrLockHeld = errors.New("lock held by another owner")
+	// ErrLockLost is returned by Release when the lease ran out and the lock may have passed to another owner
+	ErrLockLost = errors.New("lock lost")
+)
+
+type Lock interface {
+	// Context is cancelled once the lock is released or its lease could not be renewed. Work done under
+	// the lock should stop when it is, since another owner may hold the lock by then.
+	Context() context.Context
+	Release() error
+}
+
+// Locker hands out locks shared by every API instance. A lock's lease is renewed while it is held,
+// so it only expires when its owner stops renewing it, e.g. after a crash.
+type Locker interface {
+	// Acquire waits until the lock is free or ctx ends. ctx bounds the wait only, not the lock.
+	Acquire(ctx context.Context, key string, lease time.Duration) (Lock, error)
+	TryAcquire(ctx context.Context, key string, lease time.Duration) (Lock, error)
+}
+
+// CustomerLockKey serializes operations on one customer that span more than one database transaction
+func CustomerLockKey(customerID string) string {
+	return "customer:" + customerID
+}
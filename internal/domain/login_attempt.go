@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttemptTracker counts failed logins per key (a NIK, a staff login or a client IP) and holds temporary blocks
+type LoginAttemptTracker interface {
+	// LockedFor returns how long the key is still blocked, zero when attempts are allowed
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// RecordFailure counts a failed attempt, the count resets once window has passed since the first failure
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Lock blocks the key for duration unless it is already blocked for longer
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	Reset(ctx context.Context, key string) error
+}
+
+type CustomerNotifier interface {
+	NotifyLoginLocked(customerID string, lockedUntil time.Time) error
+}
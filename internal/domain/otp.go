@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	OTPPurposePasswordReset           = "password_reset"
+	OTPPurposePhoneVerification       = "phone_verification"
+	OTPPurposeTransactionConfirmation = "transaction_confirmation"
+)
+
+// OTPChallenge is a pending code. Only its hash is stored, Payload is what the code was issued for.
+type OTPChallenge struct {
+	CodeHash string
+	Payload  string
+	Attempts int
+}
+
+// OTPStore keeps at most one pending challenge per purpose and subject
+type OTPStore interface {
+	Save(ctx context.Context, purpose, subject string, challenge *OTPChallenge, ttl time.Duration) error
+	// Consume checks codeHash against the pending challenge in one atomic step. A match deletes the challenge and
+	// returns its payload, so a code works once. A mismatch counts an attempt, and the challenge is deleted once
+	// maxAttempts wrong codes were tried. Returns ErrNotFound without a pending challenge and ErrInvalidInput for a wrong code.
+	Consume(ctx context.Context, purpose, subject, codeHash string, maxAttempts int) (string, error)
+	Delete(ctx context.Context, purpose, subject string) error
+	// StartCooldown returns zero when the cooldown was started, or the time left on the running one
+	StartCooldown(ctx context.Context, purpose, subject string, cooldown time.Duration) (time.Duration, error)
+}
+
+type OTPSender interface {
+	Send(destination, purpose, code string) error
+}
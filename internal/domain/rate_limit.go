@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitPolicy is a token bucket: Burst tokens at most, refilled at RequestsPerSecond
+type RateLimitPolicy struct {
+	Name              string // Part of the bucket key, so each policy counts separately
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // Until the next token, zero when allowed
+	ResetAfter time.Duration // Until the bucket is full again
+}
+
+type RateLimiter interface {
+	// Allow takes one token from the bucket of key under policy
+	Allow(ctx context.Context, key string, policy RateLimitPolicy) (*RateLimitResult, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type StaffUser struct {
+	ID           string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	Username     string     `gorm:"unique;type:varchar(50)" json:"username"`
+	Email        string     `gorm:"unique;type:varchar(100)" json:"email"`
+	FullName     string     `gorm:"type:varchar(100)" json:"full_name"`
+	Password     string     `gorm:"type:varchar(255)" json:"-"`
+	Role         string     `gorm:"type:varchar(30)" json:"role"`
+	BranchCode   string     `gorm:"type:varchar(20);index" json:"branch_code"`
+	Enabled      bool       `gorm:"not null;default:true" json:"enabled"`
+	MFASecret    string     `gorm:"type:varchar(255)" json:"-"` // Encrypted TOTP secret, set at enrollment
+	MFAEnabledAt *time.Time `json:"mfa_enabled_at,omitempty"`
+	MFALastStep  int64      `gorm:"not null;default:0" json:"-"` // Last accepted TOTP time step, older codes are replays
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// StaffRecoveryCode is a single-use fallback for a lost authenticator. Only the SHA-256 of the code is stored.
+type StaffRecoveryCode struct {
+	ID        string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	StaffID   string     `gorm:"type:char(36);uniqueIndex:idx_recovery_code_staff_hash" json:"staff_id"`
+	CodeHash  string     `gorm:"type:char(64);uniqueIndex:idx_recovery_code_staff_hash" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type StaffUserRepository interface {
+	Create(ctx context.Context, staff *StaffUser) error
+	FindByID(ctx context.Context, id string) (*StaffUser, error)
+	FindByLogin(ctx context.Context, login string) (*StaffUser, error) // Matches username or email
+	// The update methods write only the columns their flow owns, so a concurrent change to the other columns is kept
+	// RecordLogin stamps the login time, returning false when the account was disabled or removed meanwhile
+	RecordLogin(ctx context.Context, id string, at time.Time) (bool, error)
+	SetEnabled(ctx context.Context, id string, enabled bool) error
+	// EnrollMFA stores a new encrypted secret, returning false when MFA is already active
+	EnrollMFA(ctx context.Context, id, secret string) (bool, error)
+	// ActivateMFA enables MFA for the given secret, returning false when it was replaced or MFA is already active
+	ActivateMFA(ctx context.Context, id, secret string, at time.Time) (bool, error)
+	ResetMFA(ctx context.Context, id string) error
+	// ClaimMFAStep records a TOTP time step, returning false when it is not newer than the last one used
+	ClaimMFAStep(ctx context.Context, id string, step int64) (bool, error)
+}
+
+type StaffRecoveryCodeRepository interface {
+	// Replace drops every existing code of the staff user and stores the new hashes
+	Replace(ctx context.Context, staffID string, codeHashes []string) error
+	// Consume marks an unused code as used, returning false when no such code exists
+	Consume(ctx context.Context, staffID, codeHash string, usedAt time.Time) (bool, error)
+}
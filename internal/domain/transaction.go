@@ -1,22 +1,28 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Transaction struct {
-	ID                string    `gorm:"primaryKey;type:char(36)" json:"id"`
-	CustomerID        string    `gorm:"type:char(36)" json:"customer_id"`
-	ContractNumber    string    `gorm:"unique;type:varchar(100)" json:"contract_number"`
-	OTRAmount         float64   `gorm:"type:decimal(15,2)" json:"otr_amount"`
-	AdminFee          float64   `gorm:"type:decimal(15,2)" json:"admin_fee"`
-	InstallmentAmount float64   `gorm:"type:decimal(15,2)" json:"installment_amount"`
-	InterestAmount    float64   `gorm:"type:decimal(15,2)" json:"interest_amount"`
-	AssetName         string    `gorm:"type:varchar(100)" json:"asset_name"`
-	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                string     `gorm:"primaryKey;type:char(36)" json:"id"`
+	CustomerID        string     `gorm:"type:char(36);index:idx_customer_maturity" json:"customer_id"`
+	ContractNumber    string     `gorm:"unique;type:varchar(100)" json:"contract_number"`
+	TenorMonths       int        `gorm:"type:int" json:"tenor_months"`
+	OTRAmount         float64    `gorm:"type:decimal(15,2)" json:"otr_amount"`
+	AdminFee          float64    `gorm:"type:decimal(15,2)" json:"admin_fee"`
+	InstallmentAmount float64    `gorm:"type:decimal(15,2)" json:"installment_amount"`
+	InterestAmount    float64    `gorm:"type:decimal(15,2)" json:"interest_amount"`
+	AssetName         string     `gorm:"type:varchar(100)" json:"asset_name"`
+	MaturityDate      *time.Time `gorm:"index:idx_customer_maturity" json:"maturity_date"` // Backfilled for contracts created before tenor tracking
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type TransactionRepository interface {
-	CreateTransaction(transaction *Transaction) error
-	GetTransactionByContractNumber(contractNumber string) (*Transaction, error)
-	GetTransactionsByCustomerID(customerID string) ([]Transaction, error)
+	CreateTransaction(ctx context.Context, transaction *Transaction) error
+	GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*Transaction, error)
+	GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]Transaction, error)
+	CountActiveByCustomerID(ctx context.Context, customerID string, asOf time.Time) (int64, error)
 }
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// TxRepositories are bound to one database transaction
+type TxRepositories interface {
+	Customers() CustomerRepository
+	CreditLimits() CreditLimitRepository
+	Transactions() TransactionRepository
+	ErasureCertificates() ErasureCertificateRepository
+}
+
+type UnitOfWork interface {
+	// Do runs fn in a database transaction, committing when fn returns nil and rolling back otherwise.
+	// Cache changes made through the repositories are applied only once the transaction has committed.
+	Do(ctx context.Context, fn func(repos TxRepositories) error) error
+}
@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/pkg/metrics"
+)
+
+// Stored in place of a value to remember that the row does not exist
+const notFoundMarker = "\x00not_found"
+
+var errLoadPanicked = errors.New("cache load panicked")
+
+// Published under /metrics. Misses count loads from the database, so callers sharing a load count once
+// as a miss and the others as coalesced.
+var cacheRequests = metrics.NewCounterVec("cache_requests_total",
+	"Cache lookups by key prefix and result: hit, negative_hit, miss, coalesced, stale or error.", "prefix", "result")
+
+type AsideConfig struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration // How long a missing row is remembered, zero disables negative caching
+	Jitter      float64       // Spreads expiries by up to this fraction of the TTL, e.g. 0.1 for ±10%
+}
+
+// Aside is a typed cache-aside helper. Concurrent misses on the same key share one load,
+// so a hot key expiring sends a single query to the database.
+type Aside[T any] struct {
+	store  domain.CacheStore
+	name   string
+	config AsideConfig
+	codec  *Codec[T]
+	tags   func(value *T) []string
+	group  callGroup
+}
+
+// NewAside caches values encoded by codec under the tags returned by tags, which may be nil
+func NewAside[T any](store domain.CacheStore, name string, config AsideConfig, codec *Codec[T], tags func(value *T) []string) *Aside[T] {
+	return &Aside[T]{store: store, name: name, config: config, codec: codec, tags: tags}
+}
+
+// Get returns the cached value or calls load and caches its result. A domain.ErrNotFound from load is
+// cached for NegativeTTL. Callers get their own copy of the value and may modify it.
+// Callers that join a load already in flight share the first caller's context, and load on their own
+// when that context ends before the load finishes.
+func (a *Aside[T]) Get(ctx context.Context, key string, load func() (*T, error)) (*T, error) {
+	if value, err, ok := a.lookup(ctx, key); ok {
+		return value, err
+	}
+
+	shared, err, coalesced := a.group.do(key, func() (any, error) {
+		// Another caller may have filled the key while this one waited to load
+		if value, err, ok := a.lookup(ctx, key); ok {
+			return value, err
+		}
+
+		cacheRequests.Inc(keyPrefix(key), "miss")
+		value, err := load()
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) && a.config.NegativeTTL > 0 {
+				a.store.Set(ctx, key, notFoundMarker, a.jitter(a.config.NegativeTTL))
+			}
+			return nil, err
+		}
+		a.Set(ctx, key, value)
+		return value, nil
+	})
+	if coalesced {
+		cacheRequests.Inc(keyPrefix(key), "coalesced")
+		if isContextError(err) && ctx.Err() == nil {
+			value, err := load()
+			if err != nil {
+				return nil, err
+			}
+			a.Set(ctx, key, value)
+			return value, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value := *shared.(*T)
+	return &value, nil
+}
+
+// Set caches value under key, e.g. to fill a second key that identifies the same row. Set and Del follow
+// database writes that already happened, so they go through even when ctx has ended.
+func (a *Aside[T]) Set(ctx context.Context, key string, value *T) {
+	ctx = context.WithoutCancel(ctx)
+	payload, err := a.codec.Marshal(value)
+	if err != nil {
+		log.Printf("Failed to encode %s for cache: %v", a.name, err)
+		return
+	}
+
+	var tags []string
+	if a.tags != nil {
+		tags = a.tags(value)
+	}
+	a.store.SetWithTags(ctx, key, payload, a.jitter(a.config.TTL), tags...)
+}
+
+func (a *Aside[T]) Del(ctx context.Context, keys ...string) {
+	a.store.Del(context.WithoutCancel(ctx), keys...)
+}
+
+// lookup reports ok when the cache answered, either with a value or with a remembered miss
+func (a *Aside[T]) lookup(ctx context.Context, key string) (*T, error, bool) {
+	cached, err := a.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			cacheRequests.Inc(keyPrefix(key), "error") // Cache unavailable, the database still answers
+		}
+		return nil, nil, false
+	}
+	if cached == notFoundMarker {
+		cacheRequests.Inc(keyPrefix(key), "negative_hit")
+		return nil, domain.ErrNotFound, true
+	}
+
+	value, err := a.codec.Unmarshal(cached)
+	if err != nil {
+		if errors.Is(err, errStalePayload) {
+			cacheRequests.Inc(keyPrefix(key), "stale") // Written by another version or key, the refetch overwrites it
+		} else {
+			log.Printf("Failed to decode cached %s %s: %v. Fetching from DB.", a.name, key, err)
+		}
+		return nil, nil, false
+	}
+	cacheRequests.Inc(keyPrefix(key), "hit")
+	return value, nil, true
+}
+
+// keyPrefix returns the part of key before the first colon, e.g. "customer_nik" for "customer_nik:<nik>"
+func keyPrefix(key string) string {
+	prefix, _, _ := strings.Cut(key, ":")
+	return prefix
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (a *Aside[T]) jitter(ttl time.Duration) time.Duration {
+	if a.config.Jitter <= 0 {
+		return ttl
+	}
+	spread := float64(ttl) * a.config.Jitter
+	return ttl + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// callGroup runs one call per key at a time and hands its result to everyone who asked meanwhile.
+// The third result reports whether the caller received another caller's result.
+type callGroup struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done  sync.WaitGroup
+	value any
+	err   error
+}
+
+func (g *callGroup) do(key string, fn func() (any, error)) (any, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.done.Wait()
+		return c.value, c.err, true
+	}
+	c := &call{err: errLoadPanicked} // Replaced unless fn panics
+	c.done.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.done.Done()
+	}()
+	c.value, c.err = fn()
+	return c.value, c.err, false
+}
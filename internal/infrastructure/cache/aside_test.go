@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+type testItem struct {
+	ID   string
+	Name string
+}
+
+func newTestAside(config AsideConfig) (*Aside[testItem], domain.CacheStore) {
+	store := NewMemoryCacheStore(100, 1<<20)
+	identity := func(item *testItem) testItem { return *item }
+	codec := NewCodec(nil, CodecConfig{Version: 1}, identity, identity)
+	return NewAside(store, "item", config, codec, nil), store
+}
+
+func TestAside_Get(t *testing.T) {
+	ctx := context.Background()
+	config := AsideConfig{TTL: time.Hour, NegativeTTL: time.Minute}
+
+	// Test case 1: A miss loads once, later calls are served from the cache with their own copy
+	t.Run("miss_then_hit", func(t *testing.T) {
+		aside, _ := newTestAside(config)
+		var loads int
+		load := func() (*testItem, error) {
+			loads++
+			return &testItem{ID: "1", Name: "first"}, nil
+		}
+
+		first, _ := aside.Get(ctx, "item:1", load)
+		first.Name = "changed by caller"
+		second, err := aside.Get(ctx, "item:1", load)
+
+		if err != nil || second.Name != "first" {
+			t.Fatalf("Expected the cached item unchanged, got %+v, %v", second, err)
+		}
+		if loads != 1 {
+			t.Errorf("Expected 1 load, got %d", loads)
+		}
+	})
+
+	// Test case 2: A missing row is remembered for NegativeTTL
+	t.Run("negative_caching", func(t *testing.T) {
+		aside, _ := newTestAside(config)
+		var loads int
+		load := func() (*testItem, error) {
+			loads++
+			return nil, domain.ErrNotFound
+		}
+
+		for range 3 {
+			if _, err := aside.Get(ctx, "item:missing", load); !errors.Is(err, domain.ErrNotFound) {
+				t.Fatalf("Expected ErrNotFound, got %v", err)
+			}
+		}
+		if loads != 1 {
+			t.Errorf("Expected 1 load, got %d", loads)
+		}
+	})
+
+	// Test case 3: Without NegativeTTL, and for other errors, every call loads again
+	t.Run("errors_not_cached", func(t *testing.T) {
+		aside, _ := newTestAside(AsideConfig{TTL: time.Hour})
+		var loads int
+		notFound := func() (*testItem, error) {
+			loads++
+			return nil, domain.ErrNotFound
+		}
+		failing := func() (*testItem, error) {
+			loads++
+			return nil, errors.New("db error")
+		}
+
+		aside.Get(ctx, "item:missing", notFound)
+		aside.Get(ctx, "item:missing", notFound)
+		aside.Get(ctx, "item:failing", failing)
+		aside.Get(ctx, "item:failing", failing)
+
+		if loads != 4 {
+			t.Errorf("Expected 4 loads, got %d", loads)
+		}
+	})
+
+	// Test case 4: Entries written in another format are reloaded and overwritten
+	t.Run("stale_entry_reloaded", func(t *testing.T) {
+		aside, store := newTestAside(config)
+		store.Set(ctx, "item:1", `{"ID":"1","Name":"legacy"}`, time.Hour)
+
+		item, err := aside.Get(ctx, "item:1", func() (*testItem, error) { return &testItem{ID: "1", Name: "fresh"}, nil })
+
+		if err != nil || item.Name != "fresh" {
+			t.Fatalf("Expected the reloaded item, got %+v, %v", item, err)
+		}
+		if payload, _ := store.Get(ctx, "item:1"); !strings.HasPrefix(payload, "v1:") {
+			t.Errorf("Expected the stale entry to be replaced, got %q", payload)
+		}
+	})
+}
+
+func TestAside_Coalescing(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: Concurrent misses on one key share a single load
+	t.Run("concurrent_misses_share_load", func(t *testing.T) {
+		aside, _ := newTestAside(AsideConfig{TTL: time.Hour})
+		var loads atomic.Int32
+		started, release := make(chan struct{}), make(chan struct{})
+		load := func() (*testItem, error) {
+			if loads.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+			return &testItem{ID: "1", Name: "shared"}, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make(chan *testItem, 10)
+		get := func() {
+			defer wg.Done()
+			item, err := aside.Get(ctx, "item:1", load)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			results <- item
+		}
+		wg.Add(1)
+		go get()
+		<-started
+		for range 9 {
+			wg.Add(1)
+			go get()
+		}
+		time.Sleep(20 * time.Millisecond) // Let the others join the load in flight
+		close(release)
+		wg.Wait()
+		close(results)
+
+		if loads.Load() != 1 {
+			t.Errorf("Expected 1 load, got %d", loads.Load())
+		}
+		for item := range results {
+			if item == nil || item.Name != "shared" {
+				t.Errorf("Expected the shared item, got %+v", item)
+			}
+		}
+	})
+
+	// Test case 2: When the leader's request ends, waiting callers with live requests load on their own
+	t.Run("leader_cancelled", func(t *testing.T) {
+		aside, _ := newTestAside(AsideConfig{TTL: time.Hour})
+		leaderCtx, cancelLeader := context.WithCancel(ctx)
+		started := make(chan struct{})
+
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := aside.Get(leaderCtx, "item:1", func() (*testItem, error) {
+				close(started)
+				<-leaderCtx.Done()
+				return nil, leaderCtx.Err()
+			})
+			leaderErr <- err
+		}()
+		<-started
+
+		followerItem := make(chan *testItem, 1)
+		go func() {
+			item, err := aside.Get(ctx, "item:1", func() (*testItem, error) { return &testItem{ID: "1", Name: "follower"}, nil })
+			if err != nil {
+				t.Errorf("Expected the follower to load on its own, got %v", err)
+			}
+			followerItem <- item
+		}()
+		time.Sleep(20 * time.Millisecond) // Let the follower join the load in flight
+		cancelLeader()
+
+		if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the leader to get its context error, got %v", err)
+		}
+		if item := <-followerItem; item == nil || item.Name != "follower" {
+			t.Errorf("Expected the follower's own item, got %+v", item)
+		}
+	})
+}
+
+func TestAside_Jitter(t *testing.T) {
+	// Test case 1: Expiries are spread within the configured fraction
+	t.Run("within_bounds", func(t *testing.T) {
+		aside, _ := newTestAside(AsideConfig{TTL: time.Hour, Jitter: 0.1})
+		seen := make(map[time.Duration]bool)
+
+		for range 1000 {
+			ttl := aside.jitter(time.Hour)
+			if ttl < 54*time.Minute || ttl > 66*time.Minute {
+				t.Fatalf("Expected a TTL within ±10%% of an hour, got %s", ttl)
+			}
+			seen[ttl] = true
+		}
+		if len(seen) < 100 {
+			t.Errorf("Expected TTLs to be spread, got %d distinct values", len(seen))
+		}
+	})
+
+	// Test case 2: Zero jitter keeps the TTL
+	t.Run("disabled", func(t *testing.T) {
+		aside, _ := newTestAside(AsideConfig{TTL: time.Hour})
+
+		if ttl := aside.jitter(time.Hour); ttl != time.Hour {
+			t.Errorf("Expected exactly one hour, got %s", ttl)
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// keepTTL mirrors redis.KeepTTL: overwrite the value but keep the key's current expiry
+const keepTTL = -1
+
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time // Zero means no expiry
+	tags      []string
+}
+
+func (e *memoryEntry) size() int {
+	return len(e.key) + len(e.value)
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryCacheStore is an in-process LRU cache with per-key TTLs. It is bounded by entry count and by the
+// total size of keys and values, evicting the least recently used entries first. Each instance has its
+// own cache, so invalidations are not seen by other instances.
+type MemoryCacheStore struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // Front is the most recently used
+	tags       map[string]map[string]struct{}
+	bytes      int
+	maxEntries int
+	maxBytes   int
+	now        func() time.Time
+}
+
+func NewMemoryCacheStore(maxEntries, maxBytes int) domain.CacheStore {
+	return &MemoryCacheStore{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		tags:       make(map[string]map[string]struct{}),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		now:        time.Now,
+	}
+}
+
+func (m *MemoryCacheStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return m.SetWithTags(ctx, key, value, expiration)
+}
+
+func (m *MemoryCacheStore) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	encoded, err := formatCacheValue(value)
+	if err != nil {
+		return fmt.Errorf("memory cache set failed: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(key, encoded, expiration, tags)
+	return nil
+}
+
+func (m *MemoryCacheStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	encoded, err := formatCacheValue(value)
+	if err != nil {
+		return false, fmt.Errorf("memory cache setnx failed: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lookup(key) != nil {
+		return false, nil
+	}
+	m.store(key, encoded, expiration, nil)
+	return true, nil
+}
+
+func (m *MemoryCacheStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.lookup(key)
+	if entry == nil {
+		return "", domain.ErrCacheMiss
+	}
+	return entry.value, nil
+}
+
+func (m *MemoryCacheStore) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if entry := m.lookup(key); entry != nil {
+			found[key] = entry.value
+		}
+	}
+	return found, nil
+}
+
+func (m *MemoryCacheStore) Incr(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var current int64
+	if entry := m.lookup(key); entry != nil {
+		parsed, err := strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("memory cache incr failed: value is not an integer")
+		}
+		current = parsed
+	}
+	m.store(key, strconv.FormatInt(current+1, 10), keepTTL, nil)
+	return current + 1, nil
+}
+
+func (m *MemoryCacheStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.lookup(key)
+	if entry == nil {
+		return 0, domain.ErrCacheMiss
+	}
+	if entry.expiresAt.IsZero() {
+		return domain.CacheNoExpiry, nil
+	}
+	return entry.expiresAt.Sub(m.now()), nil
+}
+
+func (m *MemoryCacheStore) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := m.entries[key]; ok {
+			m.remove(element)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryCacheStore) InvalidateTag(ctx context.Context, tag string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.tags[tag] {
+		if element, ok := m.entries[key]; ok {
+			m.remove(element)
+		}
+	}
+	delete(m.tags, tag)
+	return nil
+}
+
+func (m *MemoryCacheStore) Close() error {
+	return nil
+}
+
+// lookup returns the live entry for key and marks it as recently used. Expired entries are dropped.
+func (m *MemoryCacheStore) lookup(key string) *memoryEntry {
+	element, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if entry.expired(m.now()) {
+		m.remove(element)
+		return nil
+	}
+
+	m.order.MoveToFront(element)
+	return entry
+}
+
+func (m *MemoryCacheStore) store(key, value string, expiration time.Duration, tags []string) {
+	now := m.now()
+	entry := &memoryEntry{key: key, value: value, tags: slices.Clone(tags)}
+	if expiration > 0 {
+		entry.expiresAt = now.Add(expiration)
+	}
+
+	if element, ok := m.entries[key]; ok {
+		previous := element.Value.(*memoryEntry)
+		if expiration == keepTTL && !previous.expired(now) {
+			entry.expiresAt = previous.expiresAt
+		}
+		// Like a Redis tag set, a tag keeps its keys until the tag is invalidated
+		for _, tag := range previous.tags {
+			if !slices.Contains(entry.tags, tag) {
+				entry.tags = append(entry.tags, tag)
+			}
+		}
+		m.remove(element)
+	}
+	if entry.size() > m.maxBytes {
+		return // Larger than the whole cache, same as an immediate eviction
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+	m.bytes += entry.size()
+	for _, tag := range entry.tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = make(map[string]struct{})
+		}
+		m.tags[tag][key] = struct{}{}
+	}
+	for len(m.entries) > m.maxEntries || m.bytes > m.maxBytes {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *MemoryCacheStore) remove(element *list.Element) {
+	entry := m.order.Remove(element).(*memoryEntry)
+	delete(m.entries, entry.key)
+	m.bytes -= entry.size()
+	for _, tag := range entry.tags {
+		delete(m.tags[tag], entry.key)
+		if len(m.tags[tag]) == 0 {
+			delete(m.tags, tag)
+		}
+	}
+}
+
+// formatCacheValue turns a value into the string Redis would store for it, so both stores return the same data
+func formatCacheValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", value)
+	}
+}
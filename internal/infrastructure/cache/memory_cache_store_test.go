@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+func TestMemoryCacheStore_InvalidateTag(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: A key overwritten without tags stays under the tags it had, as in a Redis tag set
+	t.Run("overwrite_keeps_previous_tags", func(t *testing.T) {
+		store := NewMemoryCacheStore(10, 1<<20)
+		store.SetWithTags(ctx, "customer:1", "v1", 0, "customer:1")
+		store.Set(ctx, "customer:1", "v2", 0)
+
+		if err := store.InvalidateTag(ctx, "customer:1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := store.Get(ctx, "customer:1"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected cache miss after invalidation, got %v", err)
+		}
+	})
+
+	// Test case 2: Incr on a tagged key keeps its tags
+	t.Run("incr_keeps_previous_tags", func(t *testing.T) {
+		store := NewMemoryCacheStore(10, 1<<20)
+		store.SetWithTags(ctx, "counter", 1, 0, "group")
+		store.Incr(ctx, "counter")
+
+		store.InvalidateTag(ctx, "group")
+		if _, err := store.Get(ctx, "counter"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected cache miss after invalidation, got %v", err)
+		}
+	})
+
+	// Test case 3: Keys under other tags survive
+	t.Run("other_tags_untouched", func(t *testing.T) {
+		store := NewMemoryCacheStore(10, 1<<20)
+		store.SetWithTags(ctx, "a", "1", 0, "x")
+		store.SetWithTags(ctx, "b", "2", 0, "y")
+
+		store.InvalidateTag(ctx, "x")
+		if value, err := store.Get(ctx, "b"); err != nil || value != "2" {
+			t.Errorf("Expected b to survive, got %q, %v", value, err)
+		}
+	})
+}
+
+// newClockedStore returns a store whose clock only moves when advance is called
+func newClockedStore(maxEntries, maxBytes int) (*MemoryCacheStore, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryCacheStore(maxEntries, maxBytes).(*MemoryCacheStore)
+	store.now = func() time.Time { return now }
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryCacheStore_Eviction(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: Past the entry limit the least recently used key goes, reads count as use
+	t.Run("evicts_least_recently_used_entry", func(t *testing.T) {
+		store := NewMemoryCacheStore(2, 1<<20)
+		store.Set(ctx, "a", "1", 0)
+		store.Set(ctx, "b", "2", 0)
+		store.Get(ctx, "a")
+		store.Set(ctx, "c", "3", 0)
+
+		if _, err := store.Get(ctx, "b"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected b to be evicted, got %v", err)
+		}
+		for _, key := range []string{"a", "c"} {
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("Expected %s to be kept, got %v", key, err)
+			}
+		}
+	})
+
+	// Test case 2: Past the byte limit entries go until keys and values fit
+	t.Run("evicts_by_size", func(t *testing.T) {
+		store := NewMemoryCacheStore(100, 10)
+		store.Set(ctx, "k1", "aaa", 0) // 5 bytes
+		store.Set(ctx, "k2", "bbb", 0) // 10 bytes in total
+		store.Set(ctx, "k3", "cc", 0)  // 4 bytes, k1 must go
+
+		if _, err := store.Get(ctx, "k1"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected k1 to be evicted, got %v", err)
+		}
+		if value, _ := store.Get(ctx, "k3"); value != "cc" {
+			t.Errorf("Expected k3 to be stored, got %q", value)
+		}
+		if bytes := store.(*MemoryCacheStore).bytes; bytes != 9 {
+			t.Errorf("Expected 9 bytes in use, got %d", bytes)
+		}
+	})
+
+	// Test case 3: An entry larger than the whole cache is not stored and evicts nothing else
+	t.Run("oversized_entry_not_stored", func(t *testing.T) {
+		store := NewMemoryCacheStore(100, 10)
+		store.Set(ctx, "k1", "aaa", 0)
+		store.Set(ctx, "big", "0123456789", 0)
+
+		if _, err := store.Get(ctx, "big"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected the oversized entry to be dropped, got %v", err)
+		}
+		if _, err := store.Get(ctx, "k1"); err != nil {
+			t.Errorf("Expected k1 to be kept, got %v", err)
+		}
+	})
+
+	// Test case 4: Overwriting a key replaces its size instead of adding to it
+	t.Run("overwrite_accounts_size_once", func(t *testing.T) {
+		store := NewMemoryCacheStore(100, 10)
+		store.Set(ctx, "k1", "aaaa", 0)
+		store.Set(ctx, "k1", "bbbbb", 0) // 7 bytes, not 13
+		store.Set(ctx, "k2", "c", 0)     // 10 bytes in total, nothing to evict
+
+		if value, _ := store.Get(ctx, "k1"); value != "bbbbb" {
+			t.Errorf("Expected k1 to hold the new value, got %q", value)
+		}
+		if bytes := store.(*MemoryCacheStore).bytes; bytes != 10 {
+			t.Errorf("Expected 10 bytes in use, got %d", bytes)
+		}
+	})
+}
+
+func TestMemoryCacheStore_TTL(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: Keys expire after their TTL and free their space
+	t.Run("expires", func(t *testing.T) {
+		store, advance := newClockedStore(10, 1<<20)
+		store.Set(ctx, "k", "v", time.Minute)
+
+		advance(59 * time.Second)
+		if ttl, _ := store.TTL(ctx, "k"); ttl != time.Second {
+			t.Errorf("Expected 1s left, got %s", ttl)
+		}
+
+		advance(time.Second)
+		if _, err := store.Get(ctx, "k"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected the key to expire, got %v", err)
+		}
+		if store.bytes != 0 || len(store.entries) != 0 {
+			t.Errorf("Expected the expired key to be dropped, got %d bytes in %d entries", store.bytes, len(store.entries))
+		}
+	})
+
+	// Test case 2: Keys without TTL report no expiry, missing keys a miss
+	t.Run("no_expiry", func(t *testing.T) {
+		store, advance := newClockedStore(10, 1<<20)
+		store.Set(ctx, "k", "v", 0)
+		advance(24 * time.Hour)
+
+		if ttl, err := store.TTL(ctx, "k"); err != nil || ttl != domain.CacheNoExpiry {
+			t.Errorf("Expected no expiry, got %s, %v", ttl, err)
+		}
+		if _, err := store.TTL(ctx, "missing"); !errors.Is(err, domain.ErrCacheMiss) {
+			t.Errorf("Expected a miss, got %v", err)
+		}
+	})
+
+	// Test case 3: Incr keeps the TTL and starts from zero once the key expired, like Redis
+	t.Run("incr_keeps_ttl", func(t *testing.T) {
+		store, advance := newClockedStore(10, 1<<20)
+		store.Set(ctx, "counter", 1, time.Minute)
+
+		advance(30 * time.Second)
+		if count, _ := store.Incr(ctx, "counter"); count != 2 {
+			t.Fatalf("Expected 2, got %d", count)
+		}
+		if ttl, _ := store.TTL(ctx, "counter"); ttl != 30*time.Second {
+			t.Errorf("Expected the original expiry to be kept, got %s", ttl)
+		}
+
+		advance(30 * time.Second)
+		if count, _ := store.Incr(ctx, "counter"); count != 1 {
+			t.Errorf("Expected the expired counter to restart at 1, got %d", count)
+		}
+	})
+
+	// Test case 4: SetNX succeeds once the previous key expired
+	t.Run("setnx_after_expiry", func(t *testing.T) {
+		store, advance := newClockedStore(10, 1<<20)
+		store.SetNX(ctx, "lock", "a", time.Second)
+
+		if set, _ := store.SetNX(ctx, "lock", "b", time.Second); set {
+			t.Fatal("Expected SetNX to fail while the key is live")
+		}
+		advance(time.Second)
+		if set, _ := store.SetNX(ctx, "lock", "b", time.Second); !set {
+			t.Error("Expected SetNX to succeed after expiry")
+		}
+	})
+}
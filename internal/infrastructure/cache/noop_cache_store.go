@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// NoopCacheStore stores nothing, so every read misses and goes to the database
+type NoopCacheStore struct{}
+
+func NewNoopCacheStore() domain.CacheStore {
+	return NoopCacheStore{}
+}
+
+// Writes still reject values Redis could not store, so a bad value fails the same way with every backend
+func (NoopCacheStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if _, err := formatCacheValue(value); err != nil {
+		return fmt.Errorf("noop cache set failed: %w", err)
+	}
+	return nil
+}
+
+func (s NoopCacheStore) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	return s.Set(ctx, key, value, expiration)
+}
+
+// SetNX always succeeds because no key is ever set
+func (s NoopCacheStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if err := s.Set(ctx, key, value, expiration); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (NoopCacheStore) Get(ctx context.Context, key string) (string, error) {
+	return "", domain.ErrCacheMiss
+}
+
+func (NoopCacheStore) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+// Incr always returns 1 because the previous count was never kept
+func (NoopCacheStore) Incr(ctx context.Context, key string) (int64, error) {
+	return 1, nil
+}
+
+func (NoopCacheStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, domain.ErrCacheMiss
+}
+
+func (NoopCacheStore) Del(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+func (NoopCacheStore) InvalidateTag(ctx context.Context, tag string) error {
+	return nil
+}
+
+func (NoopCacheStore) Close() error {
+	return nil
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Payload flags, written between the version and the body
+const (
+	flagCompressed = 'z'
+	flagEncrypted  = 'e'
+)
+
+// errStalePayload marks entries written in another format. They are dropped and refetched, never decoded.
+var errStalePayload = errors.New("stale cache payload")
+
+type PayloadOptions struct {
+	CompressAbove int    // Bodies larger than this many bytes are gzipped, zero disables compression
+	EncryptionKey string // Sensitive payloads are sealed with AES-GCM under a key derived from it, empty stores them in the clear
+}
+
+// Payloads compresses and encrypts encoded cache entries. It is shared by every entity cache.
+type Payloads struct {
+	compressAbove int
+	aead          cipher.AEAD
+}
+
+func NewPayloads(options PayloadOptions) (*Payloads, error) {
+	payloads := &Payloads{compressAbove: options.CompressAbove}
+	if options.EncryptionKey == "" {
+		return payloads, nil
+	}
+
+	derived := sha256.Sum256([]byte(options.EncryptionKey))
+	block, err := aes.NewCipher(derived[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache cipher: %w", err)
+	}
+	payloads.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache cipher: %w", err)
+	}
+	return payloads, nil
+}
+
+type CodecConfig struct {
+	Version   int  // Bump whenever the cached representation changes, entries with another version are refetched
+	Sensitive bool // Payloads hold PII and are encrypted
+}
+
+// Codec stores values of T through a dedicated cached representation, so fields are cached only when the
+// representation has them. Entries are written as "v<version>:<flags>:<body>".
+type Codec[T any] struct {
+	payloads *Payloads
+	config   CodecConfig
+	encode   func(value *T) ([]byte, error)
+	decode   func(body []byte) (*T, error)
+}
+
+// NewCodec caches T as the representation D returned by toCache. A nil payloads writes plain bodies.
+func NewCodec[T, D any](payloads *Payloads, config CodecConfig, toCache func(value *T) D, fromCache func(entry *D) T) *Codec[T] {
+	if payloads == nil {
+		payloads = &Payloads{}
+	}
+	return &Codec[T]{
+		payloads: payloads,
+		config:   config,
+		encode: func(value *T) ([]byte, error) {
+			return json.Marshal(toCache(value))
+		},
+		decode: func(body []byte) (*T, error) {
+			entry := new(D)
+			if err := json.Unmarshal(body, entry); err != nil {
+				return nil, err
+			}
+			value := fromCache(entry)
+			return &value, nil
+		},
+	}
+}
+
+func (c *Codec[T]) Marshal(value *T) (string, error) {
+	body, err := c.encode(value)
+	if err != nil {
+		return "", err
+	}
+
+	var flags []byte
+	if c.payloads.compressAbove > 0 && len(body) > c.payloads.compressAbove {
+		if body, err = gzipBody(body); err != nil {
+			return "", err
+		}
+		flags = append(flags, flagCompressed)
+	}
+	if c.config.Sensitive && c.payloads.aead != nil {
+		nonce := make([]byte, c.payloads.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		body = c.payloads.aead.Seal(nonce, nonce, body, c.header(flags))
+		flags = append(flags, flagEncrypted)
+	}
+
+	return string(c.header(flags)) + string(body), nil
+}
+
+// Unmarshal returns errStalePayload for entries this codec should not trust: another version, plaintext
+// where encryption is expected, or a body sealed under another key
+func (c *Codec[T]) Unmarshal(payload string) (*T, error) {
+	version, rest, ok := strings.Cut(payload, ":")
+	if !ok || version != "v"+strconv.Itoa(c.config.Version) {
+		return nil, errStalePayload
+	}
+	flags, body, ok := strings.Cut(rest, ":")
+	if !ok {
+		return nil, errStalePayload
+	}
+
+	data := []byte(body)
+	encrypted := strings.IndexByte(flags, flagEncrypted) >= 0
+	if c.config.Sensitive && c.payloads.aead != nil && !encrypted {
+		return nil, errStalePayload
+	}
+	if encrypted {
+		if c.payloads.aead == nil || len(data) < c.payloads.aead.NonceSize() {
+			return nil, errStalePayload
+		}
+		// The header is authenticated, so flags cannot be stripped from a sealed body
+		unsealedFlags := strings.ReplaceAll(flags, string(flagEncrypted), "")
+		nonceSize := c.payloads.aead.NonceSize()
+		opened, err := c.payloads.aead.Open(nil, data[:nonceSize], data[nonceSize:], c.header([]byte(unsealedFlags)))
+		if err != nil {
+			return nil, errStalePayload
+		}
+		data = opened
+	}
+	if strings.IndexByte(flags, flagCompressed) >= 0 {
+		unzipped, err := gunzipBody(data)
+		if err != nil {
+			return nil, err
+		}
+		data = unzipped
+	}
+
+	return c.decode(data)
+}
+
+func (c *Codec[T]) header(flags []byte) []byte {
+	return []byte("v" + strconv.Itoa(c.config.Version) + ":" + string(flags) + ":")
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress cache payload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress cache payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBody(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress cache payload: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
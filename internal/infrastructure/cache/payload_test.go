@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testAccount struct {
+	ID       string
+	NIK      string
+	Password string
+	Notes    string
+}
+
+// cachedAccount leaves the password out, as the repositories do for credentials
+type cachedAccount struct {
+	ID    string `json:"id"`
+	NIK   string `json:"nik"`
+	Notes string `json:"notes"`
+}
+
+func newTestCodec(t *testing.T, options PayloadOptions, config CodecConfig) *Codec[testAccount] {
+	t.Helper()
+	payloads, err := NewPayloads(options)
+	if err != nil {
+		t.Fatalf("Failed to create cache payloads: %v", err)
+	}
+	return NewCodec(payloads, config,
+		func(account *testAccount) cachedAccount {
+			return cachedAccount{ID: account.ID, NIK: account.NIK, Notes: account.Notes}
+		},
+		func(entry *cachedAccount) testAccount {
+			return testAccount{ID: entry.ID, NIK: entry.NIK, Notes: entry.Notes}
+		},
+	)
+}
+
+var testAccountValue = testAccount{ID: "account-1", NIK: "1111111111111301", Password: "$2a$10$storedpasswordhash", Notes: "short"}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	long := testAccountValue
+	long.Notes = strings.Repeat("note ", 50)
+
+	tests := []struct {
+		name    string
+		options PayloadOptions
+		config  CodecConfig
+		value   testAccount
+		header  string
+	}{
+		{
+			name:   "plain",
+			config: CodecConfig{Version: 1},
+			value:  testAccountValue,
+			header: "v1::",
+		},
+		{
+			name:    "sensitive_encrypted",
+			options: PayloadOptions{EncryptionKey: "test-cache-key"},
+			config:  CodecConfig{Version: 1, Sensitive: true},
+			value:   testAccountValue,
+			header:  "v1:e:",
+		},
+		{
+			name:    "not_sensitive_stays_plain",
+			options: PayloadOptions{EncryptionKey: "test-cache-key"},
+			config:  CodecConfig{Version: 1},
+			value:   testAccountValue,
+			header:  "v1::",
+		},
+		{
+			name:    "large_compressed",
+			options: PayloadOptions{CompressAbove: 64},
+			config:  CodecConfig{Version: 2},
+			value:   long,
+			header:  "v2:z:",
+		},
+		{
+			name:    "large_compressed_and_encrypted",
+			options: PayloadOptions{CompressAbove: 64, EncryptionKey: "test-cache-key"},
+			config:  CodecConfig{Version: 1, Sensitive: true},
+			value:   long,
+			header:  "v1:ze:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec := newTestCodec(t, tt.options, tt.config)
+
+			payload, err := codec.Marshal(&tt.value)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !strings.HasPrefix(payload, tt.header) {
+				t.Errorf("Expected a payload starting with %q, got %q", tt.header, payload[:min(len(payload), 16)])
+			}
+			if strings.Contains(payload, tt.value.Password) {
+				t.Error("Expected the password to be left out of the payload")
+			}
+			if tt.config.Sensitive && strings.Contains(payload, tt.value.NIK) {
+				t.Error("Expected no plaintext NIK in a sensitive payload")
+			}
+
+			decoded, err := codec.Unmarshal(payload)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			want := tt.value
+			want.Password = ""
+			if *decoded != want {
+				t.Errorf("Expected %+v, got %+v", want, *decoded)
+			}
+		})
+	}
+}
+
+func TestCodec_StalePayloads(t *testing.T) {
+	sensitive := CodecConfig{Version: 1, Sensitive: true}
+	keyed := PayloadOptions{CompressAbove: 64, EncryptionKey: "test-cache-key"}
+	long := testAccountValue
+	long.Notes = strings.Repeat("note ", 50)
+
+	marshal := func(options PayloadOptions, config CodecConfig, value testAccount) string {
+		payload, err := newTestCodec(t, options, config).Marshal(&value)
+		if err != nil {
+			t.Fatalf("Failed to marshal: %v", err)
+		}
+		return payload
+	}
+	sealed := marshal(keyed, sensitive, testAccountValue)             // v1:e:
+	sealedCompressed := marshal(keyed, sensitive, long)               // v1:ze:
+	plain := marshal(PayloadOptions{}, CodecConfig{Version: 1}, long) // v1::
+
+	tests := []struct {
+		name    string
+		options PayloadOptions
+		config  CodecConfig
+		payload string
+	}{
+		{
+			name:    "version_mismatch",
+			options: keyed,
+			config:  CodecConfig{Version: 2, Sensitive: true},
+			payload: sealed,
+		},
+		{
+			name:    "legacy_json_without_header",
+			config:  CodecConfig{Version: 1},
+			payload: `{"id":"account-1","nik":"1111111111111301"}`,
+		},
+		{
+			name:    "missing_flags",
+			config:  CodecConfig{Version: 1},
+			payload: "v1:{}",
+		},
+		{
+			name:    "plaintext_where_encryption_expected",
+			options: keyed,
+			config:  sensitive,
+			payload: plain,
+		},
+		{
+			name:    "encryption_flag_stripped",
+			options: keyed,
+			config:  sensitive,
+			payload: "v1::" + strings.TrimPrefix(sealed, "v1:e:"),
+		},
+		{
+			name:    "compression_flag_stripped",
+			options: keyed,
+			config:  sensitive,
+			payload: "v1:e:" + strings.TrimPrefix(sealedCompressed, "v1:ze:"),
+		},
+		{
+			name:    "compression_flag_added",
+			options: keyed,
+			config:  sensitive,
+			payload: "v1:ze:" + strings.TrimPrefix(sealed, "v1:e:"),
+		},
+		{
+			name:    "wrong_key",
+			options: PayloadOptions{CompressAbove: 64, EncryptionKey: "another-cache-key"},
+			config:  sensitive,
+			payload: sealed,
+		},
+		{
+			name:    "encrypted_without_key",
+			config:  CodecConfig{Version: 1},
+			payload: sealed,
+		},
+		{
+			name:    "truncated_body",
+			options: keyed,
+			config:  sensitive,
+			payload: "v1:e:short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := newTestCodec(t, tt.options, tt.config).Unmarshal(tt.payload)
+
+			if !errors.Is(err, errStalePayload) {
+				t.Errorf("Expected errStalePayload, got %+v, %v", decoded, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// bufferedOp is a cache write held back until the database transaction commits
+type bufferedOp struct {
+	apply func(ctx context.Context, store domain.CacheStore) error
+	// Keys whose cached value the write changes, and what readers inside the transaction see for them.
+	// A nil value means the key reads as missing.
+	keys  []string
+	value *string
+	tag   string // Set for tag invalidations
+}
+
+// TxBuffer wraps a CacheStore for the duration of one database transaction. Writes and invalidations
+// are queued and reach the store only through Commit, after the transaction has committed. Discard
+// drops them after a rollback, so the cache never holds data the database does not.
+//
+// Reads inside the transaction see the queued writes. SetNX and Incr go straight to the store because
+// their result is needed immediately; do not use them for data that must follow the transaction.
+type TxBuffer struct {
+	store domain.CacheStore
+	mu    sync.Mutex
+	ops   []bufferedOp
+	done  bool
+}
+
+func NewTxBuffer(store domain.CacheStore) *TxBuffer {
+	return &TxBuffer{store: store}
+}
+
+func (b *TxBuffer) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return b.SetWithTags(ctx, key, value, expiration)
+}
+
+func (b *TxBuffer) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	encoded, err := formatCacheValue(value)
+	if err != nil {
+		return fmt.Errorf("buffered cache set failed: %w", err)
+	}
+
+	return b.queue(bufferedOp{
+		apply: func(ctx context.Context, store domain.CacheStore) error {
+			return store.SetWithTags(ctx, key, encoded, expiration, tags...)
+		},
+		keys:  []string{key},
+		value: &encoded,
+	})
+}
+
+func (b *TxBuffer) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return b.store.SetNX(ctx, key, value, expiration)
+}
+
+func (b *TxBuffer) Get(ctx context.Context, key string) (string, error) {
+	b.mu.Lock()
+	value, buffered, invalidated := b.pending(key)
+	b.mu.Unlock()
+
+	switch {
+	case invalidated || (buffered && value == nil):
+		return "", domain.ErrCacheMiss
+	case buffered:
+		return *value, nil
+	}
+	return b.store.Get(ctx, key)
+}
+
+func (b *TxBuffer) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	found := make(map[string]string, len(keys))
+	var unbuffered []string
+
+	b.mu.Lock()
+	for _, key := range keys {
+		value, buffered, invalidated := b.pending(key)
+		switch {
+		case invalidated || (buffered && value == nil):
+		case buffered:
+			found[key] = *value
+		default:
+			unbuffered = append(unbuffered, key)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(unbuffered) == 0 {
+		return found, nil
+	}
+	stored, err := b.store.MGet(ctx, unbuffered...)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range stored {
+		found[key] = value
+	}
+	return found, nil
+}
+
+func (b *TxBuffer) Incr(ctx context.Context, key string) (int64, error) {
+	return b.store.Incr(ctx, key)
+}
+
+func (b *TxBuffer) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return b.store.TTL(ctx, key)
+}
+
+func (b *TxBuffer) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return b.queue(bufferedOp{
+		apply: func(ctx context.Context, store domain.CacheStore) error {
+			return store.Del(ctx, keys...)
+		},
+		keys: keys,
+	})
+}
+
+func (b *TxBuffer) InvalidateTag(ctx context.Context, tag string) error {
+	return b.queue(bufferedOp{
+		apply: func(ctx context.Context, store domain.CacheStore) error {
+			return store.InvalidateTag(ctx, tag)
+		},
+		tag: tag,
+	})
+}
+
+// Close does nothing, the wrapped store outlives the transaction
+func (b *TxBuffer) Close() error {
+	return nil
+}
+
+// Commit applies the queued operations in order. Call it once the database transaction has committed.
+// A failed operation is logged and the rest still run; the cached data then expires with its TTL.
+func (b *TxBuffer) Commit(ctx context.Context) {
+	b.mu.Lock()
+	ops := b.ops
+	b.ops, b.done = nil, true
+	b.mu.Unlock()
+
+	for _, op := range ops {
+		if err := op.apply(ctx, b.store); err != nil {
+			log.Printf("Failed to apply cache change after commit: %v", err)
+		}
+	}
+}
+
+// Discard drops the queued operations. Call it when the database transaction rolled back.
+func (b *TxBuffer) Discard() {
+	b.mu.Lock()
+	b.ops, b.done = nil, true
+	b.mu.Unlock()
+}
+
+func (b *TxBuffer) queue(op bufferedOp) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return errors.New("cache buffer used after its transaction ended")
+	}
+	b.ops = append(b.ops, op)
+	return nil
+}
+
+// pending returns the newest queued value for key. invalidated reports a tag invalidation queued after it,
+// which may cover the key; telling would take a round trip for the tag's members, so it reads as missing.
+func (b *TxBuffer) pending(key string) (value *string, buffered bool, invalidated bool) {
+	for i := len(b.ops) - 1; i >= 0; i-- {
+		op := b.ops[i]
+		if op.tag != "" {
+			return nil, false, true
+		}
+		if slices.Contains(op.keys, key) {
+			return op.value, true, false
+		}
+	}
+	return nil, false, false
+}
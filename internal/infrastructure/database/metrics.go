@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+	"xyz-multifinance-api/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+var queryDuration = metrics.NewHistogramVec("db_query_duration_seconds",
+	"GORM query latency by operation and table.",
+	[]float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	"operation", "table")
+
+// QueryMetrics is a GORM plugin timing every statement from the first callback to the last,
+// so hooks such as BeforeCreate count towards the query they run for
+type QueryMetrics struct{}
+
+func (QueryMetrics) Name() string {
+	return "metrics"
+}
+
+func (QueryMetrics) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	register := func(operation string, before, after func(name string, fn func(*gorm.DB)) error) error {
+		if err := before("metrics:before_"+operation, startQueryTimer); err != nil {
+			return err
+		}
+		return after("metrics:after_"+operation, observeQuery(operation))
+	}
+
+	// GORM's processor type is unexported, so each one is registered by hand
+	return errors.Join(
+		register("create", callbacks.Create().Before("*").Register, callbacks.Create().After("*").Register),
+		register("query", callbacks.Query().Before("*").Register, callbacks.Query().After("*").Register),
+		register("update", callbacks.Update().Before("*").Register, callbacks.Update().After("*").Register),
+		register("delete", callbacks.Delete().Before("*").Register, callbacks.Delete().After("*").Register),
+		register("row", callbacks.Row().Before("*").Register, callbacks.Row().After("*").Register),
+		register("raw", callbacks.Raw().Before("*").Register, callbacks.Raw().After("*").Register),
+	)
+}
+
+func startQueryTimer(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		start, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown" // Raw SQL, GORM does not parse the table out of it
+		}
+		queryDuration.Observe(time.Since(start.(time.Time)).Seconds(), operation, table)
+	}
+}
+
+// RegisterPoolMetrics publishes the connection pool stats of sqlDB, read at every scrape
+func RegisterPoolMetrics(sqlDB *sql.DB) {
+	gauge := func(name, help string, value func(stats sql.DBStats) float64) {
+		metrics.NewGaugeFunc(name, help, func() []metrics.Sample {
+			return []metrics.Sample{{Value: value(sqlDB.Stats())}}
+		})
+	}
+	counter := func(name, help string, value func(stats sql.DBStats) float64) {
+		metrics.NewCounterFunc(name, help, func() []metrics.Sample {
+			return []metrics.Sample{{Value: value(sqlDB.Stats())}}
+		})
+	}
+
+	gauge("db_pool_max_open_connections", "Maximum number of open connections to the database.",
+		func(stats sql.DBStats) float64 { return float64(stats.MaxOpenConnections) })
+	gauge("db_pool_open_connections", "Established connections, in use and idle.",
+		func(stats sql.DBStats) float64 { return float64(stats.OpenConnections) })
+	gauge("db_pool_in_use_connections", "Connections currently in use.",
+		func(stats sql.DBStats) float64 { return float64(stats.InUse) })
+	gauge("db_pool_idle_connections", "Idle connections.",
+		func(stats sql.DBStats) float64 { return float64(stats.Idle) })
+	counter("db_pool_wait_count_total", "Connections waited for because the pool was exhausted.",
+		func(stats sql.DBStats) float64 { return float64(stats.WaitCount) })
+	counter("db_pool_wait_duration_seconds_total", "Time spent waiting for a connection.",
+		func(stats sql.DBStats) float64 { return stats.WaitDuration.Seconds() })
+	counter("db_pool_max_idle_closed_total", "Connections closed because of SetMaxIdleConns.",
+		func(stats sql.DBStats) float64 { return float64(stats.MaxIdleClosed) })
+	counter("db_pool_max_lifetime_closed_total", "Connections closed because of SetConnMaxLifetime.",
+		func(stats sql.DBStats) float64 { return float64(stats.MaxLifetimeClosed) })
+}
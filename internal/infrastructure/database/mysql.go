@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"time"
@@ -35,7 +37,32 @@ func InitGORM(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if err = gormDB.Use(QueryMetrics{}); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to register query metrics: %w", err)
+	}
+	RegisterPoolMetrics(sqlDB)
+
 	log.Println("Successfully connected to the database with GORM!")
 
 	return gormDB, nil
 }
+
+// CheckMigrations fails unless the schema is at least at version latest. It reads the schema_migrations
+// table kept by golang-migrate, which also marks a migration that failed halfway as dirty.
+func CheckMigrations(ctx context.Context, sqlDB *sql.DB, latest uint) error {
+	var version uint
+	var dirty bool
+	err := sqlDB.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("migration %d failed halfway and needs manual repair", version)
+	}
+	// A newer schema is fine, it belongs to a newer build rolling out alongside this one
+	if version < latest {
+		return fmt.Errorf("schema is at version %d, %d migrations are pending", version, latest-version)
+	}
+	return nil
+}
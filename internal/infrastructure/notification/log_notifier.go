@@ -0,0 +1,19 @@
+package notification
+
+import (
+	"log"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// LogNotifier only writes notifications to the log until a push, SMS or email provider is wired in
+type LogNotifier struct{}
+
+func NewLogNotifier() domain.CustomerNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) NotifyLoginLocked(customerID string, lockedUntil time.Time) error {
+	log.Printf("Notify customer %s: login locked after repeated failed attempts until %s", customerID, lockedUntil.Format(time.RFC3339))
+	return nil
+}
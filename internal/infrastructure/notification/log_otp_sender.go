@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// LogOTPSender is the local stand-in for an SMS gateway. Codes are appended as JSON lines to
+// the outbox file, or written to the log when no file is configured. Never use it in production.
+type LogOTPSender struct {
+	mu         sync.Mutex
+	outboxFile string
+}
+
+func NewLogOTPSender(outboxFile string) domain.OTPSender {
+	return &LogOTPSender{outboxFile: outboxFile}
+}
+
+func (s *LogOTPSender) Send(destination, purpose, code string) error {
+	if s.outboxFile == "" {
+		log.Printf("OTP for %s (%s): %s", destination, purpose, code)
+		return nil
+	}
+
+	line, err := json.Marshal(map[string]interface{}{
+		"destination": destination,
+		"purpose":     purpose,
+		"code":        code,
+		"sent_at":     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode OTP message: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.outboxFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open OTP outbox: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write OTP outbox: %w", err)
+	}
+	return nil
+}
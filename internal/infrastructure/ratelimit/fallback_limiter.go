@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/pkg/metrics"
+)
+
+// What to do with requests while the shared limiter is unreachable
+const (
+	FailureModeOpen   = "open"   // Let everything through
+	FailureModeClosed = "closed" // Reject everything with 503
+	FailureModeLocal  = "local"  // Throttle with per-instance buckets
+)
+
+// Published under /metrics. Decisions are counted by the mode that made them, primary while Redis answers.
+var (
+	limiterDegraded = metrics.NewGaugeVec("rate_limiter_degraded",
+		"1 while the shared rate limiter is unreachable and the failure mode decides, by failure mode.", "mode")
+	limiterDecisions = metrics.NewCounterVec("rate_limiter_decisions_total",
+		"Rate limit decisions by the mode that made them.", "mode")
+	limiterPrimaryErrors = metrics.NewCounterVec("rate_limiter_primary_errors_total",
+		"Failed calls to the shared rate limiter.")
+	limiterTransitions = metrics.NewCounterVec("rate_limiter_transitions_total",
+		"Switches to the failure mode (degraded) and back to the shared limiter (recovered).", "to")
+)
+
+// FallbackRateLimiter uses the primary limiter while it is healthy. After an error it switches to the
+// failure mode until the health probe succeeds again.
+type FallbackRateLimiter struct {
+	primary  domain.RateLimiter
+	local    *LocalRateLimiter
+	mode     string
+	probe    func() error
+	interval time.Duration
+	degraded atomic.Bool
+}
+
+func NewFallbackRateLimiter(primary domain.RateLimiter, mode string, probe func() error, interval time.Duration) *FallbackRateLimiter {
+	limiter := &FallbackRateLimiter{
+		primary:  primary,
+		local:    NewLocalRateLimiter(),
+		mode:     mode,
+		probe:    probe,
+		interval: interval,
+	}
+	limiterDegraded.Set(0, mode)
+	return limiter
+}
+
+func (l *FallbackRateLimiter) Allow(ctx context.Context, key string, policy domain.RateLimitPolicy) (*domain.RateLimitResult, error) {
+	if !l.degraded.Load() {
+		result, err := l.primary.Allow(ctx, key, policy)
+		if err == nil {
+			limiterDecisions.Inc("primary")
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err // The request ended, that says nothing about the primary's health
+		}
+
+		limiterPrimaryErrors.Inc()
+		if l.degraded.CompareAndSwap(false, true) {
+			limiterDegraded.Set(1, l.mode)
+			limiterTransitions.Inc("degraded")
+			log.Printf("Rate limiter degraded to %q mode: %v", l.mode, err)
+		}
+	}
+
+	switch l.mode {
+	case FailureModeOpen:
+		limiterDecisions.Inc(FailureModeOpen)
+		return &domain.RateLimitResult{Allowed: true, Remaining: policy.Burst}, nil
+	case FailureModeClosed:
+		limiterDecisions.Inc(FailureModeClosed)
+		return nil, domain.ErrRateLimiterUnavailable
+	default:
+		limiterDecisions.Inc(FailureModeLocal)
+		return l.local.Allow(ctx, key, policy)
+	}
+}
+
+// RunHealthProbe checks the primary while degraded and switches back once it answers
+func (l *FallbackRateLimiter) RunHealthProbe(stop <-chan struct{}) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !l.degraded.Load() {
+				continue
+			}
+			if err := l.probe(); err != nil {
+				continue
+			}
+			l.degraded.Store(false)
+			limiterDegraded.Set(0, l.mode)
+			limiterTransitions.Inc("recovered")
+			log.Printf("Rate limiter recovered, using the shared limiter again")
+		}
+	}
+}
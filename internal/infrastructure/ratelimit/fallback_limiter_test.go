@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestFallbackRateLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.RateLimitPolicy{Name: "default", RequestsPerSecond: 1, Burst: 2}
+	redisDown := errors.New("redis: connection refused")
+
+	// Test case 1: Healthy primary decides
+	t.Run("primary_healthy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockPrimary := mock.NewMockRateLimiter(ctrl)
+		mockPrimary.EXPECT().Allow(gomock.Any(), "10.0.0.1", policy).Return(&domain.RateLimitResult{Allowed: false, RetryAfter: time.Second}, nil).Times(2)
+		limiter := NewFallbackRateLimiter(mockPrimary, FailureModeLocal, nil, time.Minute)
+
+		for range 2 {
+			result, err := limiter.Allow(ctx, "10.0.0.1", policy)
+			if err != nil || result.Allowed || result.RetryAfter != time.Second {
+				t.Fatalf("Expected the primary's decision, got %+v, %v", result, err)
+			}
+		}
+	})
+
+	// Test case 2: Each failure mode answers once the primary fails, without calling it again
+	modes := []struct {
+		mode    string
+		allowed bool
+		err     error
+	}{
+		{mode: FailureModeOpen, allowed: true},
+		{mode: FailureModeClosed, err: domain.ErrRateLimiterUnavailable},
+		{mode: FailureModeLocal, allowed: true},
+	}
+	for _, tt := range modes {
+		t.Run("degraded_"+tt.mode, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPrimary := mock.NewMockRateLimiter(ctrl)
+			mockPrimary.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, redisDown).Times(1)
+			limiter := NewFallbackRateLimiter(mockPrimary, tt.mode, nil, time.Minute)
+
+			for range 2 {
+				result, err := limiter.Allow(ctx, "10.0.0.1", policy)
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Expected error %v, got %v", tt.err, err)
+				}
+				if err == nil && result.Allowed != tt.allowed {
+					t.Fatalf("Expected allowed %v, got %+v", tt.allowed, result)
+				}
+			}
+			if !limiter.degraded.Load() {
+				t.Error("Expected the limiter to be degraded")
+			}
+		})
+	}
+
+	// Test case 3: Local mode still enforces the policy
+	t.Run("degraded_local_enforces_burst", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockPrimary := mock.NewMockRateLimiter(ctrl)
+		mockPrimary.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, redisDown).Times(1)
+		limiter := NewFallbackRateLimiter(mockPrimary, FailureModeLocal, nil, time.Minute)
+
+		limiter.Allow(ctx, "10.0.0.1", policy)
+		limiter.Allow(ctx, "10.0.0.1", policy)
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", policy); result.Allowed {
+			t.Error("Expected the local bucket to reject after the burst")
+		}
+	})
+
+	// Test case 4: A request that ended says nothing about the primary's health
+	t.Run("cancelled_request_does_not_degrade", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockPrimary := mock.NewMockRateLimiter(ctrl)
+		mockPrimary.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, context.Canceled).Times(1)
+		limiter := NewFallbackRateLimiter(mockPrimary, FailureModeOpen, nil, time.Minute)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if _, err := limiter.Allow(cancelled, "10.0.0.1", policy); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected the context error, got %v", err)
+		}
+		if limiter.degraded.Load() {
+			t.Error("Expected the limiter to stay on the primary")
+		}
+	})
+}
+
+func TestFallbackRateLimiter_RunHealthProbe(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.RateLimitPolicy{Name: "default", RequestsPerSecond: 1, Burst: 2}
+
+	ctrl := gomock.NewController(t)
+	mockPrimary := mock.NewMockRateLimiter(ctrl)
+	var probes atomic.Int32
+	probe := func() error {
+		if probes.Add(1) < 3 {
+			return errors.New("redis: connection refused")
+		}
+		return nil
+	}
+	limiter := NewFallbackRateLimiter(mockPrimary, FailureModeClosed, probe, time.Millisecond)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		limiter.RunHealthProbe(stop)
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	// Test case 1: Healthy limiter is not probed
+	time.Sleep(10 * time.Millisecond)
+	if probes.Load() != 0 {
+		t.Fatalf("Expected no probes while healthy, got %d", probes.Load())
+	}
+
+	// Test case 2: Failing probes keep the failure mode, the first success switches back
+	mockPrimary.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("redis: connection refused")).Times(1)
+	if _, err := limiter.Allow(ctx, "10.0.0.1", policy); !errors.Is(err, domain.ErrRateLimiterUnavailable) {
+		t.Fatalf("Expected the closed failure mode, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for limiter.degraded.Load() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected recovery after a successful probe, %d probes so far", probes.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if probes.Load() < 3 {
+		t.Errorf("Expected recovery only after the probe succeeded, got %d probes", probes.Load())
+	}
+
+	mockPrimary.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.RateLimitResult{Allowed: true}, nil).Times(1)
+	if result, err := limiter.Allow(ctx, "10.0.0.1", policy); err != nil || !result.Allowed {
+		t.Errorf("Expected the primary to decide again, got %+v, %v", result, err)
+	}
+}
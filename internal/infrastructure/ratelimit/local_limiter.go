@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// Past this many buckets the least recently used one is evicted, so a flood of distinct IPs cannot exhaust memory
+const localMaxBuckets = 100000
+
+type localBucket struct {
+	key      string
+	tokens   float64
+	lastSeen time.Time
+}
+
+// LocalRateLimiter keeps token buckets in process memory. Limits apply per instance,
+// so a fleet of N instances lets through up to N times the configured rate.
+type LocalRateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*list.Element
+	order      *list.List // Front is the most recently used
+	maxBuckets int
+	now        func() time.Time
+}
+
+func NewLocalRateLimiter() *LocalRateLimiter {
+	return &LocalRateLimiter{
+		buckets:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxBuckets: localMaxBuckets,
+		now:        time.Now,
+	}
+}
+
+func (l *LocalRateLimiter) Allow(ctx context.Context, key string, policy domain.RateLimitPolicy) (*domain.RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucketKey := policy.Name + ":" + key
+	var bucket *localBucket
+	if element, ok := l.buckets[bucketKey]; ok {
+		l.order.MoveToFront(element)
+		bucket = element.Value.(*localBucket)
+	} else {
+		// The evicted bucket is the one idle longest, and has most likely refilled already
+		if l.order.Len() >= l.maxBuckets {
+			evicted := l.order.Remove(l.order.Back()).(*localBucket)
+			delete(l.buckets, evicted.key)
+		}
+		bucket = &localBucket{key: bucketKey, tokens: float64(policy.Burst), lastSeen: now}
+		l.buckets[bucketKey] = l.order.PushFront(bucket)
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(float64(policy.Burst), bucket.tokens+math.Max(0, elapsed)*policy.RequestsPerSecond)
+	bucket.lastSeen = now
+
+	result := &domain.RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / policy.RequestsPerSecond)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = secondsToDuration((float64(policy.Burst) - bucket.tokens) / policy.RequestsPerSecond)
+
+	return result, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
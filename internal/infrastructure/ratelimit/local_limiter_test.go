@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+// newClockedLimiter returns a limiter whose clock only moves when advance is called
+func newClockedLimiter() (*LocalRateLimiter, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLocalRateLimiter()
+	limiter.now = func() time.Time { return now }
+	return limiter, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestLocalRateLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.RateLimitPolicy{Name: "default", RequestsPerSecond: 2, Burst: 3}
+
+	// Test case 1: A new bucket allows the burst, then asks to retry once a token is back
+	t.Run("burst_then_reject", func(t *testing.T) {
+		limiter, _ := newClockedLimiter()
+
+		for want := 2; want >= 0; want-- {
+			result, _ := limiter.Allow(ctx, "10.0.0.1", policy)
+			if !result.Allowed || result.Remaining != want {
+				t.Fatalf("Expected allowed with %d remaining, got %+v", want, result)
+			}
+		}
+
+		result, _ := limiter.Allow(ctx, "10.0.0.1", policy)
+		if result.Allowed {
+			t.Fatal("Expected the request after the burst to be rejected")
+		}
+		if result.RetryAfter != 500*time.Millisecond || result.ResetAfter != 1500*time.Millisecond {
+			t.Errorf("Expected retry after 500ms and reset after 1.5s, got %+v", result)
+		}
+	})
+
+	// Test case 2: Tokens come back at the configured rate, up to the burst
+	t.Run("refill", func(t *testing.T) {
+		limiter, advance := newClockedLimiter()
+		for range 3 {
+			limiter.Allow(ctx, "10.0.0.1", policy)
+		}
+
+		advance(500 * time.Millisecond)
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", policy); !result.Allowed || result.Remaining != 0 {
+			t.Fatalf("Expected one token after 500ms, got %+v", result)
+		}
+
+		advance(time.Hour)
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", policy); !result.Allowed || result.Remaining != 2 {
+			t.Errorf("Expected the bucket to refill no further than the burst, got %+v", result)
+		}
+	})
+
+	// Test case 3: Buckets are kept per policy and key
+	t.Run("separate_buckets", func(t *testing.T) {
+		limiter, _ := newClockedLimiter()
+		single := domain.RateLimitPolicy{Name: "auth", RequestsPerSecond: 1, Burst: 1}
+		limiter.Allow(ctx, "10.0.0.1", single)
+
+		if result, _ := limiter.Allow(ctx, "10.0.0.2", single); !result.Allowed {
+			t.Error("Expected another key to have its own bucket")
+		}
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", domain.RateLimitPolicy{Name: "transaction", RequestsPerSecond: 1, Burst: 1}); !result.Allowed {
+			t.Error("Expected another policy to have its own bucket")
+		}
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", single); result.Allowed {
+			t.Error("Expected the used bucket to stay empty")
+		}
+	})
+
+	// Test case 4: When full, the least recently used bucket is evicted
+	t.Run("evict_least_recently_used", func(t *testing.T) {
+		limiter, _ := newClockedLimiter()
+		limiter.maxBuckets = 2
+		limiter.Allow(ctx, "10.0.0.1", policy)
+		limiter.Allow(ctx, "10.0.0.2", policy)
+		limiter.Allow(ctx, "10.0.0.1", policy)
+		limiter.Allow(ctx, "10.0.0.3", policy)
+
+		if len(limiter.buckets) != 2 || limiter.order.Len() != 2 {
+			t.Fatalf("Expected 2 buckets, got %d", len(limiter.buckets))
+		}
+		if _, ok := limiter.buckets["default:10.0.0.2"]; ok {
+			t.Error("Expected the least recently used bucket to be evicted")
+		}
+		if result, _ := limiter.Allow(ctx, "10.0.0.1", policy); !result.Allowed || result.Remaining != 0 {
+			t.Errorf("Expected the recently used bucket to keep its count, got %+v", result)
+		}
+	})
+}
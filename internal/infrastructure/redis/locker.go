@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	mathrand "math/rand/v2"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lockKeyPrefix = "lock:"
+	lockRetryMin  = 25 * time.Millisecond
+	lockRetryMax  = time.Second
+)
+
+// renewLockScript extends the lease only while the caller still owns the lock. KEYS[1] lock, ARGV[1] token,
+// ARGV[2] lease in milliseconds.
+var renewLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock only while the caller still owns it, so a late release never frees
+// a lock that has passed to another owner. KEYS[1] lock, ARGV[1] token.
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type RedisLocker struct {
+	client *redis.Client
+}
+
+func NewRedisLocker(client *redis.Client) domain.Locker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) Acquire(ctx context.Context, key string, lease time.Duration) (domain.Lock, error) {
+	delay := lockRetryMin
+	for {
+		lock, err := l.TryAcquire(ctx, key, lease)
+		if err != domain.ErrLockHeld {
+			return lock, err
+		}
+
+		// Jitter keeps waiting instances from retrying in lockstep
+		timer := time.NewTimer(delay/2 + mathrand.N(delay/2+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, lockRetryMax)
+	}
+}
+
+func (l *RedisLocker) TryAcquire(ctx context.Context, key string, lease time.Duration) (domain.Lock, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	acquired, err := l.client.SetNX(ctx, lockKeyPrefix+key, token, lease).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis lock acquire failed: %w", err)
+	}
+	if !acquired {
+		return nil, domain.ErrLockHeld
+	}
+
+	lockCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	lock := &redisLock{client: l.client, key: lockKeyPrefix + key, token: token, ctx: lockCtx, cancel: cancel}
+	go lock.renew(lease)
+	return lock, nil
+}
+
+type redisLock struct {
+	client *redis.Client
+	key    string
+	token  string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (l *redisLock) Context() context.Context {
+	return l.ctx
+}
+
+func (l *redisLock) Release() error {
+	l.cancel() // Stops the renewal
+
+	released, err := releaseLockScript.Run(context.WithoutCancel(l.ctx), l.client, []string{l.key}, l.token).Int()
+	if err != nil {
+		return fmt.Errorf("redis lock release failed: %w", err)
+	}
+	if released == 0 {
+		return domain.ErrLockLost
+	}
+	return nil
+}
+
+// renew extends the lease every third of it. The lock counts as lost once another owner took it over,
+// or once renewals kept failing for a whole lease, as the key has expired by then.
+func (l *redisLock) renew(lease time.Duration) {
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := renewLockScript.Run(l.ctx, l.client, []string{l.key}, l.token, lease.Milliseconds()).Int()
+			switch {
+			case err == nil && renewed == 1:
+				renewedAt = time.Now()
+			case err == nil:
+				log.Printf("Lock %s was taken over by another owner", l.key)
+				l.cancel()
+				return
+			case l.ctx.Err() != nil:
+				return // Released meanwhile
+			case time.Since(renewedAt) >= lease:
+				log.Printf("Lock %s lost, renewals failed for a whole lease: %v", l.key, err)
+				l.cancel()
+				return
+			default:
+				log.Printf("Redis lock renewal error for %s: %v", l.key, err)
+			}
+		}
+	}
+}
+
+func newLockToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// recordFailureScript counts a failure and starts the window on the first one in one atomic step, so a
+// counter is never left without an expiry. A counter found without one, e.g. written by an older
+// version, gets the window too.
+//
+// KEYS[1] failure counter, ARGV[1] window in milliseconds. Returns the failure count.
+var recordFailureScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 or redis.call('PTTL', KEYS[1]) == -1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return failures
+`)
+
+type RedisLoginAttemptTracker struct {
+	client *redis.Client
+}
+
+func NewRedisLoginAttemptTracker(client *redis.Client) domain.LoginAttemptTracker {
+	return &RedisLoginAttemptTracker{client: client}
+}
+
+func (t *RedisLoginAttemptTracker) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := t.client.PTTL(ctx, loginLockKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis login lock lookup failed: %w", err)
+	}
+	// Negative TTLs mean the key does not exist or has no expiry
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (t *RedisLoginAttemptTracker) RecordFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	// The window starts with the first failure and is not extended by later ones
+	failures, err := recordFailureScript.Run(ctx, t.client, []string{loginFailuresKey(key)}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis login failure record failed: %w", err)
+	}
+	return failures, nil
+}
+
+func (t *RedisLoginAttemptTracker) Lock(ctx context.Context, key string, duration time.Duration) error {
+	remaining, err := t.LockedFor(ctx, key)
+	if err != nil {
+		return err
+	}
+	if remaining >= duration {
+		return nil
+	}
+
+	if err := t.client.Set(ctx, loginLockKey(key), 1, duration).Err(); err != nil {
+		return fmt.Errorf("redis login lock failed: %w", err)
+	}
+	return nil
+}
+
+func (t *RedisLoginAttemptTracker) Reset(ctx context.Context, key string) error {
+	if err := t.client.Del(ctx, loginFailuresKey(key), loginLockKey(key)).Err(); err != nil {
+		return fmt.Errorf("redis login attempt reset failed: %w", err)
+	}
+	return nil
+}
+
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+func loginLockKey(key string) string {
+	return fmt.Sprintf("login_lock:%s", key)
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	otpMissing = iota
+	otpMatched
+	otpMismatched
+)
+
+// consumeOTPScript compares, counts and consumes in one atomic step, so a code is accepted once however many
+// requests race with it. A missing or expired challenge is never recreated, the attempt count of a live one
+// keeps its TTL. The hashes are keyed, so comparing them in Lua leaks nothing about the code.
+//
+// KEYS[1] challenge, ARGV[1] hash of the submitted code, ARGV[2] max attempts.
+// Returns {status, payload}, with the payload only when the code matched.
+var consumeOTPScript = redis.NewScript(`
+local challenge = redis.call('HMGET', KEYS[1], 'code_hash', 'payload')
+if not challenge[1] then
+  return {0, ''}
+end
+
+if challenge[1] == ARGV[1] then
+  redis.call('DEL', KEYS[1])
+  return {1, challenge[2] or ''}
+end
+
+if redis.call('HINCRBY', KEYS[1], 'attempts', 1) >= tonumber(ARGV[2]) then
+  redis.call('DEL', KEYS[1])
+end
+return {2, ''}
+`)
+
+type RedisOTPStore struct {
+	client *redis.Client
+}
+
+func NewRedisOTPStore(client *redis.Client) domain.OTPStore {
+	return &RedisOTPStore{client: client}
+}
+
+func (s *RedisOTPStore) Save(ctx context.Context, purpose, subject string, challenge *domain.OTPChallenge, ttl time.Duration) error {
+	key := otpKey(purpose, subject)
+
+	// Replaces any earlier challenge together with its attempt count
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "code_hash", challenge.CodeHash, "payload", challenge.Payload, "attempts", challenge.Attempts)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis otp save failed: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisOTPStore) Consume(ctx context.Context, purpose, subject, codeHash string, maxAttempts int) (string, error) {
+	values, err := consumeOTPScript.Run(ctx, s.client, []string{otpKey(purpose, subject)}, codeHash, maxAttempts).Slice()
+	if err != nil {
+		return "", fmt.Errorf("redis otp consume script failed: %w", err)
+	}
+	if len(values) != 2 {
+		return "", fmt.Errorf("redis otp consume script returned %d values", len(values))
+	}
+	status, _ := values[0].(int64)
+	payload, _ := values[1].(string)
+
+	switch status {
+	case otpMatched:
+		return payload, nil
+	case otpMismatched:
+		return "", domain.ErrInvalidInput
+	case otpMissing:
+		return "", domain.ErrNotFound
+	default:
+		return "", fmt.Errorf("redis otp consume script returned status %d", status)
+	}
+}
+
+func (s *RedisOTPStore) Delete(ctx context.Context, purpose, subject string) error {
+	if err := s.client.Del(ctx, otpKey(purpose, subject)).Err(); err != nil {
+		return fmt.Errorf("redis otp delete failed: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisOTPStore) StartCooldown(ctx context.Context, purpose, subject string, cooldown time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf("otp_cooldown:%s:%s", purpose, subject)
+
+	started, err := s.client.SetNX(ctx, key, 1, cooldown).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis otp cooldown failed: %w", err)
+	}
+	if started {
+		return 0, nil
+	}
+
+	remaining, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis otp cooldown lookup failed: %w", err)
+	}
+	if remaining <= 0 {
+		remaining = time.Second
+	}
+	return remaining, nil
+}
+
+func otpKey(purpose, subject string) string {
+	return fmt.Sprintf("otp:%s:%s", purpose, subject)
+}
@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisOTPStore_Consume(t *testing.T) {
+	tests := []struct {
+		name        string
+		reply       []any
+		err         error
+		wantPayload string
+		wantErr     error
+	}{
+		{
+			name:        "matched",
+			reply:       []any{int64(otpMatched), "+6281234567890"},
+			wantPayload: "+6281234567890",
+		},
+		{
+			name:    "mismatched",
+			reply:   []any{int64(otpMismatched), ""},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "missing",
+			reply:   []any{int64(otpMissing), ""},
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name:  "unknown_status",
+			reply: []any{int64(9), ""},
+		},
+		{
+			name: "redis_error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := redis.NewClient(&redis.Options{Addr: "redis.invalid:6379"})
+			defer client.Close()
+			client.AddHook(scriptHook{reply: func(args []any) (any, error) {
+				// EVALSHA sha 1 key hash max
+				if len(args) != 6 || args[3] != "otp:phone_verification:customer-1" || args[4] != "hash" || args[5] != 3 {
+					t.Errorf("Unexpected script call %v", args)
+				}
+				return tt.reply, tt.err
+			}})
+
+			payload, err := NewRedisOTPStore(client).Consume(context.Background(), domain.OTPPurposePhoneVerification, "customer-1", "hash", 3)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %q, %v", tt.wantErr, payload, err)
+				}
+			case tt.wantPayload == "":
+				if err == nil || errors.Is(err, domain.ErrInvalidInput) || errors.Is(err, domain.ErrNotFound) {
+					t.Fatalf("Expected a store error, got %q, %v", payload, err)
+				}
+			default:
+				if err != nil || payload != tt.wantPayload {
+					t.Fatalf("Expected payload %q, got %q, %v", tt.wantPayload, payload, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConsumeOTPScript(t *testing.T) {
+	client := newTestClient(t)
+	store := NewRedisOTPStore(client)
+	ctx := context.Background()
+	purpose := domain.OTPPurposePhoneVerification
+
+	save := func(t *testing.T) string {
+		subject := uuid.NewString()
+		t.Cleanup(func() { client.Del(ctx, otpKey(purpose, subject)) })
+		if err := store.Save(ctx, purpose, subject, &domain.OTPChallenge{CodeHash: "right", Payload: "payload"}, time.Minute); err != nil {
+			t.Fatalf("Failed to save challenge: %v", err)
+		}
+		return subject
+	}
+
+	// Test case 1: A code is accepted once
+	t.Run("single_use", func(t *testing.T) {
+		subject := save(t)
+
+		if payload, err := store.Consume(ctx, purpose, subject, "right", 3); err != nil || payload != "payload" {
+			t.Fatalf("Expected the payload, got %q, %v", payload, err)
+		}
+		if _, err := store.Consume(ctx, purpose, subject, "right", 3); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the second use to find nothing, got %v", err)
+		}
+	})
+
+	// Test case 2: Wrong codes keep the TTL and use up the challenge
+	t.Run("attempts_used_up", func(t *testing.T) {
+		subject := save(t)
+
+		for range 2 {
+			if _, err := store.Consume(ctx, purpose, subject, "wrong", 3); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Fatalf("Expected ErrInvalidInput, got %v", err)
+			}
+		}
+		if ttl := client.PTTL(ctx, otpKey(purpose, subject)).Val(); ttl <= 0 || ttl > time.Minute {
+			t.Errorf("Expected the challenge to keep its TTL, got %s", ttl)
+		}
+
+		store.Consume(ctx, purpose, subject, "wrong", 3)
+		if _, err := store.Consume(ctx, purpose, subject, "right", 3); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the challenge to be gone after the last attempt, got %v", err)
+		}
+	})
+
+	// Test case 3: A missing challenge is not recreated
+	t.Run("missing_not_recreated", func(t *testing.T) {
+		subject := uuid.NewString()
+
+		if _, err := store.Consume(ctx, purpose, subject, "right", 3); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+		if exists := client.Exists(ctx, otpKey(purpose, subject)).Val(); exists != 0 {
+			t.Error("Expected no key to be left behind")
+		}
+	})
+}
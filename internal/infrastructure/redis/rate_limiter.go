@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills and takes a token in one atomic step. It reads the clock from Redis
+// so instances with drifting clocks share one notion of time.
+//
+// KEYS[1] bucket, ARGV[1] tokens per second, ARGV[2] burst.
+// Returns {allowed, remaining tokens, ms until the next token, ms until full}.
+var tokenBucketScript = redis.NewScript(`
+if redis.replicate_commands then redis.replicate_commands() end
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry_ms = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_ms = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+-- An untouched bucket is full after this long, so it can simply disappear
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+
+return {allowed, math.floor(tokens), retry_ms, math.ceil((burst - tokens) * 1000 / rate)}
+`)
+
+type RedisRateLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimiter(client *redis.Client) domain.RateLimiter {
+	return &RedisRateLimiter{client: client}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, policy domain.RateLimitPolicy) (*domain.RateLimitResult, error) {
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{rateLimitKey(policy.Name, key)}, policy.RequestsPerSecond, policy.Burst).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("redis rate limit script failed: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("redis rate limit script returned %d values", len(values))
+	}
+
+	return &domain.RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+func rateLimitKey(policy, key string) string {
+	return fmt.Sprintf("rate_limit:%s:%s", policy, key)
+}
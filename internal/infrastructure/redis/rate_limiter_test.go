@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestClient connects to the Redis at REDIS_TEST_ADDR, tests that run scripts are skipped without one
+func newTestClient(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("Failed to connect to Redis at %s: %v", addr, err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// scriptHook answers every command without a server, handing the arguments to reply
+type scriptHook struct {
+	reply func(args []any) (any, error)
+}
+
+func (h scriptHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("unexpected dial")
+	}
+}
+
+func (h scriptHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		val, err := h.reply(cmd.Args())
+		if err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		cmd.(*redis.Cmd).SetVal(val)
+		return nil
+	}
+}
+
+func (h scriptHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	policy := domain.RateLimitPolicy{Name: "auth", RequestsPerSecond: 0.5, Burst: 5}
+
+	tests := []struct {
+		name    string
+		reply   []any
+		err     error
+		want    *domain.RateLimitResult
+		wantErr bool
+	}{
+		{
+			name:  "allowed",
+			reply: []any{int64(1), int64(4), int64(0), int64(2000)},
+			want:  &domain.RateLimitResult{Allowed: true, Remaining: 4, ResetAfter: 2 * time.Second},
+		},
+		{
+			name:  "rejected",
+			reply: []any{int64(0), int64(0), int64(1500), int64(10000)},
+			want:  &domain.RateLimitResult{Remaining: 0, RetryAfter: 1500 * time.Millisecond, ResetAfter: 10 * time.Second},
+		},
+		{
+			name:    "short_reply",
+			reply:   []any{int64(1)},
+			wantErr: true,
+		},
+		{
+			name:    "redis_error",
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := redis.NewClient(&redis.Options{Addr: "redis.invalid:6379"})
+			defer client.Close()
+			client.AddHook(scriptHook{reply: func(args []any) (any, error) {
+				// EVALSHA sha 1 key rate burst
+				if len(args) != 6 || args[3] != "rate_limit:auth:10.0.0.1" || args[4] != 0.5 || args[5] != 5 {
+					t.Errorf("Unexpected script call %v", args)
+				}
+				return tt.reply, tt.err
+			}})
+
+			result, err := NewRedisRateLimiter(client).Allow(context.Background(), "10.0.0.1", policy)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %+v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if *result != *tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestTokenBucketScript(t *testing.T) {
+	client := newTestClient(t)
+	limiter := NewRedisRateLimiter(client)
+	ctx := context.Background()
+
+	// Test case 1: A new bucket allows the burst, then asks to retry once a token is back
+	t.Run("burst_then_reject", func(t *testing.T) {
+		policy := domain.RateLimitPolicy{Name: "test", RequestsPerSecond: 1, Burst: 3}
+		key := uuid.NewString()
+		defer client.Del(ctx, rateLimitKey(policy.Name, key))
+
+		for want := 2; want >= 0; want-- {
+			result, err := limiter.Allow(ctx, key, policy)
+			if err != nil || !result.Allowed || result.Remaining != want {
+				t.Fatalf("Expected allowed with %d remaining, got %+v, %v", want, result, err)
+			}
+		}
+
+		result, err := limiter.Allow(ctx, key, policy)
+		if err != nil || result.Allowed {
+			t.Fatalf("Expected the request after the burst to be rejected, got %+v, %v", result, err)
+		}
+		if result.RetryAfter <= 0 || result.RetryAfter > time.Second {
+			t.Errorf("Expected a retry within one token interval, got %s", result.RetryAfter)
+		}
+		if result.ResetAfter <= 2*time.Second || result.ResetAfter > 3*time.Second {
+			t.Errorf("Expected the bucket to be full in about 3s, got %s", result.ResetAfter)
+		}
+	})
+
+	// Test case 2: Tokens come back at the configured rate
+	t.Run("refill", func(t *testing.T) {
+		policy := domain.RateLimitPolicy{Name: "test", RequestsPerSecond: 20, Burst: 1}
+		key := uuid.NewString()
+		defer client.Del(ctx, rateLimitKey(policy.Name, key))
+
+		if result, _ := limiter.Allow(ctx, key, policy); !result.Allowed {
+			t.Fatal("Expected the first request to be allowed")
+		}
+		if result, _ := limiter.Allow(ctx, key, policy); result.Allowed {
+			t.Fatal("Expected the empty bucket to reject")
+		}
+		time.Sleep(60 * time.Millisecond)
+		if result, _ := limiter.Allow(ctx, key, policy); !result.Allowed {
+			t.Error("Expected a token after one refill interval")
+		}
+	})
+
+	// Test case 3: Buckets are kept per policy and key, and expire once they would be full
+	t.Run("separate_buckets_expire", func(t *testing.T) {
+		policy := domain.RateLimitPolicy{Name: "test", RequestsPerSecond: 1, Burst: 1}
+		other := domain.RateLimitPolicy{Name: "other", RequestsPerSecond: 1, Burst: 1}
+		key := uuid.NewString()
+		defer client.Del(ctx, rateLimitKey(policy.Name, key), rateLimitKey(other.Name, key))
+
+		limiter.Allow(ctx, key, policy)
+		if result, _ := limiter.Allow(ctx, key, other); !result.Allowed {
+			t.Error("Expected another policy to have its own bucket")
+		}
+		if result, _ := limiter.Allow(ctx, uuid.NewString(), policy); !result.Allowed {
+			t.Error("Expected another key to have its own bucket")
+		}
+
+		ttl := client.PTTL(ctx, rateLimitKey(policy.Name, key)).Val()
+		if ttl <= 0 || ttl > 2*time.Second {
+			t.Errorf("Expected the bucket to expire within refill time plus a second, got %s", ttl)
+		}
+	})
+}
@@ -11,8 +11,6 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-var Ctx = context.Background()
-
 type RedisCacheStore struct {
 	client *redis.Client
 }
@@ -29,7 +27,7 @@ func InitRedisClient(cfg *config.Config) (*redis.Client, error) {
 		PoolSize: 10,
 	})
 
-	pong, err := rdb.Ping(Ctx).Result()
+	pong, err := rdb.Ping(context.Background()).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
@@ -38,8 +36,43 @@ func InitRedisClient(cfg *config.Config) (*redis.Client, error) {
 	return rdb, nil
 }
 
-func (r *RedisCacheStore) Set(key string, value interface{}, expiration time.Duration) error {
-	err := r.client.Set(Ctx, key, value, expiration).Err()
+// Tag sets hold the keys stored with a tag and live as long as their longest-lived key
+const cacheTagPrefix = "cache_tag:"
+
+// setWithTagsScript stores the value and registers the key with each tag in one step.
+// KEYS[1] value key, KEYS[2..] tag sets. ARGV[1] value, ARGV[2] TTL in milliseconds (0 is no expiry).
+var setWithTagsScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+  redis.call('SET', KEYS[1], ARGV[1])
+end
+
+for i = 2, #KEYS do
+  local current = redis.call('PTTL', KEYS[i]) -- -2 new set, -1 no expiry
+  redis.call('SADD', KEYS[i], KEYS[1])
+  if ttl == 0 then
+    redis.call('PERSIST', KEYS[i])
+  elseif current == -2 or (current >= 0 and current < ttl) then
+    redis.call('PEXPIRE', KEYS[i], ttl)
+  end
+end
+return 1
+`)
+
+// invalidateTagScript deletes the tag's keys in batches and then the tag set itself. KEYS[1] tag set.
+var invalidateTagScript = redis.NewScript(`
+local members = redis.call('SMEMBERS', KEYS[1])
+for i = 1, #members, 500 do
+  redis.call('DEL', unpack(members, i, math.min(i + 499, #members)))
+end
+redis.call('DEL', KEYS[1])
+return #members
+`)
+
+func (r *RedisCacheStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	err := r.client.Set(ctx, key, value, expiration).Err()
 	if err != nil {
 		log.Printf("Redis Set error for key %s: %v", key, err)
 		return fmt.Errorf("redis set failed: %w", err)
@@ -47,11 +80,34 @@ func (r *RedisCacheStore) Set(key string, value interface{}, expiration time.Dur
 	return nil
 }
 
-func (r *RedisCacheStore) Get(key string) (string, error) {
-	val, err := r.client.Get(Ctx, key).Result()
+func (r *RedisCacheStore) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	keys := []string{key}
+	for _, tag := range tags {
+		keys = append(keys, cacheTagPrefix+tag)
+	}
+
+	err := setWithTagsScript.Run(ctx, r.client, keys, value, expiration.Milliseconds()).Err()
+	if err != nil {
+		log.Printf("Redis SetWithTags error for key %s: %v", key, err)
+		return fmt.Errorf("redis tagged set failed: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisCacheStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	set, err := r.client.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		log.Printf("Redis SetNX error for key %s: %v", key, err)
+		return false, fmt.Errorf("redis setnx failed: %w", err)
+	}
+	return set, nil
+}
+
+func (r *RedisCacheStore) Get(ctx context.Context, key string) (string, error) {
+	val, err := r.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return "", fmt.Errorf("key %s not found in redis", key)
+			return "", domain.ErrCacheMiss
 		}
 		log.Printf("Redis Get error for key %s: %v", key, err)
 		return "", fmt.Errorf("redis get failed: %w", err)
@@ -59,15 +115,71 @@ func (r *RedisCacheStore) Get(key string) (string, error) {
 	return val, nil
 }
 
-func (r *RedisCacheStore) Del(key string) error {
-	err := r.client.Del(Ctx, key).Err()
+func (r *RedisCacheStore) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	found := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("Redis MGet error for %d keys: %v", len(keys), err)
+		return nil, fmt.Errorf("redis mget failed: %w", err)
+	}
+	for i, value := range values {
+		if s, ok := value.(string); ok { // nil for missing keys
+			found[keys[i]] = s
+		}
+	}
+	return found, nil
+}
+
+func (r *RedisCacheStore) Incr(ctx context.Context, key string) (int64, error) {
+	val, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		log.Printf("Redis Incr error for key %s: %v", key, err)
+		return 0, fmt.Errorf("redis incr failed: %w", err)
+	}
+	return val, nil
+}
+
+func (r *RedisCacheStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		log.Printf("Redis TTL error for key %s: %v", key, err)
+		return 0, fmt.Errorf("redis ttl failed: %w", err)
+	}
+	// go-redis reports Redis' -2 (missing) and -1 (no expiry) as those many nanoseconds
+	switch ttl {
+	case -2:
+		return 0, domain.ErrCacheMiss
+	case -1:
+		return domain.CacheNoExpiry, nil
+	}
+	return ttl, nil
+}
+
+func (r *RedisCacheStore) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	err := r.client.Del(ctx, keys...).Err()
 	if err != nil {
-		log.Printf("Redis Del error for key %s: %v", key, err)
+		log.Printf("Redis Del error for keys %v: %v", keys, err)
 		return fmt.Errorf("redis delete failed: %w", err)
 	}
 	return nil
 }
 
+func (r *RedisCacheStore) InvalidateTag(ctx context.Context, tag string) error {
+	err := invalidateTagScript.Run(ctx, r.client, []string{cacheTagPrefix + tag}).Err()
+	if err != nil {
+		log.Printf("Redis InvalidateTag error for tag %s: %v", tag, err)
+		return fmt.Errorf("redis tag invalidation failed: %w", err)
+	}
+	return nil
+}
+
 func (r *RedisCacheStore) Close() error {
 	return r.client.Close()
 }
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisTokenDenylist struct {
+	client *redis.Client
+}
+
+func NewRedisTokenDenylist(client *redis.Client) domain.TokenDenylist {
+	return &RedisTokenDenylist{client: client}
+}
+
+func (d *RedisTokenDenylist) Deny(ctx context.Context, sessionID string, ttl time.Duration) error {
+	if err := d.client.Set(ctx, denylistKey(sessionID), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("redis denylist set failed: %w", err)
+	}
+	return nil
+}
+
+func (d *RedisTokenDenylist) IsDenied(ctx context.Context, sessionID string) (bool, error) {
+	exists, err := d.client.Exists(ctx, denylistKey(sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis denylist lookup failed: %w", err)
+	}
+	return exists == 1, nil
+}
+
+func denylistKey(sessionID string) string {
+	return fmt.Sprintf("token_denylist:session:%s", sessionID)
+}
@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	usagePendingKey  = "api_usage:pending"
+	usageFlushingKey = "api_usage:flushing"
+	usageBatchKey    = "api_usage:flushing_batch"
+	// Monthly counters outlive their month a little so late flushes and reports still see them
+	usageQuotaTTL = 40 * 24 * time.Hour
+)
+
+// consumeQuotaScript checks the quota, counts the request and buffers it for the flush in one step.
+// A missing monthly counter starts from the seed, or is reported with status -1 when the seed is negative,
+// so a counter lost with Redis is always rebuilt from MySQL.
+// KEYS[1] monthly counter, KEYS[2] pending buffer. ARGV[1] quota, ARGV[2] buffer field, ARGV[3] counter TTL in seconds,
+// ARGV[4] seed.
+var consumeQuotaScript = redis.NewScript(`
+local quota = tonumber(ARGV[1])
+local used = tonumber(redis.call('GET', KEYS[1]))
+if used == nil then
+  used = tonumber(ARGV[4])
+  if used < 0 then
+    return {-1, 0}
+  end
+  redis.call('SET', KEYS[1], used, 'EX', ARGV[3])
+end
+
+if quota > 0 and used >= quota then
+  return {0, used}
+end
+
+used = redis.call('INCR', KEYS[1])
+redis.call('HINCRBY', KEYS[2], ARGV[2], 1)
+return {1, used}
+`)
+
+// startDrainScript moves the pending buffer aside under a new batch ID, unless a buffer from a failed
+// drain is still waiting, which keeps its batch ID so storing it twice is noticed.
+// KEYS[1] pending, KEYS[2] flushing, KEYS[3] batch ID. ARGV[1] new batch ID.
+// Returns the batch ID, or false when there is nothing to drain.
+var startDrainScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+  if redis.call('EXISTS', KEYS[1]) == 0 then
+    return false
+  end
+  redis.call('RENAME', KEYS[1], KEYS[2])
+  redis.call('SET', KEYS[3], ARGV[1])
+  return ARGV[1]
+end
+redis.call('SET', KEYS[3], ARGV[1], 'NX')
+return redis.call('GET', KEYS[3])
+`)
+
+type RedisUsageMeter struct {
+	client *redis.Client
+}
+
+func NewRedisUsageMeter(client *redis.Client) domain.UsageMeter {
+	return &RedisUsageMeter{client: client}
+}
+
+func (m *RedisUsageMeter) Consume(ctx context.Context, clientID, endpoint string, at time.Time, quota, seed int64) (bool, int64, error) {
+	field := strings.Join([]string{clientID, at.UTC().Format("2006-01-02"), endpoint}, "|")
+	values, err := consumeQuotaScript.Run(ctx, m.client, []string{usageQuotaKey(clientID, at), usagePendingKey},
+		quota, field, int64(usageQuotaTTL.Seconds()), seed).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("redis usage metering failed: %w", err)
+	}
+	if len(values) != 2 {
+		return false, 0, fmt.Errorf("redis usage metering script returned %d values", len(values))
+	}
+	if values[0] == -1 {
+		return false, 0, domain.ErrNotFound
+	}
+
+	return values[0] == 1, values[1], nil
+}
+
+func (m *RedisUsageMeter) Drain(ctx context.Context, store func(batchID string, usage []domain.APIUsage) error) error {
+	batchID, err := startDrainScript.Run(ctx, m.client, []string{usagePendingKey, usageFlushingKey, usageBatchKey}, uuid.NewString()).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("redis usage drain failed: %w", err)
+	}
+
+	fields, err := m.client.HGetAll(ctx, usageFlushingKey).Result()
+	if err != nil {
+		return fmt.Errorf("redis usage drain failed: %w", err)
+	}
+
+	usage := make([]domain.APIUsage, 0, len(fields))
+	for field, count := range fields {
+		row, err := parseUsageField(field, count)
+		if err != nil {
+			return err
+		}
+		usage = append(usage, row)
+	}
+
+	if err := store(batchID, usage); err != nil {
+		return err
+	}
+
+	// The counts are stored. Should this fail, the next drain hands them over again under the same
+	// batch ID and the store skips them.
+	if err := m.client.Del(context.WithoutCancel(ctx), usageFlushingKey, usageBatchKey).Err(); err != nil {
+		return fmt.Errorf("redis usage drain cleanup failed: %w", err)
+	}
+	return nil
+}
+
+func parseUsageField(field, count string) (domain.APIUsage, error) {
+	parts := strings.SplitN(field, "|", 3)
+	if len(parts) != 3 {
+		return domain.APIUsage{}, fmt.Errorf("malformed usage field %q", field)
+	}
+
+	usageDate, dateErr := time.Parse("2006-01-02", parts[1])
+	requestCount, countErr := strconv.ParseInt(count, 10, 64)
+	if err := errors.Join(dateErr, countErr); err != nil {
+		return domain.APIUsage{}, fmt.Errorf("malformed usage field %q: %w", field, err)
+	}
+
+	return domain.APIUsage{
+		ClientID:     parts[0],
+		UsageDate:    usageDate,
+		Endpoint:     parts[2],
+		RequestCount: requestCount,
+	}, nil
+}
+
+func usageQuotaKey(clientID string, at time.Time) string {
+	return fmt.Sprintf("api_quota:%s:%s", clientID, at.UTC().Format("200601"))
+}
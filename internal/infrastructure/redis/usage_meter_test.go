@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisUsageMeter_Consume(t *testing.T) {
+	at := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		seed        int64
+		reply       []any
+		wantAllowed bool
+		wantUsed    int64
+		wantErr     error
+	}{
+		{
+			name:        "counted",
+			seed:        domain.UsageSeedUnknown,
+			reply:       []any{int64(1), int64(41)},
+			wantAllowed: true,
+			wantUsed:    41,
+		},
+		{
+			name:     "quota_used_up",
+			seed:     40,
+			reply:    []any{int64(0), int64(100)},
+			wantUsed: 100,
+		},
+		{
+			name:    "month_missing",
+			seed:    domain.UsageSeedUnknown,
+			reply:   []any{int64(-1), int64(0)},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := redis.NewClient(&redis.Options{Addr: "redis.invalid:6379"})
+			defer client.Close()
+			client.AddHook(scriptHook{reply: func(args []any) (any, error) {
+				// EVALSHA sha 2 counter pending quota field ttl seed
+				if len(args) != 9 || args[3] != "api_quota:client-1:202503" || args[5] != int64(100) || args[8] != tt.seed {
+					t.Errorf("Unexpected script call %v", args)
+				}
+				return tt.reply, nil
+			}})
+
+			allowed, used, err := NewRedisUsageMeter(client).Consume(context.Background(), "client-1", "GET /api/v1/customers", at, 100, tt.seed)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if allowed != tt.wantAllowed || used != tt.wantUsed {
+				t.Errorf("Expected allowed %v with %d used, got %v with %d", tt.wantAllowed, tt.wantUsed, allowed, used)
+			}
+		})
+	}
+}
+
+func TestConsumeQuotaScript(t *testing.T) {
+	client := newTestClient(t)
+	meter := NewRedisUsageMeter(client)
+	ctx := context.Background()
+	at := time.Now()
+	cleanup := func(clientID string) {
+		client.Del(ctx, usageQuotaKey(clientID, at))
+		client.HDel(ctx, usagePendingKey, clientID+"|"+at.UTC().Format("2006-01-02")+"|GET /test")
+	}
+
+	// Test case 1: A missing month is reported without creating the counter, then starts from the seed
+	t.Run("missing_month_seeded", func(t *testing.T) {
+		clientID := uuid.NewString()
+		defer cleanup(clientID)
+
+		if _, _, err := meter.Consume(ctx, clientID, "GET /test", at, 100, domain.UsageSeedUnknown); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+		if exists := client.Exists(ctx, usageQuotaKey(clientID, at)).Val(); exists != 0 {
+			t.Fatal("Expected no counter without a seed")
+		}
+
+		allowed, used, err := meter.Consume(ctx, clientID, "GET /test", at, 100, 40)
+		if err != nil || !allowed || used != 41 {
+			t.Fatalf("Expected the 41st request to be counted, got %v, %d, %v", allowed, used, err)
+		}
+		if ttl := client.TTL(ctx, usageQuotaKey(clientID, at)).Val(); ttl <= 0 {
+			t.Errorf("Expected the counter to expire, got TTL %s", ttl)
+		}
+	})
+
+	// Test case 2: An existing month ignores the seed and stops at the quota
+	t.Run("existing_month_keeps_count", func(t *testing.T) {
+		clientID := uuid.NewString()
+		defer cleanup(clientID)
+		meter.Consume(ctx, clientID, "GET /test", at, 2, 0)
+
+		if _, used, _ := meter.Consume(ctx, clientID, "GET /test", at, 2, 50); used != 2 {
+			t.Fatalf("Expected the seed to be ignored, got %d used", used)
+		}
+		if allowed, used, _ := meter.Consume(ctx, clientID, "GET /test", at, 2, domain.UsageSeedUnknown); allowed || used != 2 {
+			t.Errorf("Expected the quota to be used up, got %v with %d used", allowed, used)
+		}
+	})
+}
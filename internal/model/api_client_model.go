@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type CreateAPIClientRequest struct {
+	Name         string `json:"name" validate:"required,max=100"`
+	Plan         string `json:"plan" validate:"required,oneof=basic business enterprise"`
+	MonthlyQuota *int64 `json:"monthly_quota" validate:"omitempty,gte=0"` // Overrides the plan quota, 0 is unlimited
+}
+
+type APIClientResponse struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Plan         string    `json:"plan"`
+	MonthlyQuota int64     `json:"monthly_quota"`
+	Enabled      bool      `json:"enabled"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CreateAPIClientResponse is the only time the API key is shown
+type CreateAPIClientResponse struct {
+	APIClientResponse
+	APIKey string `json:"api_key"`
+}
+
+// QuotaStatus is reported to partners in the X-Quota-* headers
+type QuotaStatus struct {
+	Limit   int64 // Zero is unlimited
+	Used    int64
+	ResetAt time.Time
+}
+
+type APIUsageReportRequest struct {
+	From string `form:"from" validate:"required,datetime=2006-01-02"` // Inclusive, YYYY-MM-DD
+	To   string `form:"to" validate:"required,datetime=2006-01-02"`   // Inclusive, YYYY-MM-DD
+}
+
+type APIUsageReportResponse struct {
+	ClientID      string           `json:"client_id"`
+	Plan          string           `json:"plan"`
+	From          string           `json:"from"`
+	To            string           `json:"to"`
+	TotalRequests int64            `json:"total_requests"`
+	Days          []APIUsageDayRow `json:"days"`
+}
+
+type APIUsageDayRow struct {
+	Date          string           `json:"date"`
+	TotalRequests int64            `json:"total_requests"`
+	Endpoints     map[string]int64 `json:"endpoints"`
+}
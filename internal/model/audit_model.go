@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ListAuditLogsRequest struct {
+	EntityType string `form:"entity_type" validate:"omitempty,max=50"`
+	EntityID   string `form:"entity_id" validate:"omitempty,max=100"`
+	ActorID    string `form:"actor_id" validate:"omitempty,max=36"`
+	From       string `form:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // Inclusive, RFC 3339
+	To         string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`   // Exclusive, RFC 3339
+	Cursor     string `form:"cursor" validate:"omitempty,numeric"`
+	Limit      int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type AuditEntryResponse struct {
+	ID         uint64          `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id,omitempty"`
+	Role       string          `json:"role,omitempty"`
+	IPAddress  string          `json:"ip_address"`
+	RequestID  string          `json:"request_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type,omitempty"`
+	EntityID   string          `json:"entity_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Result     string          `json:"result"`
+	StatusCode int             `json:"status_code"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Hash       string          `json:"hash"`
+}
+
+type AuditLogListResponse struct {
+	Data       []AuditEntryResponse `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// AuditChainVerificationResponse reports the first entry whose hash or link does not match.
+// Truncating the newest entries cannot be seen from the chain alone, so HeadHash should be recorded elsewhere and compared.
+type AuditChainVerificationResponse struct {
+	Valid          bool   `json:"valid"`
+	EntriesChecked int    `json:"entries_checked"`
+	HeadHash       string `json:"head_hash,omitempty"`
+	BrokenAtID     uint64 `json:"broken_at_id,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
@@ -1,24 +1,86 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// Proves the staff password was correct, only accepted by the MFA verification endpoint
+	TokenTypeMFAPending = "mfa_pending"
+)
 
 type LoginRequest struct {
-	NIK      string `json:"nik" validate:"required,len=16"`
-	Password string `json:"password" validate:"required,min=8"`
+	NIK       string `json:"nik" validate:"required,len=16"`
+	Password  string `json:"password" validate:"required,min=8"`
+	UserAgent string `json:"-"` // Filled by the handler, recorded on the session
+	IPAddress string `json:"-"`
+}
+
+type StaffLoginRequest struct {
+	Login     string `json:"login" validate:"required,max=100"` // Username or email
+	Password  string `json:"password" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
+// LoginResponse carries only MFARequired and MFAToken while a staff login waits for its second factor
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresAt    int64  `json:"expires_at"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// StaffMFAVerifyRequest completes a staff login with either an authenticator code or a recovery code
+type StaffMFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=20"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+type ForgotPasswordRequest struct {
+	NIK string `json:"nik" validate:"required,len=16"`
+}
+
+type ResetPasswordRequest struct {
+	NIK         string `json:"nik" validate:"required,len=16"`
+	Code        string `json:"code" validate:"required,len=6,numeric"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SessionResponse struct {
+	ID              string     `json:"id"`
+	UserAgent       string     `json:"user_agent"`
+	IPAddress       string     `json:"ip_address"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+}
+
 type Claims struct {
-	CustomerID string `json:"customer_id"`
-	NIK        string `json:"nik"`
+	CustomerID  string   `json:"customer_id,omitempty"`
+	NIK         string   `json:"nik,omitempty"`
+	StaffID     string   `json:"staff_id,omitempty"`
+	BranchCode  string   `json:"branch_code,omitempty"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	TokenType   string   `json:"typ"`
+	SessionID   string   `json:"sid"`
+	MFAVerified bool     `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
@@ -3,17 +3,20 @@ package model
 import "time"
 
 type CustomerResponse struct {
-	ID          string    `json:"id"`
-	NIK         string    `json:"nik"`
-	FullName    string    `json:"full_name"`
-	LegalName   string    `json:"legal_name"`
-	BirthPlace  string    `json:"birth_place"`
-	BirthDate   time.Time `json:"birth_date"`
-	Salary      float64   `json:"salary"`
-	KTPPhoto    string    `json:"ktp_photo_url"`
-	SelfiePhoto string    `json:"selfie_photo_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	NIK           string    `json:"nik"`
+	FullName      string    `json:"full_name"`
+	LegalName     string    `json:"legal_name"`
+	BirthPlace    string    `json:"birth_place"`
+	BirthDate     time.Time `json:"birth_date"`
+	Salary        float64   `json:"salary"`
+	KTPPhoto      string    `json:"ktp_photo_url"`
+	SelfiePhoto   string    `json:"selfie_photo_url"`
+	KYCStatus     string    `json:"kyc_status"`
+	PhoneNumber   string    `json:"phone_number,omitempty"`
+	PhoneVerified bool      `json:"phone_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type RegisterCustomerRequest struct {
@@ -27,3 +30,31 @@ type RegisterCustomerRequest struct {
 	KTPPhoto    string  `json:"ktp_photo_url" validate:"omitempty,url"`
 	SelfiePhoto string  `json:"selfie_photo_url" validate:"omitempty,url"`
 }
+
+type RequestPhoneVerificationRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required,e164"` // E.164, e.g. +6281234567890
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type SearchCustomersRequest struct {
+	Name               string   `form:"name" validate:"omitempty,max=100"`
+	NIKPrefix          string   `form:"nik_prefix" validate:"omitempty,numeric,max=16"`
+	RegisteredFrom     string   `form:"registered_from" validate:"omitempty,datetime=2006-01-02"` // Inclusive, YYYY-MM-DD
+	RegisteredTo       string   `form:"registered_to" validate:"omitempty,datetime=2006-01-02"`   // Inclusive, YYYY-MM-DD
+	MinSalary          *float64 `form:"min_salary" validate:"omitempty,gte=0"`
+	MaxSalary          *float64 `form:"max_salary" validate:"omitempty,gte=0"`
+	KYCStatus          string   `form:"kyc_status" validate:"omitempty,oneof=pending verified rejected"`
+	HasActiveContracts *bool    `form:"has_active_contracts"`
+	SortBy             string   `form:"sort_by" validate:"omitempty,oneof=created_at full_name salary"`
+	SortOrder          string   `form:"sort_order" validate:"omitempty,oneof=asc desc"`
+	Cursor             string   `form:"cursor"`
+	Limit              int      `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CustomerListResponse struct {
+	Data       []CustomerResponse `json:"data"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
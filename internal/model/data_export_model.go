@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	NotRecorded []string   `json:"not_recorded"` // Requested data categories this system does not keep, so no export has them
+}
+
+type DataExportDownloadRequest struct {
+	Expires   int64  `form:"expires" validate:"required"`
+	Signature string `form:"signature" validate:"required,hexadecimal"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type ErasureCertificateResponse struct {
+	ID            string    `json:"id"`
+	CustomerID    string    `json:"customer_id"`
+	SubjectDigest string    `json:"subject_digest"`
+	ErasedFields  []string  `json:"erased_fields"`
+	ErasedAt      time.Time `json:"erased_at"`
+}
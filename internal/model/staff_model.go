@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type CreateStaffRequest struct {
+	Username   string `json:"username" validate:"required,alphanum,min=3,max=50"`
+	Email      string `json:"email" validate:"required,email,max=100"`
+	FullName   string `json:"full_name" validate:"required,max=100"`
+	Password   string `json:"password" validate:"required,min=12,max=72"` // bcrypt ignores anything past 72 bytes
+	Role       string `json:"role" validate:"required,oneof=credit_officer collector admin merchant"`
+	BranchCode string `json:"branch_code" validate:"required,max=20"`
+}
+
+type UpdateStaffStatusRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+type StaffResponse struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	FullName    string     `json:"full_name"`
+	Role        string     `json:"role"`
+	BranchCode  string     `json:"branch_code"`
+	Enabled     bool       `json:"enabled"`
+	MFAEnabled  bool       `json:"mfa_enabled"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // Render as a QR code for the authenticator app
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// RecoveryCodes are only ever shown once, when they are generated
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type CreateTransactionRequest struct {
 	CustomerID        string  `json:"customer_id" validate:"required,uuid"`
 	ContractNumber    string  `json:"contract_number" validate:"required,max=100"`
@@ -9,15 +11,18 @@ type CreateTransactionRequest struct {
 	InstallmentAmount float64 `json:"installment_amount" validate:"required,gt=0"`
 	InterestAmount    float64 `json:"interest_amount" validate:"required,gte=0"`
 	AssetName         string  `json:"asset_name" validate:"required,max=255"`
+	OTPCode           string  `json:"otp_code" validate:"omitempty,len=6,numeric"` // Required above the confirmation threshold
 }
 
 type TransactionResponse struct {
-	ID                string  `json:"id"`
-	CustomerID        string  `json:"customer_id"`
-	ContractNumber    string  `json:"contract_number"`
-	OTRAmount         float64 `json:"otr_amount"`
-	AdminFee          float64 `json:"admin_fee"`
-	InstallmentAmount float64 `json:"installment_amount"`
-	InterestAmount    float64 `json:"interest_amount"`
-	AssetName         string  `json:"asset_name"`
+	ID                string     `json:"id"`
+	CustomerID        string     `json:"customer_id"`
+	ContractNumber    string     `json:"contract_number"`
+	TenorMonths       int        `json:"tenor_months"`
+	OTRAmount         float64    `json:"otr_amount"`
+	AdminFee          float64    `json:"admin_fee"`
+	InstallmentAmount float64    `json:"installment_amount"`
+	InterestAmount    float64    `json:"interest_amount"`
+	AssetName         string     `json:"asset_name"`
+	MaturityDate      *time.Time `json:"maturity_date"`
 }
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type apiClientRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIClientRepository(db *gorm.DB) domain.APIClientRepository {
+	return &apiClientRepository{db: db}
+}
+
+func (r *apiClientRepository) Create(ctx context.Context, client *domain.APIClient) error {
+	client.ID = uuid.New().String()
+
+	if err := r.db.WithContext(ctx).Create(client).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiClientRepository) FindByID(ctx context.Context, id string) (*domain.APIClient, error) {
+	client := &domain.APIClient{}
+
+	result := r.db.WithContext(ctx).First(client, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get API client by ID: %w", result.Error)
+	}
+
+	return client, nil
+}
+
+func (r *apiClientRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIClient, error) {
+	client := &domain.APIClient{}
+
+	result := r.db.WithContext(ctx).First(client, "key_hash = ?", keyHash)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get API client by key: %w", result.Error)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type apiUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIUsageRepository(db *gorm.DB) domain.APIUsageRepository {
+	return &apiUsageRepository{db: db}
+}
+
+// Stored batch IDs only guard against a drain being replayed, which happens within minutes
+const usageBatchRetention = 7 * 24 * time.Hour
+
+func (r *apiUsageRepository) AddUsage(ctx context.Context, batchID string, usage []domain.APIUsage) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.APIUsageBatch{ID: batchID, FlushedAt: now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // Stored by an earlier drain whose cleanup failed
+		}
+
+		if len(usage) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"request_count": gorm.Expr("request_count + VALUES(request_count)"),
+				}),
+			}).Create(&usage).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("flushed_at < ?", now.Add(-usageBatchRetention)).Delete(&domain.APIUsageBatch{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add API usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiUsageRepository) FindByClient(ctx context.Context, clientID string, from, to time.Time) ([]domain.APIUsage, error) {
+	var usage []domain.APIUsage
+
+	result := r.db.WithContext(ctx).
+		Where("client_id = ? AND usage_date >= ? AND usage_date <= ?", clientID, from, to).
+		Order("usage_date ASC, endpoint ASC").
+		Find(&usage)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get API usage: %w", result.Error)
+	}
+
+	return usage, nil
+}
+
+func (r *apiUsageRepository) CountSince(ctx context.Context, clientID string, from time.Time) (int64, error) {
+	var total int64
+
+	result := r.db.WithContext(ctx).Model(&domain.APIUsage{}).
+		Select("COALESCE(SUM(request_count), 0)").
+		Where("client_id = ? AND usage_date >= ?", clientID, from).
+		Scan(&total)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count API usage: %w", result.Error)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the head row serializes appends, so two entries never share a parent. Migration 014 seeds a genesis
+		// entry: on an empty table there is no row to lock, and concurrent first appends could deadlock on gap locks.
+		head := &domain.AuditEntry{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id DESC").Limit(1).Take(head).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to read audit chain head: %w", err)
+		}
+
+		entry.PrevHash = head.Hash
+		entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		entry.Hash = entry.ComputeHash()
+
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to append audit entry: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *auditRepository) Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+
+	query := r.db.WithContext(ctx).Model(&domain.AuditEntry{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *auditRepository) FindAfter(ctx context.Context, afterID uint64, limit int) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+
+	if err := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to read audit chain: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type authSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthSessionRepository(db *gorm.DB) domain.AuthSessionRepository {
+	return &authSessionRepository{db: db}
+}
+
+func (r *authSessionRepository) Create(ctx context.Context, session *domain.AuthSession) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create auth session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *authSessionRepository) FindByID(ctx context.Context, id string) (*domain.AuthSession, error) {
+	session := &domain.AuthSession{}
+
+	result := r.db.WithContext(ctx).First(session, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get auth session by ID: %w", result.Error)
+	}
+
+	return session, nil
+}
+
+func (r *authSessionRepository) Rotate(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	// Compare-and-swap, two concurrent refreshes with the same token cannot both win
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.AuthSession{}).
+		Where("id = ? AND current_token_id = ? AND revoked_at IS NULL", id, currentTokenID).
+		Updates(map[string]interface{}{
+			"current_token_id":  nextTokenID,
+			"expires_at":        expiresAt,
+			"last_refreshed_at": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to rotate auth session: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *authSessionRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&domain.AuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke auth session: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (r *authSessionRepository) FindActiveBySubject(ctx context.Context, subjectType, subjectID string, now time.Time) ([]domain.AuthSession, error) {
+	var sessions []domain.AuthSession
+
+	result := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject_id = ? AND revoked_at IS NULL AND expires_at > ?", subjectType, subjectID, now).
+		Order("created_at DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get active auth sessions: %w", result.Error)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+	"xyz-multifinance-api/internal/infrastructure/cache"
+)
+
+// Rows are cached for an hour, spread by ±10% so keys filled together do not expire together.
+// Missing rows are remembered briefly so lookups of unknown IDs do not all reach MySQL.
+var entityCacheConfig = cache.AsideConfig{
+	TTL:         time.Hour,
+	NegativeTTL: 30 * time.Second,
+	Jitter:      0.1,
+}
@@ -1,29 +1,46 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 	"xyz-multifinance-api/internal/domain"
-	"xyz-multifinance-api/pkg/utils"
+	"xyz-multifinance-api/internal/infrastructure/cache"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Cached form of domain.CreditLimit. Bump creditLimitCacheVersion whenever the fields change.
+type creditLimitCacheEntry struct {
+	ID          string    `json:"id"`
+	CustomerID  string    `json:"customer_id"`
+	TenorMonths int       `json:"tenor_months"`
+	LimitAmount float64   `json:"limit_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const creditLimitCacheVersion = 1
+
 type creditLimitRepository struct {
-	db         *gorm.DB
-	cacheStore domain.CacheStore
+	db           *gorm.DB
+	creditLimits *cache.Aside[domain.CreditLimit]
 }
 
-func NewCreditLimitRepository(db *gorm.DB, cacheStore domain.CacheStore) domain.CreditLimitRepository {
-	return &creditLimitRepository{db: db, cacheStore: cacheStore}
+func NewCreditLimitRepository(db *gorm.DB, cacheStore domain.CacheStore, cachePayloads *cache.Payloads) domain.CreditLimitRepository {
+	codec := cache.NewCodec(cachePayloads, cache.CodecConfig{Version: creditLimitCacheVersion}, toCreditLimitCacheEntry, fromCreditLimitCacheEntry)
+	return &creditLimitRepository{db: db, creditLimits: cache.NewAside(cacheStore, "credit_limit", entityCacheConfig, codec, func(creditLimit *domain.CreditLimit) []string {
+		return []string{domain.CustomerCacheTag(creditLimit.CustomerID)}
+	})}
 }
 
-func (r *creditLimitRepository) CreateCreditLimit(creditLimit *domain.CreditLimit) error {
+func (r *creditLimitRepository) CreateCreditLimit(ctx context.Context, creditLimit *domain.CreditLimit) error {
 	creditLimit.ID = uuid.New().String()
 
-	result := r.db.Create(creditLimit)
+	result := r.db.WithContext(ctx).Create(creditLimit)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
 			return domain.ErrAlreadyExists
@@ -31,49 +48,44 @@ func (r *creditLimitRepository) CreateCreditLimit(creditLimit *domain.CreditLimi
 		return fmt.Errorf("failed to create credit limit: %w", result.Error)
 	}
 
-	creditLimitJSON, err := utils.MarshalJSON(creditLimit)
-	if err != nil {
-		return fmt.Errorf("failed to marshal credit limit for cache: %w", err)
-	}
-
-	// Cache for 1 hour
-	r.cacheStore.Set(fmt.Sprintf("credit_limit:%s:%d", creditLimit.CustomerID, creditLimit.TenorMonths), creditLimitJSON, time.Hour)
+	// Also replaces a remembered miss for this tenor
+	r.creditLimits.Set(ctx, fmt.Sprintf("credit_limit:%s:%d", creditLimit.CustomerID, creditLimit.TenorMonths), creditLimit)
 
 	return nil
 }
 
-func (r *creditLimitRepository) GetCreditLimitByCustomerAndTenor(customerID string, tenorMonths int) (*domain.CreditLimit, error) {
-	// Try to get from cache first
+func (r *creditLimitRepository) GetCreditLimitByCustomerAndTenor(ctx context.Context, customerID string, tenorMonths int) (*domain.CreditLimit, error) {
 	cacheKey := fmt.Sprintf("credit_limit:%s:%d", customerID, tenorMonths)
-	if cachedLimitJSON, err := r.cacheStore.Get(cacheKey); err == nil {
+	return r.creditLimits.Get(ctx, cacheKey, func() (*domain.CreditLimit, error) {
 		creditLimit := &domain.CreditLimit{}
-		if err := utils.UnmarshalJSON(cachedLimitJSON, creditLimit); err == nil {
-			return creditLimit, nil
+		result := r.db.WithContext(ctx).Where("customer_id = ? AND tenor_months = ?", customerID, tenorMonths).First(creditLimit)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, domain.ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get credit limit from DB: %w", result.Error)
 		}
-		fmt.Printf("Warning: Failed to unmarshal cached credit limit %s:%d: %v. Fetching from DB.\n", customerID, tenorMonths, err)
-	}
+		return creditLimit, nil
+	})
+}
 
+// GetCreditLimitForUpdate skips the cache, a cached row could be older than the one being locked
+func (r *creditLimitRepository) GetCreditLimitForUpdate(ctx context.Context, customerID string, tenorMonths int) (*domain.CreditLimit, error) {
 	creditLimit := &domain.CreditLimit{}
-	result := r.db.Where("customer_id = ? AND tenor_months = ?", customerID, tenorMonths).First(creditLimit)
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ? AND tenor_months = ?", customerID, tenorMonths).
+		First(creditLimit)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to get credit limit from DB: %w", result.Error)
-	}
-
-	// Cache the result from DB
-	creditLimitJSON, err := utils.MarshalJSON(creditLimit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal credit limit for cache: %w", err)
+		return nil, fmt.Errorf("failed to get credit limit with lock: %w", result.Error)
 	}
-	r.cacheStore.Set(cacheKey, creditLimitJSON, time.Hour)
-
 	return creditLimit, nil
 }
 
-func (r *creditLimitRepository) UpdateCreditLimit(creditLimit *domain.CreditLimit) error {
-	result := r.db.Save(creditLimit)
+func (r *creditLimitRepository) UpdateCreditLimit(ctx context.Context, creditLimit *domain.CreditLimit) error {
+	result := r.db.WithContext(ctx).Save(creditLimit)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update credit limit: %w", result.Error)
 	}
@@ -82,16 +94,38 @@ func (r *creditLimitRepository) UpdateCreditLimit(creditLimit *domain.CreditLimi
 	}
 
 	// Delete cache after update
-	r.cacheStore.Del(fmt.Sprintf("credit_limit:%s:%d", creditLimit.CustomerID, creditLimit.TenorMonths))
+	r.creditLimits.Del(ctx, fmt.Sprintf("credit_limit:%s:%d", creditLimit.CustomerID, creditLimit.TenorMonths))
 
 	return nil
 }
 
-func (r *creditLimitRepository) GetCreditLimitsByCustomerID(customerID string) ([]domain.CreditLimit, error) {
+func (r *creditLimitRepository) GetCreditLimitsByCustomerID(ctx context.Context, customerID string) ([]domain.CreditLimit, error) {
 	var creditLimits []domain.CreditLimit
-	result := r.db.Where("customer_id = ?", customerID).Find(&creditLimits)
+	result := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Find(&creditLimits)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get credit limits by customer ID: %w", result.Error)
 	}
 	return creditLimits, nil
 }
+
+func toCreditLimitCacheEntry(creditLimit *domain.CreditLimit) creditLimitCacheEntry {
+	return creditLimitCacheEntry{
+		ID:          creditLimit.ID,
+		CustomerID:  creditLimit.CustomerID,
+		TenorMonths: creditLimit.TenorMonths,
+		LimitAmount: creditLimit.LimitAmount,
+		CreatedAt:   creditLimit.CreatedAt,
+		UpdatedAt:   creditLimit.UpdatedAt,
+	}
+}
+
+func fromCreditLimitCacheEntry(entry *creditLimitCacheEntry) domain.CreditLimit {
+	return domain.CreditLimit{
+		ID:          entry.ID,
+		CustomerID:  entry.CustomerID,
+		TenorMonths: entry.TenorMonths,
+		LimitAmount: entry.LimitAmount,
+		CreatedAt:   entry.CreatedAt,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+}
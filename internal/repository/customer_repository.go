@@ -1,101 +1,280 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 	"xyz-multifinance-api/internal/domain"
-	"xyz-multifinance-api/pkg/utils"
+	"xyz-multifinance-api/internal/infrastructure/cache"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Cached form of domain.Customer. The password hash is left out, so FindByID and FindByNIK never load it.
+// Bump customerCacheVersion whenever the fields change.
+type customerCacheEntry struct {
+	ID              string     `json:"id"`
+	NIK             string     `json:"nik"`
+	FullName        string     `json:"full_name"`
+	LegalName       string     `json:"legal_name"`
+	BirthPlace      string     `json:"birth_place"`
+	BirthDate       time.Time  `json:"birth_date"`
+	Salary          float64    `json:"salary"`
+	KTPPhoto        string     `json:"ktp_photo"`
+	SelfiePhoto     string     `json:"selfie_photo"`
+	KYCStatus       string     `json:"kyc_status"`
+	PhoneNumber     string     `json:"phone_number"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+	ErasedAt        *time.Time `json:"erased_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+const customerCacheVersion = 1
+
 type customerRepository struct {
-	db         *gorm.DB
-	cacheStore domain.CacheStore
+	db        *gorm.DB
+	customers *cache.Aside[domain.Customer]
 }
 
-func NewCustomerRepository(db *gorm.DB, cacheStore domain.CacheStore) domain.CustomerRepository {
-	return &customerRepository{db, cacheStore}
+func NewCustomerRepository(db *gorm.DB, cacheStore domain.CacheStore, cachePayloads *cache.Payloads) domain.CustomerRepository {
+	codec := cache.NewCodec(cachePayloads, cache.CodecConfig{Version: customerCacheVersion, Sensitive: true}, toCustomerCacheEntry, fromCustomerCacheEntry)
+	return &customerRepository{db, cache.NewAside(cacheStore, "customer", entityCacheConfig, codec, func(customer *domain.Customer) []string {
+		return []string{domain.CustomerCacheTag(customer.ID)}
+	})}
 }
 
-func (r *customerRepository) Create(customer *domain.Customer) error {
+func (r *customerRepository) Create(ctx context.Context, customer *domain.Customer) error {
 	customer.ID = uuid.New().String()
 
-	if err := r.db.Create(customer).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(customer).Error; err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return domain.ErrAlreadyExists
 		}
 		return fmt.Errorf("failed to create customer: %w", err)
 	}
 
-	customerJSON, err := utils.MarshalJSON(customer)
-	if err != nil {
-		return fmt.Errorf("failed to marshal customer for cache: %w", err)
-	}
-
-	// Cache for 1 hour
-	r.cacheStore.Set(fmt.Sprintf("customer:%s", customer.ID), customerJSON, time.Hour)
-	r.cacheStore.Set(fmt.Sprintf("customer_nik:%s", customer.NIK), customerJSON, time.Hour)
+	// Also replaces a remembered miss for this NIK
+	r.customers.Set(ctx, fmt.Sprintf("customer:%s", customer.ID), customer)
+	r.customers.Set(ctx, fmt.Sprintf("customer_nik:%s", customer.NIK), customer)
 
 	return nil
 }
 
-func (r *customerRepository) FindByID(id string) (*domain.Customer, error) {
-	cacheKey := fmt.Sprintf("customer:%s", id)
-	if cachedCustomerJSON, err := r.cacheStore.Get(cacheKey); err == nil {
+func (r *customerRepository) FindByID(ctx context.Context, id string) (*domain.Customer, error) {
+	return r.customers.Get(ctx, fmt.Sprintf("customer:%s", id), func() (*domain.Customer, error) {
 		customer := &domain.Customer{}
-		if err := utils.UnmarshalJSON(cachedCustomerJSON, customer); err == nil {
-			return customer, nil // Cache hit
+		result := r.db.WithContext(ctx).Omit("Password").First(customer, "id = ?", id)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, domain.ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get customer by ID from DB: %w", result.Error)
 		}
-		fmt.Printf("Warning: Failed to unmarshal cached customer %s: %v. Fetching from DB.\n", id, err)
-	}
 
+		r.customers.Set(ctx, fmt.Sprintf("customer_nik:%s", customer.NIK), customer) // Also cache by NIK
+		return customer, nil
+	})
+}
+
+// FindByIDForUpdate skips the cache, a cached row could be older than the one being locked
+func (r *customerRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.Customer, error) {
 	customer := &domain.Customer{}
-	result := r.db.First(customer, "id = ?", id)
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Omit("Password").First(customer, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to get customer by ID from DB: %w", result.Error)
+		return nil, fmt.Errorf("failed to get customer by ID with lock from DB: %w", result.Error)
 	}
-
-	customerJSON, err := utils.MarshalJSON(customer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal customer for cache: %w", err)
-	}
-	r.cacheStore.Set(cacheKey, customerJSON, time.Hour)
-	r.cacheStore.Set(fmt.Sprintf("customer_nik:%s", customer.NIK), customerJSON, time.Hour) // Also cache by NIK
-
 	return customer, nil
 }
 
-func (r *customerRepository) FindByNIK(nik string) (*domain.Customer, error) {
-	cacheKey := fmt.Sprintf("customer_nik:%s", nik)
-	if cachedCustomerJSON, err := r.cacheStore.Get(cacheKey); err == nil {
+func (r *customerRepository) FindByNIK(ctx context.Context, nik string) (*domain.Customer, error) {
+	return r.customers.Get(ctx, fmt.Sprintf("customer_nik:%s", nik), func() (*domain.Customer, error) {
 		customer := &domain.Customer{}
-		if err := utils.UnmarshalJSON(cachedCustomerJSON, customer); err == nil {
-			return customer, nil // Cache hit
+		result := r.db.WithContext(ctx).Omit("Password").First(customer, "nik = ?", nik)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, domain.ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get customer by NIK from DB: %w", result.Error)
 		}
-		fmt.Printf("Warning: Failed to unmarshal cached customer by NIK %s: %v. Fetching from DB.\n", nik, err)
-	}
 
+		r.customers.Set(ctx, fmt.Sprintf("customer:%s", customer.ID), customer) // Also cache by ID
+		return customer, nil
+	})
+}
+
+// FindCredentialsByNIK skips the cache, which never holds password hashes
+func (r *customerRepository) FindCredentialsByNIK(ctx context.Context, nik string) (*domain.Customer, error) {
 	customer := &domain.Customer{}
-	result := r.db.First(customer, "nik = ?", nik)
+	result := r.db.WithContext(ctx).First(customer, "nik = ?", nik)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to get customer by NIK from DB: %w", result.Error)
+		return nil, fmt.Errorf("failed to get customer credentials by NIK from DB: %w", result.Error)
+	}
+	return customer, nil
+}
+
+// Update leaves the password column alone, customers read from the cache have no password hash
+func (r *customerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	result := r.db.WithContext(ctx).Omit("Password").Save(customer)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to update customer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
 	}
 
-	customerJSON, err := utils.MarshalJSON(customer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal customer for cache: %w", err)
+	// Delete cache after update
+	r.customers.Del(ctx, fmt.Sprintf("customer:%s", customer.ID), fmt.Sprintf("customer_nik:%s", customer.NIK))
+
+	return nil
+}
+
+func (r *customerRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Customer{}).Where("id = ?", id).Update("password", passwordHash)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update customer password: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
 	}
-	r.cacheStore.Set(cacheKey, customerJSON, time.Hour)
-	r.cacheStore.Set(fmt.Sprintf("customer:%s", customer.ID), customerJSON, time.Hour) // Also cache by ID
+	return nil
+}
 
-	return customer, nil
+// Matches transactionRepository.CountActiveByCustomerID
+const activeContractSubQuery = "SELECT 1 FROM transactions WHERE transactions.customer_id = customers.id AND transactions.maturity_date > ?"
+
+// likeEscaper makes user input match literally in a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+func (r *customerRepository) Search(ctx context.Context, filter domain.CustomerFilter) ([]domain.Customer, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Customer{})
+
+	if filter.Name != "" {
+		pattern := "%" + escapeLike(filter.Name) + "%"
+		query = query.Where(`(full_name LIKE ? ESCAPE '\\' OR legal_name LIKE ? ESCAPE '\\')`, pattern, pattern)
+	}
+	if filter.NIKPrefix != "" {
+		query = query.Where(`nik LIKE ? ESCAPE '\\'`, escapeLike(filter.NIKPrefix)+"%")
+	}
+	if filter.RegisteredFrom != nil {
+		query = query.Where("created_at >= ?", *filter.RegisteredFrom)
+	}
+	if filter.RegisteredTo != nil {
+		query = query.Where("created_at < ?", *filter.RegisteredTo)
+	}
+	if filter.MinSalary != nil {
+		query = query.Where("salary >= ?", *filter.MinSalary)
+	}
+	if filter.MaxSalary != nil {
+		query = query.Where("salary <= ?", *filter.MaxSalary)
+	}
+	if filter.KYCStatus != "" {
+		query = query.Where("kyc_status = ?", filter.KYCStatus)
+	}
+	if filter.HasActiveContracts != nil {
+		if *filter.HasActiveContracts {
+			query = query.Where("EXISTS ("+activeContractSubQuery+")", filter.ActiveAsOf)
+		} else {
+			query = query.Where("NOT EXISTS ("+activeContractSubQuery+")", filter.ActiveAsOf)
+		}
+	}
+
+	sortColumn := "created_at"
+	var cursorValue interface{}
+	if filter.After != nil {
+		cursorValue = filter.After.CreatedAt
+	}
+	switch filter.SortBy {
+	case "full_name":
+		sortColumn = "full_name"
+		if filter.After != nil {
+			cursorValue = filter.After.FullName
+		}
+	case "salary":
+		sortColumn = "salary"
+		if filter.After != nil {
+			cursorValue = filter.After.Salary
+		}
+	}
+
+	direction, comparator := "ASC", ">"
+	if filter.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	// ID breaks ties so rows sharing a sort value are never skipped or repeated
+	if filter.After != nil {
+		query = query.Where(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", sortColumn, comparator, sortColumn, comparator),
+			cursorValue, cursorValue, filter.After.ID,
+		)
+	}
+
+	var customers []domain.Customer
+	result := query.
+		Order(fmt.Sprintf("%s %s, id %s", sortColumn, direction, direction)).
+		Limit(filter.Limit).
+		Find(&customers)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to search customers: %w", result.Error)
+	}
+
+	return customers, nil
+}
+
+func toCustomerCacheEntry(customer *domain.Customer) customerCacheEntry {
+	return customerCacheEntry{
+		ID:              customer.ID,
+		NIK:             customer.NIK,
+		FullName:        customer.FullName,
+		LegalName:       customer.LegalName,
+		BirthPlace:      customer.BirthPlace,
+		BirthDate:       customer.BirthDate,
+		Salary:          customer.Salary,
+		KTPPhoto:        customer.KTPPhoto,
+		SelfiePhoto:     customer.SelfiePhoto,
+		KYCStatus:       customer.KYCStatus,
+		PhoneNumber:     customer.PhoneNumber,
+		PhoneVerifiedAt: customer.PhoneVerifiedAt,
+		ErasedAt:        customer.ErasedAt,
+		CreatedAt:       customer.CreatedAt,
+		UpdatedAt:       customer.UpdatedAt,
+	}
+}
+
+func fromCustomerCacheEntry(entry *customerCacheEntry) domain.Customer {
+	return domain.Customer{
+		ID:              entry.ID,
+		NIK:             entry.NIK,
+		FullName:        entry.FullName,
+		LegalName:       entry.LegalName,
+		BirthPlace:      entry.BirthPlace,
+		BirthDate:       entry.BirthDate,
+		Salary:          entry.Salary,
+		KTPPhoto:        entry.KTPPhoto,
+		SelfiePhoto:     entry.SelfiePhoto,
+		KYCStatus:       entry.KYCStatus,
+		PhoneNumber:     entry.PhoneNumber,
+		PhoneVerifiedAt: entry.PhoneVerifiedAt,
+		ErasedAt:        entry.ErasedAt,
+		CreatedAt:       entry.CreatedAt,
+		UpdatedAt:       entry.UpdatedAt,
+	}
 }
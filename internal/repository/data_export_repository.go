@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) domain.DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+func (r *dataExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	export.ID = uuid.New().String()
+
+	if err := r.db.WithContext(ctx).Create(export).Error; err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) FindByID(ctx context.Context, id string) (*domain.DataExport, error) {
+	export := &domain.DataExport{}
+
+	result := r.db.WithContext(ctx).First(export, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get data export by ID: %w", result.Error)
+	}
+
+	return export, nil
+}
+
+func (r *dataExportRepository) Update(ctx context.Context, export *domain.DataExport) error {
+	result := r.db.WithContext(ctx).Save(export)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update data export: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) FindUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND updated_at < ?", []string{domain.DataExportStatusPending, domain.DataExportStatusProcessing}, updatedBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&exports)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get unfinished data exports: %w", result.Error)
+	}
+
+	return exports, nil
+}
+
+func (r *dataExportRepository) FindExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", domain.DataExportStatusCompleted, expiredBefore).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get expired data exports: %w", result.Error)
+	}
+
+	return exports, nil
+}
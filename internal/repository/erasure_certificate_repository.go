@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type erasureCertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewErasureCertificateRepository(db *gorm.DB) domain.ErasureCertificateRepository {
+	return &erasureCertificateRepository{db: db}
+}
+
+func (r *erasureCertificateRepository) Create(ctx context.Context, certificate *domain.ErasureCertificate) error {
+	certificate.ID = uuid.New().String()
+
+	result := r.db.WithContext(ctx).Create(certificate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+
+		return fmt.Errorf("failed to create erasure certificate: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (r *erasureCertificateRepository) FindByCustomerID(ctx context.Context, customerID string) (*domain.ErasureCertificate, error) {
+	certificate := &domain.ErasureCertificate{}
+
+	result := r.db.WithContext(ctx).Where("customer_id = ?", customerID).First(certificate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get erasure certificate by customer ID: %w", result.Error)
+	}
+
+	return certificate, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type staffRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffRecoveryCodeRepository(db *gorm.DB) domain.StaffRecoveryCodeRepository {
+	return &staffRecoveryCodeRepository{db: db}
+}
+
+func (r *staffRecoveryCodeRepository) Replace(ctx context.Context, staffID string, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("staff_id = ?", staffID).Delete(&domain.StaffRecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]domain.StaffRecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, domain.StaffRecoveryCode{
+				ID:       uuid.New().String(),
+				StaffID:  staffID,
+				CodeHash: codeHash,
+			})
+		}
+		if err := tx.Create(&codes).Error; err != nil {
+			return fmt.Errorf("failed to create recovery codes: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *staffRecoveryCodeRepository) Consume(ctx context.Context, staffID, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.StaffRecoveryCode{}).
+		Where("staff_id = ? AND code_hash = ? AND used_at IS NULL", staffID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type staffUserRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffUserRepository(db *gorm.DB) domain.StaffUserRepository {
+	return &staffUserRepository{db: db}
+}
+
+func (r *staffUserRepository) Create(ctx context.Context, staff *domain.StaffUser) error {
+	staff.ID = uuid.New().String()
+
+	if err := r.db.WithContext(ctx).Create(staff).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create staff user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *staffUserRepository) FindByID(ctx context.Context, id string) (*domain.StaffUser, error) {
+	staff := &domain.StaffUser{}
+
+	result := r.db.WithContext(ctx).First(staff, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get staff user by ID: %w", result.Error)
+	}
+
+	return staff, nil
+}
+
+func (r *staffUserRepository) FindByLogin(ctx context.Context, login string) (*domain.StaffUser, error) {
+	staff := &domain.StaffUser{}
+
+	result := r.db.WithContext(ctx).Where("username = ? OR email = ?", login, login).First(staff)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get staff user by login: %w", result.Error)
+	}
+
+	return staff, nil
+}
+
+func (r *staffUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ? AND enabled = ?", id, true).
+		UpdateColumns(map[string]interface{}{"last_login_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record staff login: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *staffUserRepository) SetEnabled(ctx context.Context, id string, enabled bool) error {
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"enabled": enabled, "updated_at": time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update staff user status: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (r *staffUserRepository) EnrollMFA(ctx context.Context, id, secret string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ? AND mfa_enabled_at IS NULL", id).
+		UpdateColumns(map[string]interface{}{"mfa_secret": secret, "mfa_last_step": 0, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to store MFA secret: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *staffUserRepository) ActivateMFA(ctx context.Context, id, secret string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ? AND mfa_secret = ? AND mfa_enabled_at IS NULL", id, secret).
+		UpdateColumns(map[string]interface{}{"mfa_enabled_at": at, "updated_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to activate MFA: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *staffUserRepository) ResetMFA(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"mfa_secret": "", "mfa_enabled_at": nil, "mfa_last_step": 0, "updated_at": time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to reset MFA: %w", result.Error)
+	}
+
+	return nil
+}
+
+func (r *staffUserRepository) ClaimMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	// Conditional update, the same code cannot be accepted twice even by concurrent requests
+	result := r.db.WithContext(ctx).Model(&domain.StaffUser{}).
+		Where("id = ? AND mfa_last_step < ?", id, step).
+		Update("mfa_last_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record MFA step: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 	"xyz-multifinance-api/internal/domain"
 
 	"github.com/google/uuid"
@@ -17,10 +19,10 @@ func NewTransactionRepository(db *gorm.DB) domain.TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-func (r *transactionRepository) CreateTransaction(transaction *domain.Transaction) error {
+func (r *transactionRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
 	transaction.ID = uuid.New().String()
 
-	result := r.db.Create(transaction)
+	result := r.db.WithContext(ctx).Create(transaction)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
 			return domain.ErrAlreadyExists
@@ -32,10 +34,10 @@ func (r *transactionRepository) CreateTransaction(transaction *domain.Transactio
 	return nil
 }
 
-func (r *transactionRepository) GetTransactionByContractNumber(contractNumber string) (*domain.Transaction, error) {
+func (r *transactionRepository) GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*domain.Transaction, error) {
 	transaction := &domain.Transaction{}
 
-	result := r.db.Where("contract_number = ?", contractNumber).First(transaction)
+	result := r.db.WithContext(ctx).Where("contract_number = ?", contractNumber).First(transaction)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
@@ -47,13 +49,27 @@ func (r *transactionRepository) GetTransactionByContractNumber(contractNumber st
 	return transaction, nil
 }
 
-func (r *transactionRepository) GetTransactionsByCustomerID(customerID string) ([]domain.Transaction, error) {
+func (r *transactionRepository) GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]domain.Transaction, error) {
 	var transactions []domain.Transaction
 
-	result := r.db.Where("customer_id = ?", customerID).Find(&transactions)
+	result := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Find(&transactions)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get transactions by customer ID: %w", result.Error)
 	}
 
 	return transactions, nil
 }
+
+// Contracts created before tenor tracking got their maturity date from migration 012.
+func (r *transactionRepository) CountActiveByCustomerID(ctx context.Context, customerID string, asOf time.Time) (int64, error) {
+	var count int64
+
+	result := r.db.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("customer_id = ? AND maturity_date > ?", customerID, asOf).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count active transactions: %w", result.Error)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/infrastructure/cache"
+
+	"gorm.io/gorm"
+)
+
+type unitOfWork struct {
+	db            *gorm.DB
+	cacheStore    domain.CacheStore
+	cachePayloads *cache.Payloads
+}
+
+func NewUnitOfWork(db *gorm.DB, cacheStore domain.CacheStore, cachePayloads *cache.Payloads) domain.UnitOfWork {
+	return &unitOfWork{db: db, cacheStore: cacheStore, cachePayloads: cachePayloads}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos domain.TxRepositories) error) error {
+	// Cache changes made inside the transaction only reach the store once it has committed
+	cacheBuffer := cache.NewTxBuffer(u.cacheStore)
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&txRepositories{
+			customers:           NewCustomerRepository(tx, cacheBuffer, u.cachePayloads),
+			creditLimits:        NewCreditLimitRepository(tx, cacheBuffer, u.cachePayloads),
+			transactions:        NewTransactionRepository(tx),
+			erasureCertificates: NewErasureCertificateRepository(tx),
+		})
+	})
+	if err != nil {
+		cacheBuffer.Discard()
+		return err
+	}
+
+	// The database has committed, so the cache has to follow even when the caller has gone away meanwhile
+	cacheBuffer.Commit(context.WithoutCancel(ctx))
+	return nil
+}
+
+type txRepositories struct {
+	customers           domain.CustomerRepository
+	creditLimits        domain.CreditLimitRepository
+	transactions        domain.TransactionRepository
+	erasureCertificates domain.ErasureCertificateRepository
+}
+
+func (r *txRepositories) Customers() domain.CustomerRepository {
+	return r.customers
+}
+
+func (r *txRepositories) CreditLimits() domain.CreditLimitRepository {
+	return r.creditLimits
+}
+
+func (r *txRepositories) Transactions() domain.TransactionRepository {
+	return r.transactions
+}
+
+func (r *txRepositories) ErasureCertificates() domain.ErasureCertificateRepository {
+	return r.erasureCertificates
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	apiKeyPrefix = "xyz_"
+	// Disabling a client or changing its quota takes effect on every instance within this time
+	apiClientCacheTTL = time.Minute
+	// Billing reports cover at most a quarter per request
+	maxUsageReportDays = 92
+	usageFlushLease    = time.Minute
+)
+
+type APIClientUseCase interface {
+	CreateClient(ctx context.Context, req *model.CreateAPIClientRequest) (*model.CreateAPIClientResponse, error)
+	// Meter identifies the client by API key and counts the request against its monthly quota
+	Meter(ctx context.Context, apiKey, endpoint string) (*domain.APIClient, *model.QuotaStatus, error)
+	GetUsageReport(ctx context.Context, clientID string, req *model.APIUsageReportRequest) (*model.APIUsageReportResponse, error)
+	FlushUsage(ctx context.Context) error
+	RunUsageFlusher(stop <-chan struct{})
+}
+
+type cachedAPIClient struct {
+	client   *domain.APIClient
+	loadedAt time.Time
+}
+
+type apiClientUseCase struct {
+	clientRepo domain.APIClientRepository
+	usageRepo  domain.APIUsageRepository
+	meter      domain.UsageMeter
+	locker     domain.Locker
+	cfg        *config.Config
+	validator  *validator.Validate
+
+	mu      sync.Mutex
+	clients map[string]cachedAPIClient // By key hash
+}
+
+func NewAPIClientUseCase(clientRepo domain.APIClientRepository, usageRepo domain.APIUsageRepository, meter domain.UsageMeter, locker domain.Locker, cfg *config.Config) APIClientUseCase {
+	return &apiClientUseCase{
+		clientRepo: clientRepo,
+		usageRepo:  usageRepo,
+		meter:      meter,
+		locker:     locker,
+		cfg:        cfg,
+		validator:  validator.New(),
+		clients:    make(map[string]cachedAPIClient),
+	}
+}
+
+func (uc *apiClientUseCase) CreateClient(ctx context.Context, req *model.CreateAPIClientRequest) (*model.CreateAPIClientResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	quota, _ := domain.MonthlyQuotaForPlan(req.Plan) // Plan already validated
+	if req.MonthlyQuota != nil {
+		quota = *req.MonthlyQuota
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("%w: failed to generate API key: %v", domain.ErrInternalServerError, err)
+	}
+	apiKey := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	client := &domain.APIClient{
+		Name:         req.Name,
+		KeyHash:      hashAPIKey(apiKey),
+		Plan:         req.Plan,
+		MonthlyQuota: quota,
+		Enabled:      true,
+	}
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return nil, fmt.Errorf("%w: failed to create API client: %v", domain.ErrInternalServerError, err)
+	}
+
+	return &model.CreateAPIClientResponse{
+		APIClientResponse: toAPIClientResponse(client),
+		APIKey:            apiKey,
+	}, nil
+}
+
+func (uc *apiClientUseCase) Meter(ctx context.Context, apiKey, endpoint string) (*domain.APIClient, *model.QuotaStatus, error) {
+	client, err := uc.findClientByKey(ctx, apiKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !client.Enabled {
+		return nil, nil, domain.ErrAccountDisabled
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	status := &model.QuotaStatus{Limit: client.MonthlyQuota, ResetAt: monthStart.AddDate(0, 1, 0)}
+
+	allowed, used, err := uc.meter.Consume(ctx, client.ID, endpoint, now, client.MonthlyQuota, domain.UsageSeedUnknown)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Redis has no total for the month, e.g. after a Redis restart, so it starts from what MySQL recorded
+		total, countErr := uc.usageRepo.CountSince(ctx, client.ID, monthStart)
+		if countErr != nil {
+			return nil, nil, fmt.Errorf("%w: failed to count API usage: %v", domain.ErrInternalServerError, countErr)
+		}
+		allowed, used, err = uc.meter.Consume(ctx, client.ID, endpoint, now, client.MonthlyQuota, total)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to meter request: %v", domain.ErrInternalServerError, err)
+	}
+	status.Used = used
+	if !allowed {
+		return client, status, &domain.RetryAfterError{Err: domain.ErrQuotaExceeded, RetryAfter: status.ResetAt.Sub(now)}
+	}
+
+	return client, status, nil
+}
+
+func (uc *apiClientUseCase) GetUsageReport(ctx context.Context, clientID string, req *model.APIUsageReportRequest) (*model.APIUsageReportResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	from, _ := time.Parse("2006-01-02", req.From) // Format already validated
+	to, _ := time.Parse("2006-01-02", req.To)
+	if to.Before(from) || to.Sub(from) > maxUsageReportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: the range must run forwards and cover at most %d days", domain.ErrInvalidInput, maxUsageReportDays)
+	}
+
+	client, err := uc.clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get API client: %v", domain.ErrInternalServerError, err)
+	}
+
+	usage, err := uc.usageRepo.FindByClient(ctx, clientID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get API usage: %v", domain.ErrInternalServerError, err)
+	}
+
+	res := &model.APIUsageReportResponse{
+		ClientID: client.ID,
+		Plan:     client.Plan,
+		From:     req.From,
+		To:       req.To,
+		Days:     []model.APIUsageDayRow{},
+	}
+	days := map[string]*model.APIUsageDayRow{}
+	for _, row := range usage {
+		date := row.UsageDate.Format("2006-01-02")
+		day, ok := days[date]
+		if !ok {
+			day = &model.APIUsageDayRow{Date: date, Endpoints: map[string]int64{}}
+			days[date] = day
+		}
+		day.Endpoints[row.Endpoint] += row.RequestCount
+		day.TotalRequests += row.RequestCount
+		res.TotalRequests += row.RequestCount
+	}
+	for _, day := range days {
+		res.Days = append(res.Days, *day)
+	}
+	sort.Slice(res.Days, func(i, j int) bool { return res.Days[i].Date < res.Days[j].Date })
+
+	return res, nil
+}
+
+// FlushUsage moves the counts buffered in Redis into the daily usage table. One instance flushes at a time,
+// two drains of the same buffer would count its requests twice.
+func (uc *apiClientUseCase) FlushUsage(ctx context.Context) error {
+	err := RunExclusive(ctx, uc.locker, "usage_flush", usageFlushLease, func(ctx context.Context) error {
+		return uc.meter.Drain(ctx, func(batchID string, usage []domain.APIUsage) error {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("usage flush lock lost: %w", err) // The buffer stays for the next flush
+			}
+			return uc.usageRepo.AddUsage(ctx, batchID, usage)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to flush API usage: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+func (uc *apiClientUseCase) RunUsageFlusher(stop <-chan struct{}) {
+	ctx := context.Background()
+	ticker := time.NewTicker(uc.cfg.UsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.FlushUsage(ctx); err != nil {
+				log.Printf("API usage flusher: %v", err)
+			}
+		case <-stop:
+			// Last flush so a clean shutdown loses nothing
+			if err := uc.FlushUsage(ctx); err != nil {
+				log.Printf("API usage flusher: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func (uc *apiClientUseCase) findClientByKey(ctx context.Context, apiKey string) (*domain.APIClient, error) {
+	keyHash := hashAPIKey(apiKey)
+
+	uc.mu.Lock()
+	cached, ok := uc.clients[keyHash]
+	uc.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < apiClientCacheTTL {
+		return cached.client, nil
+	}
+
+	client, err := uc.clientRepo.FindByKeyHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown API key", domain.ErrInvalidInput)
+		}
+		return nil, fmt.Errorf("%w: failed to get API client: %v", domain.ErrInternalServerError, err)
+	}
+
+	uc.mu.Lock()
+	uc.clients[keyHash] = cachedAPIClient{client: client, loadedAt: time.Now()}
+	uc.mu.Unlock()
+	return client, nil
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIClientResponse(client *domain.APIClient) model.APIClientResponse {
+	return model.APIClientResponse{
+		ID:           client.ID,
+		Name:         client.Name,
+		Plan:         client.Plan,
+		MonthlyQuota: client.MonthlyQuota,
+		Enabled:      client.Enabled,
+		CreatedAt:    client.CreatedAt,
+	}
+}
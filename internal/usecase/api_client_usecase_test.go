@@ -0,0 +1,287 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIClientUseCase_CreateClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClientRepo := mock.NewMockAPIClientRepository(ctrl)
+	mockUsageRepo := mock.NewMockAPIUsageRepository(ctrl)
+	mockMeter := mock.NewMockUsageMeter(ctrl)
+	apiClientUseCase := usecase.NewAPIClientUseCase(mockClientRepo, mockUsageRepo, mockMeter, nil, &config.Config{UsageFlushInterval: time.Minute})
+
+	// Test case 1: The plan quota applies and only the key hash is stored
+	t.Run("success_plan_quota", func(t *testing.T) {
+		var stored *domain.APIClient
+		mockClientRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, client *domain.APIClient) error {
+			client.ID = "client-id-123"
+			stored = client
+			return nil
+		}).Times(1)
+
+		res, err := apiClientUseCase.CreateClient(context.Background(), &model.CreateAPIClientRequest{Name: "Partner A", Plan: domain.PlanBasic})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.MonthlyQuota != 100000 || !res.Enabled {
+			t.Errorf("Expected an enabled client with the basic quota, got %+v", res.APIClientResponse)
+		}
+		if !strings.HasPrefix(res.APIKey, "xyz_") {
+			t.Errorf("Expected a prefixed API key, got %q", res.APIKey)
+		}
+		sum := sha256.Sum256([]byte(res.APIKey))
+		if stored.KeyHash != hex.EncodeToString(sum[:]) {
+			t.Errorf("Expected the SHA-256 of the key to be stored")
+		}
+	})
+
+	// Test case 2: A contract quota overrides the plan
+	t.Run("success_quota_override", func(t *testing.T) {
+		quota := int64(5000)
+		mockClientRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		res, err := apiClientUseCase.CreateClient(context.Background(), &model.CreateAPIClientRequest{Name: "Partner B", Plan: domain.PlanBusiness, MonthlyQuota: &quota})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.MonthlyQuota != 5000 {
+			t.Errorf("Expected quota 5000, got %d", res.MonthlyQuota)
+		}
+	})
+
+	// Test case 3: Unknown plan
+	t.Run("invalid_plan", func(t *testing.T) {
+		_, err := apiClientUseCase.CreateClient(context.Background(), &model.CreateAPIClientRequest{Name: "Partner C", Plan: "gold"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestAPIClientUseCase_Meter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClientRepo := mock.NewMockAPIClientRepository(ctrl)
+	mockUsageRepo := mock.NewMockAPIUsageRepository(ctrl)
+	mockMeter := mock.NewMockUsageMeter(ctrl)
+	apiClientUseCase := usecase.NewAPIClientUseCase(mockClientRepo, mockUsageRepo, mockMeter, nil, &config.Config{UsageFlushInterval: time.Minute})
+
+	apiKey := "xyz_partner-key"
+	sum := sha256.Sum256([]byte(apiKey))
+	client := &domain.APIClient{ID: "client-id-123", KeyHash: hex.EncodeToString(sum[:]), Plan: domain.PlanBasic, MonthlyQuota: 100, Enabled: true}
+
+	// Test case 1: The first request loads the client, and the month missing in Redis is seeded from MySQL
+	t.Run("success_first_request", func(t *testing.T) {
+		mockClientRepo.EXPECT().FindByKeyHash(gomock.Any(), client.KeyHash).Return(client, nil).Times(1)
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), domain.UsageSeedUnknown).
+			Return(false, int64(0), domain.ErrNotFound).Times(1)
+		mockUsageRepo.EXPECT().CountSince(gomock.Any(), client.ID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, from time.Time) (int64, error) {
+			if from.Day() != 1 || from.Hour() != 0 || from.Location() != time.UTC {
+				t.Errorf("Expected the start of the month in UTC, got %v", from)
+			}
+			return 40, nil
+		}).Times(1)
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), int64(40)).Return(true, int64(41), nil).Times(1)
+
+		got, status, err := apiClientUseCase.Meter(context.Background(), apiKey, "GET /api/v1/customers")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got.ID != client.ID || status.Limit != 100 || status.Used != 41 {
+			t.Errorf("Expected 41 of 100 used, got %+v", status)
+		}
+		if !status.ResetAt.After(time.Now()) || status.ResetAt.Day() != 1 {
+			t.Errorf("Expected the reset at the start of next month, got %v", status.ResetAt)
+		}
+	})
+
+	// Test case 2: Later requests use the cached client and the month already in Redis
+	t.Run("success_cached", func(t *testing.T) {
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), domain.UsageSeedUnknown).Return(true, int64(42), nil).Times(1)
+
+		_, status, err := apiClientUseCase.Meter(context.Background(), apiKey, "GET /api/v1/customers")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if status.Used != 42 {
+			t.Errorf("Expected 42 used, got %d", status.Used)
+		}
+	})
+
+	// Test case 3: Quota used up
+	t.Run("quota_exceeded", func(t *testing.T) {
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), domain.UsageSeedUnknown).Return(false, int64(100), nil).Times(1)
+
+		_, status, err := apiClientUseCase.Meter(context.Background(), apiKey, "GET /api/v1/customers")
+
+		if !errors.Is(err, domain.ErrQuotaExceeded) {
+			t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+		}
+		var retryErr *domain.RetryAfterError
+		if !errors.As(err, &retryErr) || retryErr.RetryAfter <= 0 {
+			t.Errorf("Expected a RetryAfterError until the reset, got %v", err)
+		}
+		if status == nil || status.Used != 100 {
+			t.Errorf("Expected the quota status alongside the error, got %+v", status)
+		}
+	})
+
+	// Test case 4: A month lost with Redis is seeded again from MySQL, whatever this instance saw before
+	t.Run("reseeded_after_redis_restart", func(t *testing.T) {
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), domain.UsageSeedUnknown).
+			Return(false, int64(0), domain.ErrNotFound).Times(1)
+		mockUsageRepo.EXPECT().CountSince(gomock.Any(), client.ID, gomock.Any()).Return(int64(100), nil).Times(1)
+		mockMeter.EXPECT().Consume(gomock.Any(), client.ID, "GET /api/v1/customers", gomock.Any(), int64(100), int64(100)).Return(false, int64(100), nil).Times(1)
+
+		_, _, err := apiClientUseCase.Meter(context.Background(), apiKey, "GET /api/v1/customers")
+
+		if !errors.Is(err, domain.ErrQuotaExceeded) {
+			t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+		}
+	})
+
+	// Test case 5: Unknown API key
+	t.Run("unknown_key", func(t *testing.T) {
+		mockClientRepo.EXPECT().FindByKeyHash(gomock.Any(), gomock.Any()).Return(nil, domain.ErrNotFound).Times(1)
+
+		_, _, err := apiClientUseCase.Meter(context.Background(), "xyz_unknown", "GET /api/v1/customers")
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 6: Disabled client
+	t.Run("disabled_client", func(t *testing.T) {
+		mockClientRepo.EXPECT().FindByKeyHash(gomock.Any(), gomock.Any()).Return(&domain.APIClient{ID: "client-id-456", Enabled: false}, nil).Times(1)
+
+		_, _, err := apiClientUseCase.Meter(context.Background(), "xyz_disabled", "GET /api/v1/customers")
+
+		if !errors.Is(err, domain.ErrAccountDisabled) {
+			t.Fatalf("Expected ErrAccountDisabled, got %v", err)
+		}
+	})
+}
+
+func TestAPIClientUseCase_GetUsageReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClientRepo := mock.NewMockAPIClientRepository(ctrl)
+	mockUsageRepo := mock.NewMockAPIUsageRepository(ctrl)
+	mockMeter := mock.NewMockUsageMeter(ctrl)
+	apiClientUseCase := usecase.NewAPIClientUseCase(mockClientRepo, mockUsageRepo, mockMeter, nil, &config.Config{UsageFlushInterval: time.Minute})
+
+	// Test case 1: Rows are grouped by day with per-endpoint counts
+	t.Run("success_report", func(t *testing.T) {
+		day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		day2 := day1.AddDate(0, 0, 1)
+		mockClientRepo.EXPECT().FindByID(gomock.Any(), "client-id-123").Return(&domain.APIClient{ID: "client-id-123", Plan: domain.PlanBusiness}, nil).Times(1)
+		mockUsageRepo.EXPECT().FindByClient(gomock.Any(), "client-id-123", day1, day2).Return([]domain.APIUsage{
+			{ClientID: "client-id-123", UsageDate: day2, Endpoint: "GET /api/v1/customers", RequestCount: 5},
+			{ClientID: "client-id-123", UsageDate: day1, Endpoint: "GET /api/v1/customers", RequestCount: 10},
+			{ClientID: "client-id-123", UsageDate: day1, Endpoint: "POST /api/v1/transactions", RequestCount: 3},
+		}, nil).Times(1)
+
+		res, err := apiClientUseCase.GetUsageReport(context.Background(), "client-id-123", &model.APIUsageReportRequest{From: "2025-03-01", To: "2025-03-02"})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.TotalRequests != 18 || len(res.Days) != 2 {
+			t.Fatalf("Expected 18 requests over 2 days, got %+v", res)
+		}
+		if res.Days[0].Date != "2025-03-01" || res.Days[0].TotalRequests != 13 || res.Days[0].Endpoints["POST /api/v1/transactions"] != 3 {
+			t.Errorf("Expected the first day with 13 requests, got %+v", res.Days[0])
+		}
+	})
+
+	// Test case 2: Range runs backwards
+	t.Run("invalid_range", func(t *testing.T) {
+		_, err := apiClientUseCase.GetUsageReport(context.Background(), "client-id-123", &model.APIUsageReportRequest{From: "2025-03-02", To: "2025-03-01"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Unknown client
+	t.Run("client_not_found", func(t *testing.T) {
+		mockClientRepo.EXPECT().FindByID(gomock.Any(), "missing").Return(nil, domain.ErrNotFound).Times(1)
+
+		_, err := apiClientUseCase.GetUsageReport(context.Background(), "missing", &model.APIUsageReportRequest{From: "2025-03-01", To: "2025-03-02"})
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestAPIClientUseCase_FlushUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClientRepo := mock.NewMockAPIClientRepository(ctrl)
+	mockUsageRepo := mock.NewMockAPIUsageRepository(ctrl)
+	mockMeter := mock.NewMockUsageMeter(ctrl)
+	apiClientUseCase := usecase.NewAPIClientUseCase(mockClientRepo, mockUsageRepo, mockMeter, newFreeLocker(ctrl), &config.Config{UsageFlushInterval: time.Minute})
+
+	// Test case 1: Drained counts are written to the usage table
+	t.Run("success_flush", func(t *testing.T) {
+		usage := []domain.APIUsage{{ClientID: "client-id-123", Endpoint: "GET /api/v1/customers", RequestCount: 7}}
+		mockMeter.EXPECT().Drain(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, store func(string, []domain.APIUsage) error) error {
+			return store("batch-1", usage)
+		}).Times(1)
+		mockUsageRepo.EXPECT().AddUsage(gomock.Any(), "batch-1", usage).Return(nil).Times(1)
+
+		if err := apiClientUseCase.FlushUsage(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 2: A failed write is reported so the counts stay buffered
+	t.Run("store_error", func(t *testing.T) {
+		mockMeter.EXPECT().Drain(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, store func(string, []domain.APIUsage) error) error {
+			return store("batch-2", []domain.APIUsage{{ClientID: "client-id-123", RequestCount: 1}})
+		}).Times(1)
+		mockUsageRepo.EXPECT().AddUsage(gomock.Any(), "batch-2", gomock.Any()).Return(errors.New("db down")).Times(1)
+
+		if err := apiClientUseCase.FlushUsage(context.Background()); !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+
+	// Test case 3: Another instance is flushing, this one leaves the buffer alone
+	t.Run("skipped_while_locked", func(t *testing.T) {
+		mockLocker := mock.NewMockLocker(ctrl)
+		mockLocker.EXPECT().TryAcquire(gomock.Any(), "job:usage_flush", gomock.Any()).Return(nil, domain.ErrLockHeld).Times(1)
+		mockMeter.EXPECT().Drain(gomock.Any(), gomock.Any()).Times(0)
+		lockedUseCase := usecase.NewAPIClientUseCase(mockClientRepo, mockUsageRepo, mockMeter, mockLocker, &config.Config{UsageFlushInterval: time.Minute})
+
+		if err := lockedUseCase.FlushUsage(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+}
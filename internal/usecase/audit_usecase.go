@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultAuditPageSize = 50
+	auditVerifyBatchSize = 500
+)
+
+type AuditUseCase interface {
+	domain.AuditLogger
+	ListEntries(ctx context.Context, req *model.ListAuditLogsRequest) (*model.AuditLogListResponse, error)
+	VerifyChain(ctx context.Context) (*model.AuditChainVerificationResponse, error)
+}
+
+type auditUseCase struct {
+	repo      domain.AuditRepository
+	validator *validator.Validate
+}
+
+func NewAuditUseCase(repo domain.AuditRepository) AuditUseCase {
+	return &auditUseCase{
+		repo:      repo,
+		validator: validator.New(),
+	}
+}
+
+func (uc *auditUseCase) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if err := uc.repo.Append(ctx, entry); err != nil {
+		return fmt.Errorf("%w: failed to record audit entry: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+func (uc *auditUseCase) ListEntries(ctx context.Context, req *model.ListAuditLogsRequest) (*model.AuditLogListResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	filter := domain.AuditFilter{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		ActorID:    req.ActorID,
+		Limit:      req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	if req.From != "" {
+		from, _ := time.Parse(time.RFC3339, req.From) // Format already validated
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.Parse(time.RFC3339, req.To)
+		filter.To = &to
+	}
+	if req.Cursor != "" {
+		beforeID, err := strconv.ParseUint(req.Cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+		}
+		filter.BeforeID = beforeID
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	entries, err := uc.repo.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to list audit entries: %v", domain.ErrInternalServerError, err)
+	}
+
+	res := &model.AuditLogListResponse{Data: []model.AuditEntryResponse{}}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		res.NextCursor = strconv.FormatUint(entries[limit-1].ID, 10)
+	}
+	for _, entry := range entries {
+		res.Data = append(res.Data, toAuditEntryResponse(entry))
+	}
+	return res, nil
+}
+
+// VerifyChain walks the whole log in order and recomputes every hash
+func (uc *auditUseCase) VerifyChain(ctx context.Context) (*model.AuditChainVerificationResponse, error) {
+	res := &model.AuditChainVerificationResponse{Valid: true}
+
+	var lastID uint64
+	for {
+		entries, err := uc.repo.FindAfter(ctx, lastID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read audit chain: %v", domain.ErrInternalServerError, err)
+		}
+
+		for _, entry := range entries {
+			res.EntriesChecked++
+			switch {
+			case entry.PrevHash != res.HeadHash:
+				return brokenChain(res, entry.ID, "previous hash does not match, an entry was removed or reordered"), nil
+			case entry.ComputeHash() != entry.Hash:
+				return brokenChain(res, entry.ID, "content does not match its hash, the entry was modified"), nil
+			}
+			res.HeadHash = entry.Hash
+			lastID = entry.ID
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			return res, nil
+		}
+	}
+}
+
+func brokenChain(res *model.AuditChainVerificationResponse, entryID uint64, reason string) *model.AuditChainVerificationResponse {
+	res.Valid = false
+	res.BrokenAtID = entryID
+	res.Reason = reason
+	return res
+}
+
+func toAuditEntryResponse(entry domain.AuditEntry) model.AuditEntryResponse {
+	res := model.AuditEntryResponse{
+		ID:         entry.ID,
+		ActorType:  entry.ActorType,
+		ActorID:    entry.ActorID,
+		Role:       entry.Role,
+		IPAddress:  entry.IPAddress,
+		RequestID:  entry.RequestID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Result:     entry.Result,
+		StatusCode: entry.StatusCode,
+		CreatedAt:  entry.CreatedAt,
+		Hash:       entry.Hash,
+	}
+	if entry.Before != "" {
+		res.Before = json.RawMessage(entry.Before)
+	}
+	if entry.After != "" {
+		res.After = json.RawMessage(entry.After)
+	}
+	return res
+}
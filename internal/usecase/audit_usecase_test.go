@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+// newAuditChain links entries the way the repository does on append
+func newAuditChain(count int) []domain.AuditEntry {
+	entries := make([]domain.AuditEntry, 0, count)
+	prevHash := ""
+	for i := 1; i <= count; i++ {
+		entry := domain.AuditEntry{
+			ID:         uint64(i),
+			ActorType:  domain.SubjectTypeStaff,
+			ActorID:    "staff-id-123",
+			Action:     "credit_limit.set",
+			EntityType: "customer",
+			EntityID:   "cust-id-123",
+			After:      `{"limit_amount":1000000}`,
+			Result:     domain.AuditResultSuccess,
+			StatusCode: 201,
+			CreatedAt:  time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC),
+			PrevHash:   prevHash,
+		}
+		entry.Hash = entry.ComputeHash()
+		prevHash = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditUseCase_VerifyChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	auditUseCase := usecase.NewAuditUseCase(mockAuditRepo)
+
+	// Test case 1: An untouched chain verifies
+	t.Run("valid_chain", func(t *testing.T) {
+		entries := newAuditChain(3)
+		mockAuditRepo.EXPECT().FindAfter(gomock.Any(), uint64(0), gomock.Any()).Return(entries, nil).Times(1)
+
+		res, err := auditUseCase.VerifyChain(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !res.Valid || res.EntriesChecked != 3 || res.HeadHash != entries[2].Hash {
+			t.Errorf("Expected a valid chain of 3 ending at the last hash, got %+v", res)
+		}
+	})
+
+	// Test case 2: Editing a snapshot breaks that entry's hash
+	t.Run("modified_entry", func(t *testing.T) {
+		entries := newAuditChain(3)
+		entries[1].After = `{"limit_amount":9000000}`
+		mockAuditRepo.EXPECT().FindAfter(gomock.Any(), uint64(0), gomock.Any()).Return(entries, nil).Times(1)
+
+		res, err := auditUseCase.VerifyChain(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Valid || res.BrokenAtID != 2 {
+			t.Errorf("Expected the chain to break at entry 2, got %+v", res)
+		}
+	})
+
+	// Test case 3: Deleting an entry breaks the link of the next one
+	t.Run("deleted_entry", func(t *testing.T) {
+		entries := newAuditChain(3)
+		entries = append(entries[:1], entries[2:]...)
+		mockAuditRepo.EXPECT().FindAfter(gomock.Any(), uint64(0), gomock.Any()).Return(entries, nil).Times(1)
+
+		res, err := auditUseCase.VerifyChain(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Valid || res.BrokenAtID != 3 {
+			t.Errorf("Expected the chain to break at entry 3, got %+v", res)
+		}
+	})
+
+	// Test case 4: The genesis entry inserted by migration 014 starts a valid chain
+	t.Run("genesis_entry", func(t *testing.T) {
+		genesis := domain.AuditEntry{
+			ID: 1, ActorType: "system", Action: "audit.genesis", Result: domain.AuditResultSuccess,
+			CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Hash:      "1740f3e622492f6e1f843d773e6fe92710a01731daa1934cf560fabd7827c5a3",
+		}
+		next := newAuditChain(1)[0]
+		next.ID, next.PrevHash = 2, genesis.Hash
+		next.Hash = next.ComputeHash()
+		mockAuditRepo.EXPECT().FindAfter(gomock.Any(), uint64(0), gomock.Any()).Return([]domain.AuditEntry{genesis, next}, nil).Times(1)
+
+		res, err := auditUseCase.VerifyChain(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !res.Valid || res.EntriesChecked != 2 {
+			t.Errorf("Expected a valid chain of 2 from the genesis entry, got %+v", res)
+		}
+	})
+
+	// Test case 5: Repository failure
+	t.Run("repository_error", func(t *testing.T) {
+		mockAuditRepo.EXPECT().FindAfter(gomock.Any(), uint64(0), gomock.Any()).Return(nil, errors.New("db down")).Times(1)
+
+		_, err := auditUseCase.VerifyChain(context.Background())
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+}
+
+func TestAuditUseCase_ListEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	auditUseCase := usecase.NewAuditUseCase(mockAuditRepo)
+
+	// Test case 1: Filters are passed through and the extra row becomes the next cursor
+	t.Run("success_list_with_cursor", func(t *testing.T) {
+		entries := newAuditChain(3)
+		mockAuditRepo.EXPECT().Find(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+			if filter.EntityType != "customer" || filter.EntityID != "cust-id-123" || filter.ActorID != "staff-id-123" {
+				t.Errorf("Expected entity and actor filters, got %+v", filter)
+			}
+			if filter.From == nil || filter.To == nil || filter.BeforeID != 10 || filter.Limit != 3 {
+				t.Errorf("Expected time range, cursor 10 and limit 3, got %+v", filter)
+			}
+			return []domain.AuditEntry{entries[2], entries[1], entries[0]}, nil
+		}).Times(1)
+
+		res, err := auditUseCase.ListEntries(context.Background(), &model.ListAuditLogsRequest{
+			EntityType: "customer",
+			EntityID:   "cust-id-123",
+			ActorID:    "staff-id-123",
+			From:       "2025-01-01T00:00:00Z",
+			To:         "2025-01-02T00:00:00+07:00",
+			Cursor:     "10",
+			Limit:      2,
+		})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(res.Data) != 2 || res.NextCursor != "2" {
+			t.Errorf("Expected 2 entries and cursor 2, got %d and %q", len(res.Data), res.NextCursor)
+		}
+		if string(res.Data[0].After) != `{"limit_amount":1000000}` {
+			t.Errorf("Expected the snapshot as raw JSON, got %s", res.Data[0].After)
+		}
+	})
+
+	// Test case 2: Invalid time range format
+	t.Run("invalid_from", func(t *testing.T) {
+		_, err := auditUseCase.ListEntries(context.Background(), &model.ListAuditLogsRequest{From: "2025-01-01"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -1,71 +1,260 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 	"xyz-multifinance-api/config"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/pkg/jwtkeys"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// Failures before each further attempt is delayed, doubling from one second
+	loginDelayAfterFailures = 3
+	loginMaxDelay           = 30 * time.Second
+)
+
+// Compared against when the NIK does not exist, so unknown and known NIKs take the same time
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
 type AuthUseCase interface {
-	Login(req *model.LoginRequest) (*model.LoginResponse, error)
-	Register(req *model.RegisterCustomerRequest) (*model.CustomerResponse, error)
-	RefreshToken(req *model.RefreshTokenRequest) (*model.LoginResponse, error)
+	Login(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error)
+	StaffLogin(ctx context.Context, req *model.StaffLoginRequest) (*model.LoginResponse, error)
+	VerifyStaffMFA(ctx context.Context, req *model.StaffMFAVerifyRequest) (*model.LoginResponse, error)
+	Register(ctx context.Context, req *model.RegisterCustomerRequest) (*model.CustomerResponse, error)
+	RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.LoginResponse, error)
+	Logout(ctx context.Context, req *model.LogoutRequest) error
+	ListCustomerSessions(ctx context.Context, customerID string) ([]model.SessionResponse, error)
+	RevokeCustomerSession(ctx context.Context, customerID, sessionID string) error
+	UnlockCustomerLogin(ctx context.Context, customerID string) error
+	ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error
 }
 
 type authUseCase struct {
-	customerRepo domain.CustomerRepository
-	cfg          *config.Config
-	validator    *validator.Validate
+	customerRepo     domain.CustomerRepository
+	staffRepo        domain.StaffUserRepository
+	recoveryCodeRepo domain.StaffRecoveryCodeRepository
+	sessionRepo      domain.AuthSessionRepository
+	denylist         domain.TokenDenylist
+	attempts         domain.LoginAttemptTracker
+	notifier         domain.CustomerNotifier
+	otpService       OTPService
+	keySet           *jwtkeys.KeySet
+	cfg              *config.Config
+	validator        *validator.Validate
 }
 
-func NewAuthUseCase(customerRepo domain.CustomerRepository, cfg *config.Config) AuthUseCase {
+func NewAuthUseCase(
+	customerRepo domain.CustomerRepository,
+	staffRepo domain.StaffUserRepository,
+	recoveryCodeRepo domain.StaffRecoveryCodeRepository,
+	sessionRepo domain.AuthSessionRepository,
+	denylist domain.TokenDenylist,
+	attempts domain.LoginAttemptTracker,
+	notifier domain.CustomerNotifier,
+	otpService OTPService,
+	keySet *jwtkeys.KeySet,
+	cfg *config.Config,
+) AuthUseCase {
 	return &authUseCase{
-		customerRepo: customerRepo,
-		cfg:          cfg,
-		validator:    validator.New(),
+		customerRepo:     customerRepo,
+		staffRepo:        staffRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		sessionRepo:      sessionRepo,
+		denylist:         denylist,
+		attempts:         attempts,
+		notifier:         notifier,
+		otpService:       otpService,
+		keySet:           keySet,
+		cfg:              cfg,
+		validator:        validator.New(),
+	}
+}
+
+func (uc *authUseCase) Login(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error) {
+	nikKey, ipKey := "nik:"+req.NIK, "ip:"+req.IPAddress
+	if err := uc.checkLoginAllowed(ctx, nikKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.customerRepo.FindCredentialsByNIK(ctx, req.NIK)
+	if err != nil && err != domain.ErrNotFound {
+		return nil, fmt.Errorf("%w: failed to retrieve customer for login: %v", domain.ErrInternalServerError, err)
+	}
+
+	// Unknown NIKs go through the same hash comparison and counters, the response never tells them apart
+	passwordHash := dummyPasswordHash
+	if customer != nil {
+		passwordHash = []byte(customer.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(req.Password)); err != nil || customer == nil {
+		lockedUntil, err := uc.recordLoginFailure(ctx, nikKey, ipKey)
+		if err != nil {
+			return nil, err
+		}
+		if lockedUntil != nil && customer != nil {
+			if err := uc.notifier.NotifyLoginLocked(customer.ID, *lockedUntil); err != nil {
+				log.Printf("Failed to notify customer %s about login lockout: %v", customer.ID, err)
+			}
+		}
+		return nil, fmt.Errorf("%w: invalid credentials", domain.ErrInvalidInput)
+	}
+
+	// Only the NIK counter is cleared, otherwise one valid account would reset a guessing IP
+	if err := uc.attempts.Reset(ctx, nikKey); err != nil {
+		return nil, fmt.Errorf("%w: failed to reset login attempts: %v", domain.ErrInternalServerError, err)
 	}
+
+	return uc.startSession(ctx, customerClaims(customer), domain.SubjectTypeCustomer, customer.ID, req.UserAgent, req.IPAddress)
 }
 
-func (uc *authUseCase) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
-	customer, err := uc.customerRepo.FindByNIK(req.NIK)
+func (uc *authUseCase) StaffLogin(ctx context.Context, req *model.StaffLoginRequest) (*model.LoginResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	staffKey, ipKey := "staff:"+strings.ToLower(req.Login), "ip:"+req.IPAddress
+	if err := uc.checkLoginAllowed(ctx, staffKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	staff, err := uc.staffRepo.FindByLogin(ctx, strings.ToLower(req.Login))
+	if err != nil && err != domain.ErrNotFound {
+		return nil, fmt.Errorf("%w: failed to retrieve staff user for login: %v", domain.ErrInternalServerError, err)
+	}
+
+	passwordHash := dummyPasswordHash
+	if staff != nil {
+		passwordHash = []byte(staff.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(req.Password)); err != nil || staff == nil {
+		if _, err := uc.recordLoginFailure(ctx, staffKey, ipKey); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: invalid credentials", domain.ErrInvalidInput)
+	}
+
+	if err := uc.attempts.Reset(ctx, staffKey); err != nil {
+		return nil, fmt.Errorf("%w: failed to reset login attempts: %v", domain.ErrInternalServerError, err)
+	}
+
+	// Only reported once the password matched, so it reveals nothing to a guesser
+	if !staff.Enabled {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	// No session exists until the second factor is verified
+	if staff.MFAEnabledAt != nil {
+		return uc.issueMFAPendingToken(staff)
+	}
+
+	return uc.completeStaffLogin(ctx, staff, false, req.UserAgent, req.IPAddress)
+}
+
+func (uc *authUseCase) VerifyStaffMFA(ctx context.Context, req *model.StaffMFAVerifyRequest) (*model.LoginResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	pending, err := uc.parseMFAPendingToken(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaKey, ipKey := "mfa:"+pending.StaffID, "ip:"+req.IPAddress
+	if err := uc.checkLoginAllowed(ctx, mfaKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	staff, err := uc.staffRepo.FindByID(ctx, pending.StaffID)
 	if err != nil {
 		if err == domain.ErrNotFound {
-			return nil, fmt.Errorf("%w: customer not found", domain.ErrInvalidInput)
+			return nil, fmt.Errorf("%w: staff user no longer exists", domain.ErrInvalidInput)
 		}
-		return nil, fmt.Errorf("%w: failed to retrieve customer for login: %v", domain.ErrInternalServerError, err)
+		return nil, fmt.Errorf("%w: failed to retrieve staff user for MFA: %v", domain.ErrInternalServerError, err)
+	}
+	if !staff.Enabled {
+		return nil, domain.ErrAccountDisabled
+	}
+	if staff.MFAEnabledAt == nil {
+		return nil, fmt.Errorf("%w: MFA is not enabled", domain.ErrInvalidInput)
 	}
 
-	// Compare password hash
-	if err := bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(req.Password)); err != nil {
-		return nil, fmt.Errorf("%w: invalid credentials", domain.ErrInvalidInput)
+	if err := uc.verifySecondFactor(ctx, staff, req); err != nil {
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			return nil, err
+		}
+		if _, recordErr := uc.recordLoginFailure(ctx, mfaKey, ipKey); recordErr != nil {
+			return nil, recordErr
+		}
+		return nil, err
 	}
 
-	// Generate Access Token and Refresh Token
-	accessToken, accessExpiresAt, err := uc.generateToken(customer.ID, customer.NIK, uc.cfg.AccessTokenExpiry)
+	if err := uc.attempts.Reset(ctx, mfaKey); err != nil {
+		return nil, fmt.Errorf("%w: failed to reset MFA attempts: %v", domain.ErrInternalServerError, err)
+	}
+
+	return uc.completeStaffLogin(ctx, staff, true, req.UserAgent, req.IPAddress)
+}
+
+func (uc *authUseCase) verifySecondFactor(ctx context.Context, staff *domain.StaffUser, req *model.StaffMFAVerifyRequest) error {
+	if req.Code != "" {
+		return verifyStaffTOTP(ctx, uc.staffRepo, staff, uc.cfg.MFAEncryptionKey, req.Code)
+	}
+
+	consumed, err := uc.recoveryCodeRepo.Consume(ctx, staff.ID, hashRecoveryCode(req.RecoveryCode), time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to generate access token: %v", domain.ErrInternalServerError, err)
+		return fmt.Errorf("%w: failed to consume recovery code: %v", domain.ErrInternalServerError, err)
+	}
+	if !consumed {
+		return fmt.Errorf("%w: invalid recovery code", domain.ErrInvalidInput)
 	}
+	return nil
+}
 
-	refreshToken, _, err := uc.generateToken(customer.ID, customer.NIK, uc.cfg.RefreshTokenExpiry) // Refresh token has no expiry check for now
+func (uc *authUseCase) completeStaffLogin(ctx context.Context, staff *domain.StaffUser, mfaVerified bool, userAgent, ipAddress string) (*model.LoginResponse, error) {
+	now := time.Now()
+	recorded, err := uc.staffRepo.RecordLogin(ctx, staff.ID, now)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to generate refresh token: %v", domain.ErrInternalServerError, err)
+		return nil, fmt.Errorf("%w: failed to record staff login: %v", domain.ErrInternalServerError, err)
+	}
+	if !recorded {
+		return nil, domain.ErrAccountDisabled // Disabled while the password was being checked
+	}
+	staff.LastLoginAt = &now
+
+	claims := staffClaims(staff)
+	claims.MFAVerified = mfaVerified
+	return uc.startSession(ctx, claims, domain.SubjectTypeStaff, staff.ID, userAgent, ipAddress)
+}
+
+// The pending token carries no permissions and is signed with the refresh secret, so resource routes reject it
+func (uc *authUseCase) issueMFAPendingToken(staff *domain.StaffUser) (*model.LoginResponse, error) {
+	claims, expiresAt := withRegisteredClaims(model.Claims{StaffID: staff.ID}, model.TokenTypeMFAPending, uuid.New().String(), uc.cfg.MFAPendingTokenExpiry)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims).SignedString([]byte(uc.cfg.JWTRefreshSecret))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to generate MFA token: %v", domain.ErrInternalServerError, err)
 	}
 
 	return &model.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    accessExpiresAt.Unix(),
+		ExpiresAt:   expiresAt.Unix(),
+		MFARequired: true,
+		MFAToken:    token,
 	}, nil
 }
 
-func (uc *authUseCase) Register(req *model.RegisterCustomerRequest) (*model.CustomerResponse, error) {
+func (uc *authUseCase) Register(ctx context.Context, req *model.RegisterCustomerRequest) (*model.CustomerResponse, error) {
 	if err := uc.validator.Struct(req); err != nil {
 		return nil, domain.ErrInvalidInput
 	}
@@ -90,9 +279,10 @@ func (uc *authUseCase) Register(req *model.RegisterCustomerRequest) (*model.Cust
 		Salary:      req.Salary,
 		KTPPhoto:    req.KTPPhoto,
 		SelfiePhoto: req.SelfiePhoto,
+		KYCStatus:   domain.KYCStatusPending,
 	}
 
-	err = uc.customerRepo.Create(customer)
+	err = uc.customerRepo.Create(ctx, customer)
 	if err != nil {
 		if errors.Is(err, domain.ErrAlreadyExists) {
 			return nil, domain.ErrAlreadyExists
@@ -110,61 +300,384 @@ func (uc *authUseCase) Register(req *model.RegisterCustomerRequest) (*model.Cust
 		Salary:      customer.Salary,
 		KTPPhoto:    customer.KTPPhoto,
 		SelfiePhoto: customer.SelfiePhoto,
+		KYCStatus:   customer.KYCStatus,
 		CreatedAt:   customer.CreatedAt,
 		UpdatedAt:   customer.UpdatedAt,
 	}, nil
 }
 
-func (uc *authUseCase) RefreshToken(req *model.RefreshTokenRequest) (*model.LoginResponse, error) {
-	// Parse and validate
-	token, err := jwt.ParseWithClaims(req.RefreshToken, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(uc.cfg.JWTSecret), nil
-	}, jwt.WithValidMethods([]string{"HS256"}))
+func (uc *authUseCase) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.LoginResponse, error) {
+	claims, err := uc.parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, fmt.Errorf("%w: unknown session", domain.ErrInvalidInput)
+		}
+		return nil, fmt.Errorf("%w: failed to retrieve session: %v", domain.ErrInternalServerError, err)
+	}
+	if session.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: session has been revoked", domain.ErrInvalidInput)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("%w: session has expired", domain.ErrInvalidInput)
+	}
+
+	subjectClaims, err := uc.currentSubjectClaims(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	subjectClaims.MFAVerified = session.MFAVerified
+
+	nextTokenID := uuid.New().String()
+	rotated, err := uc.sessionRepo.Rotate(ctx, session.ID, claims.ID, nextTokenID, time.Now().Add(uc.cfg.RefreshTokenExpiry))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to rotate refresh token: %v", domain.ErrInternalServerError, err)
+	}
+	if !rotated {
+		// An already rotated token came back, assume it leaked and kill the whole family
+		if err := uc.revokeSession(ctx, session.ID); err != nil {
+			return nil, fmt.Errorf("%w: failed to revoke session after refresh token reuse: %v", domain.ErrInternalServerError, err)
+		}
+		return nil, fmt.Errorf("%w: refresh token reuse detected, session revoked", domain.ErrInvalidInput)
+	}
+
+	return uc.issueTokens(subjectClaims, session.ID, nextTokenID)
+}
+
+func (uc *authUseCase) Logout(ctx context.Context, req *model.LogoutRequest) error {
+	if err := uc.validator.Struct(req); err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	claims, err := uc.parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.revokeSession(ctx, claims.SessionID); err != nil {
+		return fmt.Errorf("%w: failed to revoke session: %v", domain.ErrInternalServerError, err)
+	}
+
+	return nil
+}
+
+func (uc *authUseCase) ListCustomerSessions(ctx context.Context, customerID string) ([]model.SessionResponse, error) {
+	sessions, err := uc.sessionRepo.FindActiveBySubject(ctx, domain.SubjectTypeCustomer, customerID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to retrieve sessions: %v", domain.ErrInternalServerError, err)
+	}
+
+	responses := []model.SessionResponse{}
+	for _, session := range sessions {
+		responses = append(responses, model.SessionResponse{
+			ID:              session.ID,
+			UserAgent:       session.UserAgent,
+			IPAddress:       session.IPAddress,
+			CreatedAt:       session.CreatedAt,
+			LastRefreshedAt: session.LastRefreshedAt,
+			ExpiresAt:       session.ExpiresAt,
+		})
+	}
+	return responses, nil
+}
 
+func (uc *authUseCase) RevokeCustomerSession(ctx context.Context, customerID, sessionID string) error {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("%w: failed to retrieve session: %v", domain.ErrInternalServerError, err)
+	}
+	if session.SubjectType != domain.SubjectTypeCustomer || session.SubjectID != customerID {
+		return domain.ErrNotFound
+	}
+
+	if err := uc.revokeSession(ctx, session.ID); err != nil {
+		return fmt.Errorf("%w: failed to revoke session: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+func (uc *authUseCase) UnlockCustomerLogin(ctx context.Context, customerID string) error {
+	customer, err := uc.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("%w: failed to retrieve customer: %v", domain.ErrInternalServerError, err)
+	}
+
+	if err := uc.attempts.Reset(ctx, "nik:"+customer.NIK); err != nil {
+		return fmt.Errorf("%w: failed to unlock customer login: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+// ForgotPassword succeeds silently for unknown NIKs and customers without a verified phone, so it cannot be used to probe NIKs
+func (uc *authUseCase) ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) error {
+	if err := uc.validator.Struct(req); err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	customer, err := uc.customerRepo.FindByNIK(ctx, req.NIK)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil
+		}
+		return fmt.Errorf("%w: failed to retrieve customer for password reset: %v", domain.ErrInternalServerError, err)
+	}
+	if customer.PhoneVerifiedAt == nil || customer.ErasedAt != nil {
+		return nil
+	}
+
+	err = uc.otpService.Send(ctx, domain.OTPPurposePasswordReset, customer.ID, customer.PhoneNumber, "")
+	if err != nil && !errors.Is(err, domain.ErrTooManyAttempts) {
+		return err
+	}
+	return nil
+}
+
+func (uc *authUseCase) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
+	if err := uc.validator.Struct(req); err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	customer, err := uc.customerRepo.FindByNIK(ctx, req.NIK)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return fmt.Errorf("%w: invalid code", domain.ErrInvalidInput)
+		}
+		return fmt.Errorf("%w: failed to retrieve customer for password reset: %v", domain.ErrInternalServerError, err)
+	}
+
+	if _, err := uc.otpService.Verify(ctx, domain.OTPPurposePasswordReset, customer.ID, req.Code); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: failed to hash password: %v", domain.ErrInternalServerError, err)
+	}
+	if err := uc.customerRepo.UpdatePassword(ctx, customer.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("%w: failed to update password: %v", domain.ErrInternalServerError, err)
+	}
+
+	// Whoever knew the old password is signed out everywhere
+	sessions, err := uc.sessionRepo.FindActiveBySubject(ctx, domain.SubjectTypeCustomer, customer.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: failed to retrieve sessions: %v", domain.ErrInternalServerError, err)
+	}
+	for _, session := range sessions {
+		if err := uc.revokeSession(ctx, session.ID); err != nil {
+			return fmt.Errorf("%w: failed to revoke session: %v", domain.ErrInternalServerError, err)
+		}
+	}
+
+	if err := uc.attempts.Reset(ctx, "nik:"+customer.NIK); err != nil {
+		return fmt.Errorf("%w: failed to reset login attempts: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+func (uc *authUseCase) checkLoginAllowed(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		lockedFor, err := uc.attempts.LockedFor(ctx, key)
+		if err != nil {
+			return fmt.Errorf("%w: failed to check login attempts: %v", domain.ErrInternalServerError, err)
+		}
+		if lockedFor > 0 {
+			return &domain.RetryAfterError{Err: domain.ErrTooManyAttempts, RetryAfter: lockedFor}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure delays or locks the account and IP keys, returning the lockout end when this failure started one on the account
+func (uc *authUseCase) recordLoginFailure(ctx context.Context, accountKey, ipKey string) (*time.Time, error) {
+	var lockedUntil *time.Time
+	for _, limit := range []struct {
+		key         string
+		maxAttempts int
+	}{
+		{accountKey, uc.cfg.LoginMaxAttempts},
+		{ipKey, uc.cfg.LoginIPMaxAttempts},
+	} {
+		failures, err := uc.attempts.RecordFailure(ctx, limit.key, uc.cfg.LoginLockoutDuration)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to record login attempt: %v", domain.ErrInternalServerError, err)
+		}
+
+		block := progressiveLoginDelay(failures)
+		if failures >= int64(limit.maxAttempts) {
+			block = uc.cfg.LoginLockoutDuration
+			if failures == int64(limit.maxAttempts) && limit.key == accountKey {
+				until := time.Now().Add(block)
+				lockedUntil = &until
+			}
+		}
+		if block > 0 {
+			if err := uc.attempts.Lock(ctx, limit.key, block); err != nil {
+				return nil, fmt.Errorf("%w: failed to lock login: %v", domain.ErrInternalServerError, err)
+			}
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+func progressiveLoginDelay(failures int64) time.Duration {
+	if failures < loginDelayAfterFailures {
+		return 0
+	}
+	shift := failures - loginDelayAfterFailures
+	if shift >= 5 {
+		return loginMaxDelay
+	}
+	return min(time.Second<<shift, loginMaxDelay)
+}
+
+func (uc *authUseCase) startSession(ctx context.Context, claims model.Claims, subjectType, subjectID, userAgent, ipAddress string) (*model.LoginResponse, error) {
+	session := &domain.AuthSession{
+		ID:             uuid.New().String(),
+		SubjectType:    subjectType,
+		SubjectID:      subjectID,
+		CurrentTokenID: uuid.New().String(),
+		UserAgent:      userAgent,
+		IPAddress:      ipAddress,
+		MFAVerified:    claims.MFAVerified,
+		ExpiresAt:      time.Now().Add(uc.cfg.RefreshTokenExpiry),
+	}
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("%w: failed to create session: %v", domain.ErrInternalServerError, err)
+	}
+
+	return uc.issueTokens(claims, session.ID, session.CurrentTokenID)
+}
+
+// Role, branch and account status may have changed since the session started
+func (uc *authUseCase) currentSubjectClaims(ctx context.Context, session *domain.AuthSession) (model.Claims, error) {
+	if session.SubjectType == domain.SubjectTypeStaff {
+		staff, err := uc.staffRepo.FindByID(ctx, session.SubjectID)
+		if err != nil {
+			if err == domain.ErrNotFound {
+				return model.Claims{}, fmt.Errorf("%w: staff user no longer exists", domain.ErrInvalidInput)
+			}
+			return model.Claims{}, fmt.Errorf("%w: failed to retrieve staff user for refresh: %v", domain.ErrInternalServerError, err)
+		}
+		if !staff.Enabled {
+			return model.Claims{}, fmt.Errorf("%w: staff account disabled", domain.ErrInvalidInput)
+		}
+		return staffClaims(staff), nil
+	}
+
+	customer, err := uc.customerRepo.FindByID(ctx, session.SubjectID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return model.Claims{}, fmt.Errorf("%w: customer no longer exists", domain.ErrInvalidInput)
+		}
+		return model.Claims{}, fmt.Errorf("%w: failed to retrieve customer for refresh: %v", domain.ErrInternalServerError, err)
+	}
+	if customer.ErasedAt != nil {
+		return model.Claims{}, fmt.Errorf("%w: customer data has been erased", domain.ErrInvalidInput)
+	}
+	return customerClaims(customer), nil
+}
+
+// Access tokens of the session stay denied until the longest-lived one has expired
+func (uc *authUseCase) revokeSession(ctx context.Context, sessionID string) error {
+	if err := uc.sessionRepo.Revoke(ctx, sessionID, time.Now()); err != nil {
+		return err
+	}
+	return uc.denylist.Deny(ctx, sessionID, uc.cfg.AccessTokenExpiry)
+}
+
+func (uc *authUseCase) parseRefreshToken(tokenString string) (*model.Claims, error) {
+	claims := &model.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(uc.cfg.JWTRefreshSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
 	if err != nil {
 		return nil, fmt.Errorf("%w: invalid or expired refresh token: %v", domain.ErrInvalidInput, err)
 	}
 
-	claims, ok := token.Claims.(*model.Claims)
-	if !ok || !token.Valid {
+	if !token.Valid || claims.TokenType != model.TokenTypeRefresh || claims.SessionID == "" || claims.ID == "" {
 		return nil, fmt.Errorf("%w: invalid refresh token claims", domain.ErrInvalidInput)
 	}
+	return claims, nil
+}
 
-	// Generate a new Access Token
-	newAccessToken, newAccessExpiresAt, err := uc.generateToken(claims.CustomerID, claims.NIK, uc.cfg.AccessTokenExpiry)
+func (uc *authUseCase) parseMFAPendingToken(tokenString string) (*model.Claims, error) {
+	claims := &model.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(uc.cfg.JWTRefreshSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to generate new access token: %v", domain.ErrInternalServerError, err)
+		return nil, fmt.Errorf("%w: invalid or expired MFA token: %v", domain.ErrInvalidInput, err)
+	}
+
+	if !token.Valid || claims.TokenType != model.TokenTypeMFAPending || claims.StaffID == "" {
+		return nil, fmt.Errorf("%w: invalid MFA token claims", domain.ErrInvalidInput)
 	}
+	return claims, nil
+}
 
-	// New refresh token
-	newRefreshToken, _, err := uc.generateToken(claims.CustomerID, claims.NIK, uc.cfg.RefreshTokenExpiry)
+// Access tokens are signed with the key set so other services can verify them, refresh tokens never leave this service
+func (uc *authUseCase) issueTokens(claims model.Claims, sessionID, refreshTokenID string) (*model.LoginResponse, error) {
+	claims.SessionID = sessionID
+
+	accessClaims, accessExpiresAt := withRegisteredClaims(claims, model.TokenTypeAccess, uuid.New().String(), uc.cfg.AccessTokenExpiry)
+	accessToken, err := uc.keySet.Sign(&accessClaims)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to generate new refresh token: %v", domain.ErrInternalServerError, err)
+		return nil, fmt.Errorf("%w: failed to generate access token: %v", domain.ErrInternalServerError, err)
+	}
+
+	refreshClaims, _ := withRegisteredClaims(claims, model.TokenTypeRefresh, refreshTokenID, uc.cfg.RefreshTokenExpiry)
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &refreshClaims).SignedString([]byte(uc.cfg.JWTRefreshSecret))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to generate refresh token: %v", domain.ErrInternalServerError, err)
 	}
 
 	return &model.LoginResponse{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresAt:    newAccessExpiresAt.Unix(),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    accessExpiresAt.Unix(),
 	}, nil
 }
 
-func (uc *authUseCase) generateToken(customerID, nik string, expiryDuration time.Duration) (string, time.Time, error) {
-	expiresAt := time.Now().Add(expiryDuration)
-	claims := &model.Claims{
-		CustomerID: customerID,
-		NIK:        nik,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+func customerClaims(customer *domain.Customer) model.Claims {
+	return model.Claims{
+		CustomerID:  customer.ID,
+		NIK:         customer.NIK,
+		Role:        domain.RoleCustomer,
+		Permissions: domain.PermissionsForRole(domain.RoleCustomer),
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(uc.cfg.JWTSecret))
-	if err != nil {
-		return "", time.Time{}, err
+// Staff tokens never carry a customer ID
+func staffClaims(staff *domain.StaffUser) model.Claims {
+	return model.Claims{
+		StaffID:     staff.ID,
+		BranchCode:  staff.BranchCode,
+		Role:        staff.Role,
+		Permissions: domain.PermissionsForRole(staff.Role),
+	}
+}
+
+func withRegisteredClaims(claims model.Claims, tokenType, tokenID string, expiryDuration time.Duration) (model.Claims, time.Time) {
+	now := time.Now()
+	expiresAt := now.Add(expiryDuration)
+
+	claims.TokenType = tokenType
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        tokenID,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
 	}
-	return tokenString, expiresAt, nil
+	return claims, expiresAt
 }
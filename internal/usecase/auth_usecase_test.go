@@ -1,13 +1,19 @@
 package usecase_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 	"xyz-multifinance-api/config"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
 	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/jwtkeys"
+	"xyz-multifinance-api/pkg/totp"
 	"xyz-multifinance-api/test/mock"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,9 +26,10 @@ func Register(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
 	cfg := &config.Config{}
 
-	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, cfg)
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, mockStaffRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
 
 	// Test case 1: Successful registration
 	t.Run("success_registration", func(t *testing.T) {
@@ -38,10 +45,10 @@ func Register(t *testing.T) {
 			SelfiePhoto: "http://example.com/selfie.jpg",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, domain.ErrNotFound).Times(1)
-		mockCustomerRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), req.NIK).Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-		res, err := authUseCase.Register(req)
+		res, err := authUseCase.Register(context.Background(), req)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -71,10 +78,10 @@ func Register(t *testing.T) {
 			SelfiePhoto: "http://example.com/selfie_exist.jpg",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(&domain.Customer{NIK: req.NIK}, nil).Times(1)
-		mockCustomerRepo.EXPECT().Create(gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), req.NIK).Return(&domain.Customer{NIK: req.NIK}, nil).Times(1)
+		mockCustomerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := authUseCase.Register(req)
+		_, err := authUseCase.Register(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrAlreadyExists) {
 			t.Fatalf("Expected ErrAlreadyExists, got %v", err)
@@ -96,10 +103,10 @@ func Register(t *testing.T) {
 		}
 
 		// No repository calls expected for invalid input
-		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any()).Times(0)
-		mockCustomerRepo.EXPECT().Create(gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := authUseCase.Register(req)
+		_, err := authUseCase.Register(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
@@ -120,10 +127,10 @@ func Register(t *testing.T) {
 			SelfiePhoto: "http://example.com/selfie_fail.jpg",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, domain.ErrNotFound).Times(1)
-		mockCustomerRepo.EXPECT().Create(gomock.Any()).Return(errors.New("db error")).Times(1) // Simulate DB error
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), req.NIK).Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error")).Times(1) // Simulate DB error
 
-		_, err := authUseCase.Register(req)
+		_, err := authUseCase.Register(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
@@ -136,12 +143,16 @@ func TestAuthUseCase_Login(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
 	cfg := &config.Config{
 		JWTSecret:          "test-jwt-secret",
+		JWTRefreshSecret:   "test-jwt-refresh-secret",
 		AccessTokenExpiry:  time.Minute * 15,
 		RefreshTokenExpiry: time.Hour * 24 * 7,
 	}
-	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, cfg)
+	mockSessionRepo := newInMemorySessionRepo(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, mockStaffRepo, nil, mockSessionRepo, mockDenylist, newOpenAttemptTracker(ctrl), nil, nil, jwtkeys.NewHMACKeySet(cfg.JWTSecret), cfg)
 
 	// Prepare a customer with a hashed password
 	password := "testpassword123"
@@ -160,9 +171,9 @@ func TestAuthUseCase_Login(t *testing.T) {
 			Password: password,
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(testCustomer, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), req.NIK).Return(testCustomer, nil).Times(1)
 
-		res, err := authUseCase.Login(req)
+		res, err := authUseCase.Login(context.Background(), req)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -185,6 +196,12 @@ func TestAuthUseCase_Login(t *testing.T) {
 		if !ok || claims.CustomerID != testCustomer.ID {
 			t.Errorf("Invalid claims in access token")
 		}
+		if claims.Role != domain.RoleCustomer || !slices.Contains(claims.Permissions, domain.PermTransactionReadOwn) {
+			t.Errorf("Expected customer role and permissions in access token, got %s %v", claims.Role, claims.Permissions)
+		}
+		if slices.Contains(claims.Permissions, domain.PermCreditLimitWrite) {
+			t.Error("Customer token must not allow setting credit limits")
+		}
 	})
 
 	// Test case 2: Invalid password
@@ -194,9 +211,9 @@ func TestAuthUseCase_Login(t *testing.T) {
 			Password: "wrongpassword",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(testCustomer, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), req.NIK).Return(testCustomer, nil).Times(1)
 
-		_, err := authUseCase.Login(req)
+		_, err := authUseCase.Login(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
@@ -210,9 +227,9 @@ func TestAuthUseCase_Login(t *testing.T) {
 			Password: "anypassword",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), req.NIK).Return(nil, domain.ErrNotFound).Times(1)
 
-		_, err := authUseCase.Login(req)
+		_, err := authUseCase.Login(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
@@ -226,9 +243,9 @@ func TestAuthUseCase_Login(t *testing.T) {
 			Password: "password123",
 		}
 
-		mockCustomerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, errors.New("db error")).Times(1)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), req.NIK).Return(nil, errors.New("db error")).Times(1)
 
-		_, err := authUseCase.Login(req)
+		_, err := authUseCase.Login(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
@@ -241,52 +258,49 @@ func TestAuthUseCase_RefreshToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
 	cfg := &config.Config{
 		JWTSecret:          "test-jwt-secret",
+		JWTRefreshSecret:   "test-jwt-refresh-secret",
 		AccessTokenExpiry:  time.Minute * 15,
 		RefreshTokenExpiry: time.Hour * 24 * 7,
 	}
-	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, cfg)
-
-	testCustomerID := "refresh-cust-id-123"
-	testNIK := "2222222222222222"
-
-	// **GENERATE A VALID REFRESH TOKEN DIRECTLY IN THE TEST**
-	// TODO: Move token generation to utils
-	refreshTokenClaims := &model.Claims{
-		CustomerID: testCustomerID,
-		NIK:        testNIK,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.RefreshTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+	mockSessionRepo := newInMemorySessionRepo(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, mockStaffRepo, nil, mockSessionRepo, mockDenylist, newOpenAttemptTracker(ctrl), nil, nil, jwtkeys.NewHMACKeySet(cfg.JWTSecret), cfg)
+
+	password := "testpassword123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	testCustomer := &domain.Customer{
+		ID:       "refresh-cust-id-123",
+		NIK:      "2222222222222222",
+		Password: string(hashedPassword),
 	}
-	refreshTokenTestToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshTokenClaims)
-	validRefreshToken, err := refreshTokenTestToken.SignedString([]byte(cfg.JWTSecret))
-	if err != nil {
-		t.Fatalf("Failed to sign test refresh token: %v", err)
+	mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).AnyTimes()
+	mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomer.ID).Return(testCustomer, nil).AnyTimes()
+
+	login := func(t *testing.T) *model.LoginResponse {
+		res, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: testCustomer.NIK, Password: password})
+		if err != nil {
+			t.Fatalf("Failed to log in test customer: %v", err)
+		}
+		return res
 	}
 
-	// Test case 1: Successful refresh
+	// Test case 1: Successful refresh rotates the refresh token
 	t.Run("success_refresh_token", func(t *testing.T) {
-		req := &model.RefreshTokenRequest{
-			RefreshToken: validRefreshToken,
-		}
+		loginRes := login(t)
 
-		res, err := authUseCase.RefreshToken(req)
+		res, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: loginRes.RefreshToken})
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
-		if res == nil {
-			t.Fatal("Expected a response, got nil")
-		}
 		if res.AccessToken == "" || res.RefreshToken == "" {
 			t.Error("Expected new access and refresh tokens, got empty")
 		}
-		if res.ExpiresAt == 0 {
-			t.Error("Expected expires_at to be set, got 0")
+		if res.RefreshToken == loginRes.RefreshToken {
+			t.Error("Expected the refresh token to be rotated")
 		}
 
 		// Verify new access token claims
@@ -294,35 +308,73 @@ func TestAuthUseCase_RefreshToken(t *testing.T) {
 		token, parseErr := jwt.ParseWithClaims(res.AccessToken, newAccessTokenClaims, func(token *jwt.Token) (interface{}, error) {
 			return []byte(cfg.JWTSecret), nil
 		})
-		if parseErr != nil || !token.Valid || newAccessTokenClaims.CustomerID != testCustomerID {
+		if parseErr != nil || !token.Valid || newAccessTokenClaims.CustomerID != testCustomer.ID {
 			t.Errorf("Newly generated access token is invalid or has wrong claims: %v", parseErr)
 		}
+		if newAccessTokenClaims.TokenType != model.TokenTypeAccess || newAccessTokenClaims.SessionID == "" {
+			t.Errorf("Expected an access token bound to a session, got type %q session %q", newAccessTokenClaims.TokenType, newAccessTokenClaims.SessionID)
+		}
 	})
 
-	// Test case 2: Invalid refresh token string
+	// Test case 2: Reusing a rotated refresh token revokes the whole session
+	t.Run("reused_refresh_token_revokes_session", func(t *testing.T) {
+		loginRes := login(t)
+		rotated, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: loginRes.RefreshToken})
+		if err != nil {
+			t.Fatalf("Expected no error on first refresh, got %v", err)
+		}
+
+		mockDenylist.EXPECT().Deny(gomock.Any(), gomock.Any(), cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		_, err = authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: loginRes.RefreshToken})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput on reuse, got %v", err)
+		}
+
+		_, err = authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: rotated.RefreshToken})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected the latest refresh token to be revoked too, got %v", err)
+		}
+	})
+
+	// Test case 3: Access token is not accepted as a refresh token
+	t.Run("access_token_as_refresh_token", func(t *testing.T) {
+		loginRes := login(t)
+
+		_, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: loginRes.AccessToken})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Invalid refresh token string
 	t.Run("invalid_refresh_token_string", func(t *testing.T) {
 		req := &model.RefreshTokenRequest{
 			RefreshToken: "invalid.token.string",
 		}
 
-		_, err := authUseCase.RefreshToken(req)
+		_, err := authUseCase.RefreshToken(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
 		}
 	})
 
-	// Test case 3: Expired refresh token
+	// Test case 5: Expired refresh token
 	t.Run("expired_refresh_token", func(t *testing.T) {
 		expiredRefreshTokenClaims := &model.Claims{
-			CustomerID: testCustomerID,
-			NIK:        testNIK,
+			CustomerID: testCustomer.ID,
+			NIK:        testCustomer.NIK,
+			TokenType:  model.TokenTypeRefresh,
+			SessionID:  "expired-session-id",
 			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "expired-token-id",
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)), // Expired 1 hour ago
 			},
 		}
 		expiredRefreshTokenTestToken := jwt.NewWithClaims(jwt.SigningMethodHS256, expiredRefreshTokenClaims)
-		expiredRefreshToken, err := expiredRefreshTokenTestToken.SignedString([]byte(cfg.JWTSecret))
+		expiredRefreshToken, err := expiredRefreshTokenTestToken.SignedString([]byte(cfg.JWTRefreshSecret))
 		if err != nil {
 			t.Fatalf("Failed to sign expired test refresh token: %v", err)
 		}
@@ -331,10 +383,641 @@ func TestAuthUseCase_RefreshToken(t *testing.T) {
 			RefreshToken: expiredRefreshToken,
 		}
 
-		_, err = authUseCase.RefreshToken(req)
+		_, err = authUseCase.RefreshToken(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput for expired token, got %v", err)
 		}
 	})
+
+	// Test case 6: Logout revokes the session and denies its access tokens
+	t.Run("logout", func(t *testing.T) {
+		loginRes := login(t)
+		claims := &model.Claims{}
+		jwt.ParseWithClaims(loginRes.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(cfg.JWTSecret), nil
+		})
+
+		mockDenylist.EXPECT().Deny(gomock.Any(), claims.SessionID, cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		if err := authUseCase.Logout(context.Background(), &model.LogoutRequest{RefreshToken: loginRes.RefreshToken}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		_, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: loginRes.RefreshToken})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput after logout, got %v", err)
+		}
+	})
+}
+
+func TestAuthUseCase_CustomerSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mock.NewMockAuthSessionRepository(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	cfg := &config.Config{AccessTokenExpiry: time.Minute * 15}
+	authUseCase := usecase.NewAuthUseCase(nil, nil, nil, mockSessionRepo, mockDenylist, nil, nil, nil, nil, cfg)
+
+	testCustomerID := "session-cust-id-123"
+	session := &domain.AuthSession{
+		ID:          "session-id-1",
+		SubjectType: domain.SubjectTypeCustomer,
+		SubjectID:   testCustomerID,
+		UserAgent:   "test-agent",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	// Test case 1: Active sessions are listed
+	t.Run("success_list_sessions", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeCustomer, testCustomerID, gomock.Any()).Return([]domain.AuthSession{*session}, nil).Times(1)
+
+		res, err := authUseCase.ListCustomerSessions(context.Background(), testCustomerID)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(res) != 1 || res[0].ID != session.ID || res[0].UserAgent != "test-agent" {
+			t.Errorf("Expected the active session, got %+v", res)
+		}
+	})
+
+	// Test case 2: Revoking a session denies its access tokens
+	t.Run("success_revoke_session", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByID(gomock.Any(), session.ID).Return(session, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), session.ID, gomock.Any()).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), session.ID, cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		if err := authUseCase.RevokeCustomerSession(context.Background(), testCustomerID, session.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 3: Another customer's session is reported as not found
+	t.Run("other_customer_session", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByID(gomock.Any(), session.ID).Return(session, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := authUseCase.RevokeCustomerSession(context.Background(), "other-customer-id", session.ID)
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+// newOpenAttemptTracker never blocks a login, for tests that are not about lockouts
+func newOpenAttemptTracker(ctrl *gomock.Controller) *mock.MockLoginAttemptTracker {
+	tracker := mock.NewMockLoginAttemptTracker(ctrl)
+	tracker.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+	tracker.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	tracker.EXPECT().Lock(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	tracker.EXPECT().Reset(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return tracker
+}
+
+// newInMemorySessionRepo backs the session mock with a map so rotation can be followed across calls
+func newInMemorySessionRepo(ctrl *gomock.Controller) *mock.MockAuthSessionRepository {
+	sessions := map[string]*domain.AuthSession{}
+	repo := mock.NewMockAuthSessionRepository(ctrl)
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, session *domain.AuthSession) error {
+		stored := *session
+		sessions[session.ID] = &stored
+		return nil
+	}).AnyTimes()
+	repo.EXPECT().FindByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (*domain.AuthSession, error) {
+		session, ok := sessions[id]
+		if !ok {
+			return nil, domain.ErrNotFound
+		}
+		found := *session
+		return &found, nil
+	}).AnyTimes()
+	repo.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+			session, ok := sessions[id]
+			if !ok || session.RevokedAt != nil || session.CurrentTokenID != currentTokenID {
+				return false, nil
+			}
+			session.CurrentTokenID = nextTokenID
+			session.ExpiresAt = expiresAt
+			return true, nil
+		}).AnyTimes()
+	repo.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string, revokedAt time.Time) error {
+		if session, ok := sessions[id]; ok && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+		}
+		return nil
+	}).AnyTimes()
+
+	return repo
+}
+
+func TestAuthUseCase_StaffLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
+	cfg := &config.Config{
+		JWTSecret:          "test-jwt-secret",
+		JWTRefreshSecret:   "test-jwt-refresh-secret",
+		AccessTokenExpiry:  time.Minute * 15,
+		RefreshTokenExpiry: time.Hour * 24 * 7,
+	}
+	mockSessionRepo := newInMemorySessionRepo(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, mockStaffRepo, nil, mockSessionRepo, mockDenylist, newOpenAttemptTracker(ctrl), nil, nil, jwtkeys.NewHMACKeySet(cfg.JWTSecret), cfg)
+
+	password := "Str0ng!Passw0rd"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	newStaff := func(enabled bool) *domain.StaffUser {
+		return &domain.StaffUser{
+			ID:         "staff-id-123",
+			Username:   "officer1",
+			Email:      "officer1@xyz.co.id",
+			Password:   string(hashedPassword),
+			Role:       domain.RoleCreditOfficer,
+			BranchCode: "JKT01",
+			Enabled:    enabled,
+		}
+	}
+
+	// Test case 1: Successful login by email
+	t.Run("success_staff_login", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1@xyz.co.id").Return(newStaff(true), nil).Times(1)
+		mockStaffRepo.EXPECT().RecordLogin(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).Times(1)
+
+		res, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "Officer1@xyz.co.id", Password: password})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		claims := &model.Claims{}
+		jwt.ParseWithClaims(res.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(cfg.JWTSecret), nil
+		})
+		if claims.StaffID != "staff-id-123" || claims.CustomerID != "" {
+			t.Errorf("Expected staff ID and no customer ID in token, got %q and %q", claims.StaffID, claims.CustomerID)
+		}
+		if claims.Role != domain.RoleCreditOfficer || claims.BranchCode != "JKT01" {
+			t.Errorf("Expected credit officer role and branch JKT01, got %s and %s", claims.Role, claims.BranchCode)
+		}
+		if !slices.Contains(claims.Permissions, domain.PermCreditLimitWrite) {
+			t.Errorf("Expected credit officer permissions, got %v", claims.Permissions)
+		}
+	})
+
+	// Test case 2: Wrong password
+	t.Run("invalid_password", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1").Return(newStaff(true), nil).Times(1)
+
+		_, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: "wrong"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Disabled account
+	t.Run("disabled_account", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1").Return(newStaff(false), nil).Times(1)
+
+		_, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: password})
+
+		if !errors.Is(err, domain.ErrAccountDisabled) {
+			t.Fatalf("Expected ErrAccountDisabled, got %v", err)
+		}
+	})
+
+	// Test case 4: Account disabled while the password was being checked
+	t.Run("disabled_during_login", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1").Return(newStaff(true), nil).Times(1)
+		mockStaffRepo.EXPECT().RecordLogin(gomock.Any(), "staff-id-123", gomock.Any()).Return(false, nil).Times(1)
+
+		_, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: password})
+
+		if !errors.Is(err, domain.ErrAccountDisabled) {
+			t.Fatalf("Expected ErrAccountDisabled, got %v", err)
+		}
+	})
+
+	// Test case 5: Refresh is refused once the account is disabled
+	t.Run("refresh_disabled_staff", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1").Return(newStaff(true), nil).Times(1)
+		mockStaffRepo.EXPECT().RecordLogin(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).Times(1)
+		res, _ := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: password})
+
+		mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(newStaff(false), nil).Times(1)
+
+		_, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: res.RefreshToken})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestAuthUseCase_StaffMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
+	mockRecoveryCodeRepo := mock.NewMockStaffRecoveryCodeRepository(ctrl)
+	cfg := &config.Config{
+		JWTSecret:             "test-jwt-secret",
+		JWTRefreshSecret:      "test-jwt-refresh-secret",
+		AccessTokenExpiry:     time.Minute * 15,
+		RefreshTokenExpiry:    time.Hour * 24 * 7,
+		MFAEncryptionKey:      "test-mfa-key",
+		MFAPendingTokenExpiry: time.Minute * 5,
+	}
+	authUseCase := usecase.NewAuthUseCase(nil, mockStaffRepo, mockRecoveryCodeRepo, newInMemorySessionRepo(ctrl), nil, newOpenAttemptTracker(ctrl), nil, nil, jwtkeys.NewHMACKeySet(cfg.JWTSecret), cfg)
+
+	password := "Str0ng!Passw0rd"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	staff := &domain.StaffUser{
+		ID:         "staff-id-123",
+		Username:   "officer1",
+		Password:   string(hashedPassword),
+		Role:       domain.RoleCreditOfficer,
+		BranchCode: "JKT01",
+		Enabled:    true,
+	}
+
+	// Enroll through the staff use case so the secret is stored the way production stores it
+	mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(staff, nil).AnyTimes()
+	mockStaffRepo.EXPECT().EnrollMFA(gomock.Any(), "staff-id-123", gomock.Any()).DoAndReturn(func(_ context.Context, _, secret string) (bool, error) {
+		staff.MFASecret = secret
+		return true, nil
+	}).Times(1)
+	mockStaffRepo.EXPECT().RecordLogin(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).AnyTimes()
+	enrollment, err := usecase.NewStaffUseCase(mockStaffRepo, nil, nil, nil, cfg).EnrollMFA(context.Background(), "staff-id-123")
+	if err != nil {
+		t.Fatalf("Failed to enroll MFA: %v", err)
+	}
+	enabledAt := time.Now()
+	staff.MFAEnabledAt = &enabledAt
+	mockStaffRepo.EXPECT().FindByLogin(gomock.Any(), "officer1").Return(staff, nil).AnyTimes()
+
+	parseAccess := func(t *testing.T, accessToken string) *model.Claims {
+		claims := &model.Claims{}
+		if _, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(cfg.JWTSecret), nil
+		}); err != nil {
+			t.Fatalf("Expected a valid access token, got %v", err)
+		}
+		return claims
+	}
+	pendingToken := func(t *testing.T) string {
+		res, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: password})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return res.MFAToken
+	}
+
+	// Test case 1: Password login only yields an MFA-pending token
+	t.Run("login_requires_mfa", func(t *testing.T) {
+		res, err := authUseCase.StaffLogin(context.Background(), &model.StaffLoginRequest{Login: "officer1", Password: password})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !res.MFARequired || res.MFAToken == "" || res.AccessToken != "" || res.RefreshToken != "" {
+			t.Fatalf("Expected only an MFA token, got %+v", res)
+		}
+
+		_, err = authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: res.MFAToken})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected the MFA token to be rejected as a refresh token, got %v", err)
+		}
+	})
+
+	// Test case 2: A valid TOTP code completes the login with an MFA-verified session
+	t.Run("success_verify_totp", func(t *testing.T) {
+		code, _ := totp.CodeAt(enrollment.Secret, totp.Step(time.Now()))
+		mockStaffRepo.EXPECT().ClaimMFAStep(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).Times(1)
+
+		res, err := authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: pendingToken(t), Code: code})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if claims := parseAccess(t, res.AccessToken); !claims.MFAVerified || claims.StaffID != "staff-id-123" {
+			t.Errorf("Expected an MFA-verified staff token, got %+v", claims)
+		}
+
+		refreshed, err := authUseCase.RefreshToken(context.Background(), &model.RefreshTokenRequest{RefreshToken: res.RefreshToken})
+		if err != nil {
+			t.Fatalf("Expected refresh to succeed, got %v", err)
+		}
+		if !parseAccess(t, refreshed.AccessToken).MFAVerified {
+			t.Error("Expected MFA verification to survive a refresh")
+		}
+	})
+
+	// Test case 3: A code that was already used is rejected
+	t.Run("replayed_totp", func(t *testing.T) {
+		code, _ := totp.CodeAt(enrollment.Secret, totp.Step(time.Now()))
+		mockStaffRepo.EXPECT().ClaimMFAStep(gomock.Any(), "staff-id-123", gomock.Any()).Return(false, nil).Times(1)
+
+		_, err := authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: pendingToken(t), Code: code})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: A wrong code is rejected
+	t.Run("wrong_totp", func(t *testing.T) {
+		code, _ := totp.CodeAt(enrollment.Secret, totp.Step(time.Now())+10)
+
+		_, err := authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: pendingToken(t), Code: code})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 5: A recovery code works once, regardless of case and dashes
+	t.Run("success_recovery_code", func(t *testing.T) {
+		// Stored as the SHA-256 of the normalized code "abcdefgh"
+		storedHash := sha256.Sum256([]byte("abcdefgh"))
+		mockRecoveryCodeRepo.EXPECT().Consume(gomock.Any(), "staff-id-123", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, staffID, codeHash string, usedAt time.Time) (bool, error) {
+				return codeHash == hex.EncodeToString(storedHash[:]), nil
+			}).Times(2)
+
+		res, err := authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: pendingToken(t), RecoveryCode: "ABCDEFGH"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !parseAccess(t, res.AccessToken).MFAVerified {
+			t.Error("Expected an MFA-verified token")
+		}
+
+		_, err = authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: pendingToken(t), RecoveryCode: "wrong-code"})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 6: Access tokens cannot stand in for the MFA token
+	t.Run("invalid_mfa_token", func(t *testing.T) {
+		_, err := authUseCase.VerifyStaffMFA(context.Background(), &model.StaffMFAVerifyRequest{MFAToken: "not-a-token", Code: "123456"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestAuthUseCase_AsymmetricSigning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	cfg := &config.Config{
+		JWTRefreshSecret:   "test-jwt-refresh-secret",
+		AccessTokenExpiry:  time.Minute * 15,
+		RefreshTokenExpiry: time.Hour * 24 * 7,
+	}
+
+	password := "testpassword123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	testCustomer := &domain.Customer{ID: "eddsa-cust-id-123", NIK: "3333333333333333", Password: string(hashedPassword)}
+	mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).AnyTimes()
+
+	keysDir := t.TempDir()
+	keySet, err := jwtkeys.LoadKeySet(keysDir, jwtkeys.AlgorithmEdDSA, time.Hour, cfg.AccessTokenExpiry)
+	if err != nil {
+		t.Fatalf("Failed to load key set: %v", err)
+	}
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, nil, nil, newInMemorySessionRepo(ctrl), mock.NewMockTokenDenylist(ctrl), newOpenAttemptTracker(ctrl), nil, nil, keySet, cfg)
+
+	login := func(t *testing.T) (*jwt.Token, *model.Claims) {
+		res, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: testCustomer.NIK, Password: password})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		claims := &model.Claims{}
+		token, err := jwt.ParseWithClaims(res.AccessToken, claims, keySet.Keyfunc, jwt.WithValidMethods(keySet.Methods()))
+		if err != nil {
+			t.Fatalf("Expected access token to verify against the key set, got %v", err)
+		}
+		return token, claims
+	}
+
+	// Test case 1: Access token is EdDSA-signed and its kid is published in the JWKS
+	t.Run("success_eddsa_access_token", func(t *testing.T) {
+		token, claims := login(t)
+
+		if token.Method.Alg() != jwtkeys.AlgorithmEdDSA || claims.CustomerID != testCustomer.ID {
+			t.Fatalf("Expected EdDSA token for %s, got %s for %s", testCustomer.ID, token.Method.Alg(), claims.CustomerID)
+		}
+
+		jwks := keySet.JWKS()
+		if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != token.Header["kid"] || jwks.Keys[0].Curve != "Ed25519" {
+			t.Errorf("Expected the signing key in the JWKS, got %+v", jwks.Keys)
+		}
+	})
+
+	// Test case 2: Tokens signed before a rotation still verify
+	t.Run("rotation_keeps_previous_key", func(t *testing.T) {
+		oldToken, _ := login(t)
+
+		if err := keySet.Rotate(); err != nil {
+			t.Fatalf("Expected no error rotating keys, got %v", err)
+		}
+		newToken, _ := login(t)
+
+		if oldToken.Header["kid"] == newToken.Header["kid"] {
+			t.Error("Expected a new kid after rotation")
+		}
+		if _, err := jwt.Parse(oldToken.Raw, keySet.Keyfunc); err != nil {
+			t.Errorf("Expected token signed with the previous key to verify, got %v", err)
+		}
+		if len(keySet.JWKS().Keys) != 2 {
+			t.Errorf("Expected both keys in the JWKS, got %d", len(keySet.JWKS().Keys))
+		}
+	})
+
+	// Test case 3: A token claiming HS256 is not verified with a public key
+	t.Run("algorithm_confusion", func(t *testing.T) {
+		token, _ := login(t)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"customer_id": "attacker"})
+		forged.Header["kid"] = token.Header["kid"]
+		forgedString, _ := forged.SignedString([]byte("guessed-secret"))
+
+		if _, err := jwt.Parse(forgedString, keySet.Keyfunc, jwt.WithValidMethods(keySet.Methods())); err == nil {
+			t.Fatal("Expected HS256 token to be rejected")
+		}
+	})
+}
+
+func TestAuthUseCase_LoginLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockTracker := mock.NewMockLoginAttemptTracker(ctrl)
+	mockNotifier := mock.NewMockCustomerNotifier(ctrl)
+	cfg := &config.Config{
+		JWTSecret:            "test-jwt-secret",
+		JWTRefreshSecret:     "test-jwt-refresh-secret",
+		AccessTokenExpiry:    time.Minute * 15,
+		RefreshTokenExpiry:   time.Hour * 24 * 7,
+		LoginMaxAttempts:     5,
+		LoginIPMaxAttempts:   20,
+		LoginLockoutDuration: time.Minute * 15,
+	}
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, nil, nil, newInMemorySessionRepo(ctrl), nil, mockTracker, mockNotifier, nil, jwtkeys.NewHMACKeySet(cfg.JWTSecret), cfg)
+
+	password := "testpassword123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	testCustomer := &domain.Customer{ID: "lockout-cust-id-123", NIK: "4444444444444444", Password: string(hashedPassword)}
+	nikKey, ipKey := "nik:"+testCustomer.NIK, "ip:10.0.0.1"
+
+	// Test case 1: Locked NIK is refused even with the right password
+	t.Run("locked_nik", func(t *testing.T) {
+		mockTracker.EXPECT().LockedFor(gomock.Any(), nikKey).Return(time.Minute*10, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: testCustomer.NIK, Password: password, IPAddress: "10.0.0.1"})
+
+		var retryErr *domain.RetryAfterError
+		if !errors.Is(err, domain.ErrTooManyAttempts) || !errors.As(err, &retryErr) || retryErr.RetryAfter != time.Minute*10 {
+			t.Fatalf("Expected ErrTooManyAttempts with retry after 10m, got %v", err)
+		}
+	})
+
+	// Test case 2: Reaching the limit locks the NIK and notifies the customer
+	t.Run("lockout_notifies_customer", func(t *testing.T) {
+		mockTracker.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).Times(1)
+		mockTracker.EXPECT().RecordFailure(gomock.Any(), nikKey, cfg.LoginLockoutDuration).Return(int64(5), nil).Times(1)
+		mockTracker.EXPECT().Lock(gomock.Any(), nikKey, cfg.LoginLockoutDuration).Return(nil).Times(1)
+		mockTracker.EXPECT().RecordFailure(gomock.Any(), ipKey, cfg.LoginLockoutDuration).Return(int64(5), nil).Times(1)
+		mockTracker.EXPECT().Lock(gomock.Any(), ipKey, 4*time.Second).Return(nil).Times(1) // Progressive delay only, IP limit not reached
+		mockNotifier.EXPECT().NotifyLoginLocked(testCustomer.ID, gomock.Any()).Return(nil).Times(1)
+
+		_, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: testCustomer.NIK, Password: "wrongpassword", IPAddress: "10.0.0.1"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Unknown NIK is counted like a known one and gives the same error
+	t.Run("unknown_nik_counted", func(t *testing.T) {
+		unknownKey := "nik:5555555555555555"
+		mockTracker.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), "5555555555555555").Return(nil, domain.ErrNotFound).Times(1)
+		mockTracker.EXPECT().RecordFailure(gomock.Any(), unknownKey, cfg.LoginLockoutDuration).Return(int64(5), nil).Times(1)
+		mockTracker.EXPECT().Lock(gomock.Any(), unknownKey, cfg.LoginLockoutDuration).Return(nil).Times(1)
+		mockTracker.EXPECT().RecordFailure(gomock.Any(), ipKey, cfg.LoginLockoutDuration).Return(int64(1), nil).Times(1)
+		mockNotifier.EXPECT().NotifyLoginLocked(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: "5555555555555555", Password: password, IPAddress: "10.0.0.1"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Successful login clears the NIK counter but not the IP counter
+	t.Run("success_resets_nik", func(t *testing.T) {
+		mockTracker.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+		mockCustomerRepo.EXPECT().FindCredentialsByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).Times(1)
+		mockTracker.EXPECT().Reset(gomock.Any(), nikKey).Return(nil).Times(1)
+
+		if _, err := authUseCase.Login(context.Background(), &model.LoginRequest{NIK: testCustomer.NIK, Password: password, IPAddress: "10.0.0.1"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 5: Staff unlock clears the customer's NIK lock
+	t.Run("unlock_customer", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomer.ID).Return(testCustomer, nil).Times(1)
+		mockTracker.EXPECT().Reset(gomock.Any(), nikKey).Return(nil).Times(1)
+
+		if err := authUseCase.UnlockCustomerLogin(context.Background(), testCustomer.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestAuthUseCase_PasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockSessionRepo := mock.NewMockAuthSessionRepository(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	mockTracker := mock.NewMockLoginAttemptTracker(ctrl)
+	mockOTPService := mock.NewMockOTPService(ctrl)
+	cfg := &config.Config{AccessTokenExpiry: time.Minute * 15}
+	authUseCase := usecase.NewAuthUseCase(mockCustomerRepo, nil, nil, mockSessionRepo, mockDenylist, mockTracker, nil, mockOTPService, nil, cfg)
+
+	verifiedAt := time.Now()
+	testCustomer := &domain.Customer{ID: "reset-cust-id-123", NIK: "6666666666666666", PhoneNumber: "+6281234567890", PhoneVerifiedAt: &verifiedAt}
+
+	// Test case 1: Code is sent to the verified phone
+	t.Run("success_forgot_password", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).Times(1)
+		mockOTPService.EXPECT().Send(gomock.Any(), domain.OTPPurposePasswordReset, testCustomer.ID, testCustomer.PhoneNumber, "").Return(nil).Times(1)
+
+		if err := authUseCase.ForgotPassword(context.Background(), &model.ForgotPasswordRequest{NIK: testCustomer.NIK}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 2: Unknown NIK looks like success
+	t.Run("unknown_nik_silent", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), "7777777777777777").Return(nil, domain.ErrNotFound).Times(1)
+		mockOTPService.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		if err := authUseCase.ForgotPassword(context.Background(), &model.ForgotPasswordRequest{NIK: "7777777777777777"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 3: Valid code sets the password and signs out every session
+	t.Run("success_reset_password", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).Times(1)
+		mockOTPService.EXPECT().Verify(gomock.Any(), domain.OTPPurposePasswordReset, testCustomer.ID, "123456").Return("", nil).Times(1)
+		mockCustomerRepo.EXPECT().UpdatePassword(gomock.Any(), testCustomer.ID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, passwordHash string) error {
+			if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("newpassword123")) != nil {
+				t.Error("Expected the new password to be stored as a hash")
+			}
+			return nil
+		}).Times(1)
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeCustomer, testCustomer.ID, gomock.Any()).Return([]domain.AuthSession{{ID: "session-1"}, {ID: "session-2"}}, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockDenylist.EXPECT().Deny(gomock.Any(), gomock.Any(), cfg.AccessTokenExpiry).Return(nil).Times(2)
+		mockTracker.EXPECT().Reset(gomock.Any(), "nik:"+testCustomer.NIK).Return(nil).Times(1)
+
+		err := authUseCase.ResetPassword(context.Background(), &model.ResetPasswordRequest{NIK: testCustomer.NIK, Code: "123456", NewPassword: "newpassword123"})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 4: Wrong code leaves the password alone
+	t.Run("invalid_code", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), testCustomer.NIK).Return(testCustomer, nil).Times(1)
+		mockOTPService.EXPECT().Verify(gomock.Any(), domain.OTPPurposePasswordReset, testCustomer.ID, "654321").Return("", domain.ErrInvalidInput).Times(1)
+		mockCustomerRepo.EXPECT().UpdatePassword(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := authUseCase.ResetPassword(context.Background(), &model.ResetPasswordRequest{NIK: testCustomer.NIK, Code: "654321", NewPassword: "newpassword123"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"xyz-multifinance-api/internal/domain"
@@ -10,32 +11,46 @@ import (
 )
 
 type CreditLimitUseCase interface {
-	SetCustomerCreditLimit(req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error)
-	GetCustomerCreditLimits(customerID string) ([]model.CreditLimitResponse, error)
-	GetCustomerCreditLimitByTenor(customerID string, tenorMonths int) (*model.CreditLimitResponse, error)
+	SetCustomerCreditLimit(ctx context.Context, req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error)
+	GetCustomerCreditLimits(ctx context.Context, customerID string) ([]model.CreditLimitResponse, error)
+	GetCustomerCreditLimitByTenor(ctx context.Context, customerID string, tenorMonths int) (*model.CreditLimitResponse, error)
 }
 
 type creditLimitUseCase struct {
 	creditLimitRepo domain.CreditLimitRepository
 	customerRepo    domain.CustomerRepository
+	locker          domain.Locker
 	validator       *validator.Validate
 }
 
-func NewCreditLimitUseCase(creditLimitRepo domain.CreditLimitRepository, customerRepo domain.CustomerRepository) CreditLimitUseCase {
+func NewCreditLimitUseCase(creditLimitRepo domain.CreditLimitRepository, customerRepo domain.CustomerRepository, locker domain.Locker) CreditLimitUseCase {
 	return &creditLimitUseCase{
 		creditLimitRepo: creditLimitRepo,
 		customerRepo:    customerRepo,
+		locker:          locker,
 		validator:       validator.New(),
 	}
 }
 
-func (uc *creditLimitUseCase) SetCustomerCreditLimit(req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error) {
+func (uc *creditLimitUseCase) SetCustomerCreditLimit(ctx context.Context, req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error) {
 	if err := uc.validator.Struct(req); err != nil {
 		return nil, domain.ErrInvalidInput
 	}
 
+	// The check for an existing limit and the write are separate statements
+	var res *model.CreditLimitResponse
+	err := withCustomerLock(ctx, uc.locker, req.CustomerID, func(ctx context.Context) error {
+		var err error
+		res, err = uc.setCustomerCreditLimit(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+func (uc *creditLimitUseCase) setCustomerCreditLimit(ctx context.Context, req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error) {
+
 	// Verify customer exist
-	_, err := uc.customerRepo.FindByID(req.CustomerID)
+	_, err := uc.customerRepo.FindByID(ctx, req.CustomerID)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, req.CustomerID)
@@ -43,7 +58,7 @@ func (uc *creditLimitUseCase) SetCustomerCreditLimit(req *model.SetCreditLimitRe
 		return nil, fmt.Errorf("%w: failed to verify customer existence: %v", domain.ErrInternalServerError, err)
 	}
 
-	existingLimit, err := uc.creditLimitRepo.GetCreditLimitByCustomerAndTenor(req.CustomerID, req.TenorMonths)
+	existingLimit, err := uc.creditLimitRepo.GetCreditLimitByCustomerAndTenor(ctx, req.CustomerID, req.TenorMonths)
 
 	if err != nil && err != domain.ErrNotFound {
 		return nil, fmt.Errorf("%w: failed to check existing credit limit: %v", domain.ErrInternalServerError, err)
@@ -57,13 +72,13 @@ func (uc *creditLimitUseCase) SetCustomerCreditLimit(req *model.SetCreditLimitRe
 
 	if existingLimit != nil {
 		creditLimit.ID = existingLimit.ID // Update existing row
-		err = uc.creditLimitRepo.UpdateCreditLimit(creditLimit)
+		err = uc.creditLimitRepo.UpdateCreditLimit(ctx, creditLimit)
 		if err != nil {
 			return nil, fmt.Errorf("%w: failed to update credit limit: %v", domain.ErrInternalServerError, err)
 		}
 	} else {
 		// Create if not exist
-		err = uc.creditLimitRepo.CreateCreditLimit(creditLimit)
+		err = uc.creditLimitRepo.CreateCreditLimit(ctx, creditLimit)
 		if err != nil {
 			if errors.Is(err, domain.ErrAlreadyExists) {
 				return nil, fmt.Errorf("%w: credit limit for this customer and tenor already exists", domain.ErrAlreadyExists)
@@ -80,9 +95,9 @@ func (uc *creditLimitUseCase) SetCustomerCreditLimit(req *model.SetCreditLimitRe
 	}, nil
 }
 
-func (uc *creditLimitUseCase) GetCustomerCreditLimits(customerID string) ([]model.CreditLimitResponse, error) {
+func (uc *creditLimitUseCase) GetCustomerCreditLimits(ctx context.Context, customerID string) ([]model.CreditLimitResponse, error) {
 	// Verify customer exist
-	_, err := uc.customerRepo.FindByID(customerID)
+	_, err := uc.customerRepo.FindByID(ctx, customerID)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, customerID)
@@ -90,7 +105,7 @@ func (uc *creditLimitUseCase) GetCustomerCreditLimits(customerID string) ([]mode
 		return nil, fmt.Errorf("%w: failed to verify customer existence: %v", domain.ErrInternalServerError, err)
 	}
 
-	limits, err := uc.creditLimitRepo.GetCreditLimitsByCustomerID(customerID)
+	limits, err := uc.creditLimitRepo.GetCreditLimitsByCustomerID(ctx, customerID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to retrieve credit limits: %v", domain.ErrInternalServerError, err)
 	}
@@ -107,9 +122,9 @@ func (uc *creditLimitUseCase) GetCustomerCreditLimits(customerID string) ([]mode
 	return responses, nil
 }
 
-func (uc *creditLimitUseCase) GetCustomerCreditLimitByTenor(customerID string, tenorMonths int) (*model.CreditLimitResponse, error) {
+func (uc *creditLimitUseCase) GetCustomerCreditLimitByTenor(ctx context.Context, customerID string, tenorMonths int) (*model.CreditLimitResponse, error) {
 	// Verify customer exist
-	_, err := uc.customerRepo.FindByID(customerID)
+	_, err := uc.customerRepo.FindByID(ctx, customerID)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, customerID)
@@ -117,7 +132,7 @@ func (uc *creditLimitUseCase) GetCustomerCreditLimitByTenor(customerID string, t
 		return nil, fmt.Errorf("%w: failed to verify customer existence: %v", domain.ErrInternalServerError, err)
 	}
 
-	limit, err := uc.creditLimitRepo.GetCreditLimitByCustomerAndTenor(customerID, tenorMonths)
+	limit, err := uc.creditLimitRepo.GetCreditLimitByCustomerAndTenor(ctx, customerID, tenorMonths)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, fmt.Errorf("%w: credit limit for tenor %d not found for customer %s", domain.ErrNotFound, tenorMonths, customerID)
@@ -1,6 +1,7 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"xyz-multifinance-api/internal/domain"
@@ -19,7 +20,7 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 	mockCreditLimitRepo := mock.NewMockCreditLimitRepository(ctrl)
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
 
-	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo)
+	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo, newFreeLocker(ctrl))
 
 	testCustomerID := uuid.New().String()
 	testCustomer := &domain.Customer{ID: testCustomerID, NIK: "1234567890123456"}
@@ -32,11 +33,11 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 1000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, req.TenorMonths).Return(nil, domain.ErrNotFound).Times(1)
-		mockCreditLimitRepo.EXPECT().CreateCreditLimit(gomock.Any()).Return(nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, req.TenorMonths).Return(nil, domain.ErrNotFound).Times(1)
+		mockCreditLimitRepo.EXPECT().CreateCreditLimit(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-		res, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		res, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -63,11 +64,11 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 1000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, req.TenorMonths).Return(existingLimit, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any()).Return(nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, req.TenorMonths).Return(existingLimit, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-		res, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		res, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -88,10 +89,10 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 1000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(req.CustomerID).Return(nil, domain.ErrNotFound).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), req.CustomerID).Return(nil, domain.ErrNotFound).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		_, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -106,10 +107,10 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 1000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(gomock.Any()).Times(0)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		_, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
@@ -124,11 +125,11 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 2000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, req.TenorMonths).Return(nil, domain.ErrNotFound).Times(1)
-		mockCreditLimitRepo.EXPECT().CreateCreditLimit(gomock.Any()).Return(errors.New("db write error")).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, req.TenorMonths).Return(nil, domain.ErrNotFound).Times(1)
+		mockCreditLimitRepo.EXPECT().CreateCreditLimit(gomock.Any(), gomock.Any()).Return(errors.New("db write error")).Times(1)
 
-		_, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		_, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
@@ -149,16 +150,60 @@ func TestCreditLimitUseCase_SetCustomerCreditLimit(t *testing.T) {
 			LimitAmount: 1000000,
 		}
 
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, req.TenorMonths).Return(existingLimit, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any()).Return(errors.New("db update error")).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, req.TenorMonths).Return(existingLimit, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any(), gomock.Any()).Return(errors.New("db update error")).Times(1)
 
-		_, err := creditLimitUseCase.SetCustomerCreditLimit(req)
+		_, err := creditLimitUseCase.SetCustomerCreditLimit(context.Background(), req)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
 		}
 	})
+
+	// Test case 7: Another operation holds the customer's lock for the whole wait
+	t.Run("customer_busy", func(t *testing.T) {
+		mockLocker := mock.NewMockLocker(ctrl)
+		mockLocker.EXPECT().Acquire(gomock.Any(), domain.CustomerLockKey(testCustomerID), gomock.Any()).Return(nil, context.DeadlineExceeded).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+		busyUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo, mockLocker)
+
+		_, err := busyUseCase.SetCustomerCreditLimit(context.Background(), &model.SetCreditLimitRequest{CustomerID: testCustomerID, TenorMonths: 1, LimitAmount: 1500000})
+
+		var retryErr *domain.RetryAfterError
+		if !errors.Is(err, domain.ErrResourceBusy) || !errors.As(err, &retryErr) {
+			t.Fatalf("Expected ErrResourceBusy with a retry hint, got %v", err)
+		}
+	})
+
+	// Test case 8: The request deadline passes while waiting for the lock
+	t.Run("request_deadline_exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		mockLocker := mock.NewMockLocker(ctrl)
+		mockLocker.EXPECT().Acquire(gomock.Any(), domain.CustomerLockKey(testCustomerID), gomock.Any()).Return(nil, context.DeadlineExceeded).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+		deadlineUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo, mockLocker)
+
+		_, err := deadlineUseCase.SetCustomerCreditLimit(ctx, &model.SetCreditLimitRequest{CustomerID: testCustomerID, TenorMonths: 1, LimitAmount: 1500000})
+
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, domain.ErrResourceBusy) {
+			t.Fatalf("Expected the request's deadline error, got %v", err)
+		}
+	})
+}
+
+// newFreeLocker returns a locker whose locks are always free
+func newFreeLocker(ctrl *gomock.Controller) *mock.MockLocker {
+	mockLock := mock.NewMockLock(ctrl)
+	mockLock.EXPECT().Context().Return(context.Background()).AnyTimes()
+	mockLock.EXPECT().Release().Return(nil).AnyTimes()
+
+	mockLocker := mock.NewMockLocker(ctrl)
+	mockLocker.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockLock, nil).AnyTimes()
+	mockLocker.EXPECT().TryAcquire(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockLock, nil).AnyTimes()
+	return mockLocker
 }
 
 func TestCreditLimitUseCase_GetCustomerCreditLimits(t *testing.T) {
@@ -168,7 +213,7 @@ func TestCreditLimitUseCase_GetCustomerCreditLimits(t *testing.T) {
 	mockCreditLimitRepo := mock.NewMockCreditLimitRepository(ctrl)
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
 
-	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo)
+	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo, nil)
 
 	testCustomerID := "test-cust-id-get"
 	testCustomer := &domain.Customer{ID: testCustomerID, NIK: "1234567890123456"}
@@ -179,10 +224,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimits(t *testing.T) {
 
 	// Test case 1: Successfully retrieve multiple limits
 	t.Run("success_get_multiple_limits", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(testCustomerID).Return(testLimits, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(gomock.Any(), testCustomerID).Return(testLimits, nil).Times(1)
 
-		res, err := creditLimitUseCase.GetCustomerCreditLimits(testCustomerID)
+		res, err := creditLimitUseCase.GetCustomerCreditLimits(context.Background(), testCustomerID)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -197,10 +242,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimits(t *testing.T) {
 
 	// Test case 2: Customer not found
 	t.Run("get_limits_customer_not_found", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID("non-existent").Return(nil, domain.ErrNotFound).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), "non-existent").Return(nil, domain.ErrNotFound).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := creditLimitUseCase.GetCustomerCreditLimits("non-existent")
+		_, err := creditLimitUseCase.GetCustomerCreditLimits(context.Background(), "non-existent")
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -209,10 +254,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimits(t *testing.T) {
 
 	// Test case 3: Repository error during retrieval
 	t.Run("repo_error_get_limits", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(testCustomerID).Return(nil, errors.New("db error")).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(gomock.Any(), testCustomerID).Return(nil, errors.New("db error")).Times(1)
 
-		_, err := creditLimitUseCase.GetCustomerCreditLimits(testCustomerID)
+		_, err := creditLimitUseCase.GetCustomerCreditLimits(context.Background(), testCustomerID)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
@@ -227,7 +272,7 @@ func TestCreditLimitUseCase_GetCustomerCreditLimitByTenor(t *testing.T) {
 	mockCreditLimitRepo := mock.NewMockCreditLimitRepository(ctrl)
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
 
-	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo)
+	creditLimitUseCase := usecase.NewCreditLimitUseCase(mockCreditLimitRepo, mockCustomerRepo, nil)
 
 	testCustomerID := "test-cust-id-tenor"
 	testTenor := 6
@@ -238,10 +283,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimitByTenor(t *testing.T) {
 
 	// Test case 1: Successfully retrieve specific limit
 	t.Run("success_get_specific_limit", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, testTenor).Return(testLimit, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, testTenor).Return(testLimit, nil).Times(1)
 
-		res, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(testCustomerID, testTenor)
+		res, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(context.Background(), testCustomerID, testTenor)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -256,10 +301,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimitByTenor(t *testing.T) {
 
 	// Test case 2: Customer not found
 	t.Run("get_specific_limit_customer_not_found", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID("non-existent").Return(nil, domain.ErrNotFound).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any()).Times(0)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), "non-existent").Return(nil, domain.ErrNotFound).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor("non-existent", testTenor)
+		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(context.Background(), "non-existent", testTenor)
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -268,10 +313,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimitByTenor(t *testing.T) {
 
 	// Test case 3: Specific limit not found
 	t.Run("specific_limit_not_found", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, testTenor).Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, testTenor).Return(nil, domain.ErrNotFound).Times(1)
 
-		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(testCustomerID, testTenor)
+		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(context.Background(), testCustomerID, testTenor)
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -280,10 +325,10 @@ func TestCreditLimitUseCase_GetCustomerCreditLimitByTenor(t *testing.T) {
 
 	// Test case 4: Repository error during retrieval
 	t.Run("repo_error_get_specific_limit", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
-		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(testCustomerID, testTenor).Return(nil, errors.New("db error")).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitByCustomerAndTenor(gomock.Any(), testCustomerID, testTenor).Return(nil, errors.New("db error")).Times(1)
 
-		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(testCustomerID, testTenor)
+		_, err := creditLimitUseCase.GetCustomerCreditLimitByTenor(context.Background(), testCustomerID, testTenor)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
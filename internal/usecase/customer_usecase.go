@@ -1,28 +1,56 @@
 package usecase
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"time"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultCustomerPageSize = 20
+	defaultCustomerSortBy   = "created_at"
 )
 
 type CustomerUseCase interface {
-	GetCustomerProfileByID(id string) (*model.CustomerResponse, error)
-	GetCustomerProfileByNIK(nik string) (*model.CustomerResponse, error)
+	GetCustomerProfileByID(ctx context.Context, id string) (*model.CustomerResponse, error)
+	GetCustomerProfileByNIK(ctx context.Context, nik string) (*model.CustomerResponse, error)
+	SearchCustomers(ctx context.Context, req *model.SearchCustomersRequest) (*model.CustomerListResponse, error)
+	RequestPhoneVerification(ctx context.Context, customerID string, req *model.RequestPhoneVerificationRequest) error
+	VerifyPhone(ctx context.Context, customerID string, req *model.VerifyPhoneRequest) (*model.CustomerResponse, error)
 }
 
 type customerUseCase struct {
-	repo domain.CustomerRepository
+	repo       domain.CustomerRepository
+	otpService OTPService
+	validator  *validator.Validate
+}
+
+// Opaque to clients, pinned to the sort it was issued for
+type customerPageCursor struct {
+	SortBy    string    `json:"sort_by"`
+	SortOrder string    `json:"sort_order"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	FullName  string    `json:"full_name"`
+	Salary    float64   `json:"salary"`
 }
 
-func NewCustomerUseCase(customerRepo domain.CustomerRepository) *customerUseCase {
+func NewCustomerUseCase(customerRepo domain.CustomerRepository, otpService OTPService) *customerUseCase {
 	return &customerUseCase{
-		repo: customerRepo,
+		repo:       customerRepo,
+		otpService: otpService,
+		validator:  validator.New(),
 	}
 }
 
-func (uc *customerUseCase) GetCustomerProfileByID(id string) (*model.CustomerResponse, error) {
-	customer, err := uc.repo.FindByID(id)
+func (uc *customerUseCase) GetCustomerProfileByID(ctx context.Context, id string) (*model.CustomerResponse, error) {
+	customer, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, domain.ErrNotFound
@@ -31,22 +59,25 @@ func (uc *customerUseCase) GetCustomerProfileByID(id string) (*model.CustomerRes
 	}
 
 	return &model.CustomerResponse{
-		ID:          customer.ID,
-		NIK:         customer.NIK,
-		FullName:    customer.FullName,
-		LegalName:   customer.LegalName,
-		BirthPlace:  customer.BirthPlace,
-		BirthDate:   customer.BirthDate,
-		Salary:      customer.Salary,
-		KTPPhoto:    customer.KTPPhoto,
-		SelfiePhoto: customer.SelfiePhoto,
-		CreatedAt:   customer.CreatedAt,
-		UpdatedAt:   customer.UpdatedAt,
+		ID:            customer.ID,
+		NIK:           customer.NIK,
+		FullName:      customer.FullName,
+		LegalName:     customer.LegalName,
+		BirthPlace:    customer.BirthPlace,
+		BirthDate:     customer.BirthDate,
+		Salary:        customer.Salary,
+		KTPPhoto:      customer.KTPPhoto,
+		SelfiePhoto:   customer.SelfiePhoto,
+		KYCStatus:     customer.KYCStatus,
+		PhoneNumber:   customer.PhoneNumber,
+		PhoneVerified: customer.PhoneVerifiedAt != nil,
+		CreatedAt:     customer.CreatedAt,
+		UpdatedAt:     customer.UpdatedAt,
 	}, nil
 }
 
-func (uc *customerUseCase) GetCustomerProfileByNIK(nik string) (*model.CustomerResponse, error) {
-	customer, err := uc.repo.FindByNIK(nik)
+func (uc *customerUseCase) GetCustomerProfileByNIK(ctx context.Context, nik string) (*model.CustomerResponse, error) {
+	customer, err := uc.repo.FindByNIK(ctx, nik)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			return nil, domain.ErrNotFound
@@ -55,16 +86,176 @@ func (uc *customerUseCase) GetCustomerProfileByNIK(nik string) (*model.CustomerR
 	}
 
 	return &model.CustomerResponse{
-		ID:          customer.ID,
-		NIK:         customer.NIK,
-		FullName:    customer.FullName,
-		LegalName:   customer.LegalName,
-		BirthPlace:  customer.BirthPlace,
-		BirthDate:   customer.BirthDate,
-		Salary:      customer.Salary,
-		KTPPhoto:    customer.KTPPhoto,
-		SelfiePhoto: customer.SelfiePhoto,
-		CreatedAt:   customer.CreatedAt,
-		UpdatedAt:   customer.UpdatedAt,
+		ID:            customer.ID,
+		NIK:           customer.NIK,
+		FullName:      customer.FullName,
+		LegalName:     customer.LegalName,
+		BirthPlace:    customer.BirthPlace,
+		BirthDate:     customer.BirthDate,
+		Salary:        customer.Salary,
+		KTPPhoto:      customer.KTPPhoto,
+		SelfiePhoto:   customer.SelfiePhoto,
+		KYCStatus:     customer.KYCStatus,
+		PhoneNumber:   customer.PhoneNumber,
+		PhoneVerified: customer.PhoneVerifiedAt != nil,
+		CreatedAt:     customer.CreatedAt,
+		UpdatedAt:     customer.UpdatedAt,
 	}, nil
 }
+
+func (uc *customerUseCase) SearchCustomers(ctx context.Context, req *model.SearchCustomersRequest) (*model.CustomerListResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	filter := domain.CustomerFilter{
+		Name:               req.Name,
+		NIKPrefix:          req.NIKPrefix,
+		MinSalary:          req.MinSalary,
+		MaxSalary:          req.MaxSalary,
+		KYCStatus:          req.KYCStatus,
+		HasActiveContracts: req.HasActiveContracts,
+		ActiveAsOf:         time.Now(),
+		SortBy:             req.SortBy,
+		SortDesc:           req.SortOrder == "desc",
+		Limit:              req.Limit,
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = defaultCustomerSortBy
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultCustomerPageSize
+	}
+	sortOrder := "asc"
+	if filter.SortDesc {
+		sortOrder = "desc"
+	}
+
+	if req.RegisteredFrom != "" {
+		from, _ := time.Parse("2006-01-02", req.RegisteredFrom) // Format already validated
+		filter.RegisteredFrom = &from
+	}
+	if req.RegisteredTo != "" {
+		to, _ := time.Parse("2006-01-02", req.RegisteredTo)
+		to = to.AddDate(0, 0, 1) // Include the whole end day
+		filter.RegisteredTo = &to
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCustomerCursor(req.Cursor)
+		if err != nil || cursor.SortBy != filter.SortBy || cursor.SortOrder != sortOrder {
+			return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+		}
+		filter.After = &domain.CustomerCursor{
+			ID:        cursor.ID,
+			CreatedAt: cursor.CreatedAt,
+			FullName:  cursor.FullName,
+			Salary:    cursor.Salary,
+		}
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	customers, err := uc.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to search customers: %v", domain.ErrInternalServerError, err)
+	}
+
+	res := &model.CustomerListResponse{Data: []model.CustomerResponse{}}
+	if len(customers) > limit {
+		customers = customers[:limit]
+		last := customers[limit-1]
+		res.NextCursor = encodeCustomerCursor(customerPageCursor{
+			SortBy:    filter.SortBy,
+			SortOrder: sortOrder,
+			ID:        last.ID,
+			CreatedAt: last.CreatedAt,
+			FullName:  last.FullName,
+			Salary:    last.Salary,
+		})
+	}
+
+	for _, customer := range customers {
+		res.Data = append(res.Data, model.CustomerResponse{
+			ID:            customer.ID,
+			NIK:           customer.NIK,
+			FullName:      customer.FullName,
+			LegalName:     customer.LegalName,
+			BirthPlace:    customer.BirthPlace,
+			BirthDate:     customer.BirthDate,
+			Salary:        customer.Salary,
+			KTPPhoto:      customer.KTPPhoto,
+			SelfiePhoto:   customer.SelfiePhoto,
+			KYCStatus:     customer.KYCStatus,
+			PhoneNumber:   customer.PhoneNumber,
+			PhoneVerified: customer.PhoneVerifiedAt != nil,
+			CreatedAt:     customer.CreatedAt,
+			UpdatedAt:     customer.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// The number only replaces the current one once the code sent to it has been confirmed
+func (uc *customerUseCase) RequestPhoneVerification(ctx context.Context, customerID string, req *model.RequestPhoneVerificationRequest) error {
+	if err := uc.validator.Struct(req); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	if _, err := uc.repo.FindByID(ctx, customerID); err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("%w: failed to get customer: %v", domain.ErrInternalServerError, err)
+	}
+
+	return uc.otpService.Send(ctx, domain.OTPPurposePhoneVerification, customerID, req.PhoneNumber, req.PhoneNumber)
+}
+
+func (uc *customerUseCase) VerifyPhone(ctx context.Context, customerID string, req *model.VerifyPhoneRequest) (*model.CustomerResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	phoneNumber, err := uc.otpService.Verify(ctx, domain.OTPPurposePhoneVerification, customerID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.repo.FindByID(ctx, customerID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get customer: %v", domain.ErrInternalServerError, err)
+	}
+
+	now := time.Now()
+	customer.PhoneNumber = phoneNumber
+	customer.PhoneVerifiedAt = &now
+	if err := uc.repo.Update(ctx, customer); err != nil {
+		return nil, fmt.Errorf("%w: failed to update phone number: %v", domain.ErrInternalServerError, err)
+	}
+
+	return uc.GetCustomerProfileByID(ctx, customerID)
+}
+
+func encodeCustomerCursor(cursor customerPageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCustomerCursor(encoded string) (*customerPageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &customerPageCursor{}
+	if err := json.Unmarshal(raw, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
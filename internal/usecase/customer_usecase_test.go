@@ -1,10 +1,12 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
 	"xyz-multifinance-api/internal/usecase"
 	"xyz-multifinance-api/test/mock"
 
@@ -16,7 +18,7 @@ func TestCustomerUseCase_GetCustomerProfile(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
-	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo)
+	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo, nil)
 
 	testCustomerID := "test-customer-id-123"
 	testCustomer := &domain.Customer{
@@ -29,9 +31,9 @@ func TestCustomerUseCase_GetCustomerProfile(t *testing.T) {
 
 	// Test case 1: Successful retrieval
 	t.Run("success_get_customer_by_id", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(testCustomer, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(testCustomer, nil).Times(1)
 
-		res, err := customerUseCase.GetCustomerProfileByID(testCustomerID)
+		res, err := customerUseCase.GetCustomerProfileByID(context.Background(), testCustomerID)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -47,9 +49,9 @@ func TestCustomerUseCase_GetCustomerProfile(t *testing.T) {
 
 	// Test case 2: Customer not found
 	t.Run("customer_not_found_by_id", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID("non-existent-id").Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), "non-existent-id").Return(nil, domain.ErrNotFound).Times(1)
 
-		_, err := customerUseCase.GetCustomerProfileByID("non-existent-id")
+		_, err := customerUseCase.GetCustomerProfileByID(context.Background(), "non-existent-id")
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -58,9 +60,9 @@ func TestCustomerUseCase_GetCustomerProfile(t *testing.T) {
 
 	// Test case 3: Repository error
 	t.Run("repository_error_get_customer_by_id", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByID(testCustomerID).Return(nil, errors.New("db connection failed")).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(nil, errors.New("db connection failed")).Times(1)
 
-		_, err := customerUseCase.GetCustomerProfileByID(testCustomerID)
+		_, err := customerUseCase.GetCustomerProfileByID(context.Background(), testCustomerID)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
@@ -73,7 +75,7 @@ func TestCustomerUseCase_GetCustomerProfileByNIK(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
-	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo)
+	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo, nil)
 
 	testNIK := "1234567890123456"
 	testCustomer := &domain.Customer{
@@ -86,9 +88,9 @@ func TestCustomerUseCase_GetCustomerProfileByNIK(t *testing.T) {
 
 	// Test case 1: Successful retrieval by NIK
 	t.Run("success_get_customer_by_nik", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByNIK(testNIK).Return(testCustomer, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), testNIK).Return(testCustomer, nil).Times(1)
 
-		res, err := customerUseCase.GetCustomerProfileByNIK(testNIK)
+		res, err := customerUseCase.GetCustomerProfileByNIK(context.Background(), testNIK)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -103,9 +105,9 @@ func TestCustomerUseCase_GetCustomerProfileByNIK(t *testing.T) {
 
 	// Test case 2: Customer not found by NIK
 	t.Run("customer_not_found_by_nik", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByNIK("non-existent-nik").Return(nil, domain.ErrNotFound).Times(1)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), "non-existent-nik").Return(nil, domain.ErrNotFound).Times(1)
 
-		_, err := customerUseCase.GetCustomerProfileByNIK("non-existent-nik")
+		_, err := customerUseCase.GetCustomerProfileByNIK(context.Background(), "non-existent-nik")
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound, got %v", err)
@@ -114,12 +116,148 @@ func TestCustomerUseCase_GetCustomerProfileByNIK(t *testing.T) {
 
 	// Test case 3: Repository error for NIK
 	t.Run("repository_error_get_customer_by_nik", func(t *testing.T) {
-		mockCustomerRepo.EXPECT().FindByNIK(testNIK).Return(nil, errors.New("db error")).Times(1)
+		mockCustomerRepo.EXPECT().FindByNIK(gomock.Any(), testNIK).Return(nil, errors.New("db error")).Times(1)
 
-		_, err := customerUseCase.GetCustomerProfileByNIK(testNIK)
+		_, err := customerUseCase.GetCustomerProfileByNIK(context.Background(), testNIK)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError, got %v", err)
 		}
 	})
 }
+
+func TestCustomerUseCase_SearchCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo, nil)
+
+	customers := []domain.Customer{
+		{ID: "customer-1", FullName: "Alice", CreatedAt: time.Now().Add(-2 * time.Hour)},
+		{ID: "customer-2", FullName: "Bob", CreatedAt: time.Now().Add(-time.Hour)},
+		{ID: "customer-3", FullName: "Carol", CreatedAt: time.Now()},
+	}
+
+	// Test case 1: Full page returns a cursor for the next one
+	t.Run("success_first_page_with_cursor", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter domain.CustomerFilter) ([]domain.Customer, error) {
+			if filter.Limit != 3 || filter.SortBy != "created_at" || filter.NIKPrefix != "3201" {
+				t.Errorf("Unexpected filter %+v", filter)
+			}
+			return customers, nil
+		}).Times(1)
+
+		res, err := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{NIKPrefix: "3201", Limit: 2})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(res.Data) != 2 {
+			t.Fatalf("Expected 2 customers, got %d", len(res.Data))
+		}
+		if res.NextCursor == "" {
+			t.Fatal("Expected a next cursor, got empty")
+		}
+
+		// Cursor continues after the last returned row
+		mockCustomerRepo.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter domain.CustomerFilter) ([]domain.Customer, error) {
+			if filter.After == nil || filter.After.ID != "customer-2" {
+				t.Errorf("Expected cursor after customer-2, got %+v", filter.After)
+			}
+			return customers[2:], nil
+		}).Times(1)
+
+		next, err := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{NIKPrefix: "3201", Limit: 2, Cursor: res.NextCursor})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(next.Data) != 1 || next.NextCursor != "" {
+			t.Errorf("Expected last page with 1 customer and no cursor, got %d and %q", len(next.Data), next.NextCursor)
+		}
+	})
+
+	// Test case 2: Cursor issued for another sort order
+	t.Run("cursor_sort_mismatch", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().Search(gomock.Any(), gomock.Any()).Return(customers, nil).Times(1)
+		res, _ := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{Limit: 1})
+
+		_, err := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{Limit: 1, SortBy: "salary", Cursor: res.NextCursor})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Invalid filter value
+	t.Run("invalid_kyc_status", func(t *testing.T) {
+		_, err := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{KYCStatus: "unknown"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Repository error
+	t.Run("repository_error_search", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, errors.New("db connection failed")).Times(1)
+
+		_, err := customerUseCase.SearchCustomers(context.Background(), &model.SearchCustomersRequest{})
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+}
+
+func TestCustomerUseCase_PhoneVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockOTPService := mock.NewMockOTPService(ctrl)
+	customerUseCase := usecase.NewCustomerUseCase(mockCustomerRepo, mockOTPService)
+
+	testCustomer := &domain.Customer{ID: "phone-cust-id-123", NIK: "8888888888888888"}
+	phone := "+6281234567890"
+
+	// Test case 1: Code goes to the new number, which is carried as the payload
+	t.Run("success_request_verification", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomer.ID).Return(testCustomer, nil).Times(1)
+		mockOTPService.EXPECT().Send(gomock.Any(), domain.OTPPurposePhoneVerification, testCustomer.ID, phone, phone).Return(nil).Times(1)
+
+		err := customerUseCase.RequestPhoneVerification(context.Background(), testCustomer.ID, &model.RequestPhoneVerificationRequest{PhoneNumber: phone})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 2: Number that is not E.164
+	t.Run("invalid_phone_number", func(t *testing.T) {
+		mockOTPService.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := customerUseCase.RequestPhoneVerification(context.Background(), testCustomer.ID, &model.RequestPhoneVerificationRequest{PhoneNumber: "081234"})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Confirmed code stores the number as verified
+	t.Run("success_verify_phone", func(t *testing.T) {
+		mockOTPService.EXPECT().Verify(gomock.Any(), domain.OTPPurposePhoneVerification, testCustomer.ID, "123456").Return(phone, nil).Times(1)
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomer.ID).Return(testCustomer, nil).Times(2)
+		mockCustomerRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		res, err := customerUseCase.VerifyPhone(context.Background(), testCustomer.ID, &model.VerifyPhoneRequest{Code: "123456"})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.PhoneNumber != phone || !res.PhoneVerified {
+			t.Errorf("Expected verified phone %s, got %s (verified %v)", phone, res.PhoneNumber, res.PhoneVerified)
+		}
+	})
+}
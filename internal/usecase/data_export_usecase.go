@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	dataExportQueueSize = 100
+	auditExportPageSize = 500
+	// Exports untouched for this long were missed by the queue, e.g. it was full or the instance stopped
+	dataExportSweepAfter = 2 * time.Minute
+	dataExportSweepBatch = 50
+	dataExportSweepLease = time.Minute
+)
+
+// Data categories of the export request that this system does not record. Exports list them as not
+// included so customers know to ask the servicing system for them.
+var dataExportNotRecorded = []string{"installments", "payments", "consents"}
+
+type DataExportUseCase interface {
+	RequestExport(ctx context.Context, customerID string) (*model.DataExportResponse, error)
+	GetExport(ctx context.Context, customerID, exportID string) (*model.DataExportResponse, error)
+	ProcessExport(ctx context.Context, exportID string) error
+	SweepExports(ctx context.Context) error
+	GetExportArchive(ctx context.Context, customerID, exportID string, req *model.DataExportDownloadRequest) (string, error)
+	RunWorker(stop <-chan struct{})
+}
+
+type dataExportUseCase struct {
+	exportRepo      domain.DataExportRepository
+	customerRepo    domain.CustomerRepository
+	creditLimitRepo domain.CreditLimitRepository
+	transactionRepo domain.TransactionRepository
+	certificateRepo domain.ErasureCertificateRepository
+	auditRepo       domain.AuditRepository
+	locker          domain.Locker
+	cfg             *config.Config
+	validator       *validator.Validate
+	queue           chan string
+}
+
+func NewDataExportUseCase(
+	exportRepo domain.DataExportRepository,
+	customerRepo domain.CustomerRepository,
+	creditLimitRepo domain.CreditLimitRepository,
+	transactionRepo domain.TransactionRepository,
+	certificateRepo domain.ErasureCertificateRepository,
+	auditRepo domain.AuditRepository,
+	locker domain.Locker,
+	cfg *config.Config,
+) DataExportUseCase {
+	return &dataExportUseCase{
+		exportRepo:      exportRepo,
+		customerRepo:    customerRepo,
+		creditLimitRepo: creditLimitRepo,
+		transactionRepo: transactionRepo,
+		certificateRepo: certificateRepo,
+		auditRepo:       auditRepo,
+		locker:          locker,
+		cfg:             cfg,
+		validator:       validator.New(),
+		queue:           make(chan string, dataExportQueueSize),
+	}
+}
+
+func (uc *dataExportUseCase) RequestExport(ctx context.Context, customerID string) (*model.DataExportResponse, error) {
+	if _, err := uc.customerRepo.FindByID(ctx, customerID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, customerID)
+		}
+		return nil, fmt.Errorf("%w: failed to verify customer existence: %v", domain.ErrInternalServerError, err)
+	}
+
+	export := &domain.DataExport{
+		CustomerID: customerID,
+		Status:     domain.DataExportStatusPending,
+	}
+	if err := uc.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("%w: failed to create data export: %v", domain.ErrInternalServerError, err)
+	}
+
+	// Never block the request on a full queue, the export stays pending instead
+	select {
+	case uc.queue <- export.ID:
+	default:
+		log.Printf("Data export queue full, export %s left pending", export.ID)
+	}
+
+	return uc.toDataExportResponse(export), nil
+}
+
+func (uc *dataExportUseCase) GetExport(ctx context.Context, customerID, exportID string) (*model.DataExportResponse, error) {
+	export, err := uc.findOwnedExport(ctx, customerID, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toDataExportResponse(export), nil
+}
+
+// ProcessExport holds the customer's lock while it builds the archive, so an erasure cannot run halfway through
+func (uc *dataExportUseCase) ProcessExport(ctx context.Context, exportID string) error {
+	export, err := uc.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		return fmt.Errorf("failed to load data export %s: %w", exportID, err)
+	}
+
+	return withCustomerLock(ctx, uc.locker, export.CustomerID, func(ctx context.Context) error {
+		// Queue and sweep may both hand over the same export, the first one to get the lock builds it.
+		// An export still marked processing here was left behind by a worker that stopped halfway.
+		export, err := uc.exportRepo.FindByID(ctx, exportID)
+		if err != nil {
+			return fmt.Errorf("failed to reload data export %s: %w", exportID, err)
+		}
+		if export.Status != domain.DataExportStatusPending && export.Status != domain.DataExportStatusProcessing {
+			return nil
+		}
+		return uc.processExport(ctx, export)
+	})
+}
+
+// SweepExports builds exports the queue missed and deletes archives whose link has expired, so personal
+// data does not stay on disk. One instance sweeps at a time.
+func (uc *dataExportUseCase) SweepExports(ctx context.Context) error {
+	err := RunExclusive(ctx, uc.locker, "data_export_sweep", dataExportSweepLease, func(ctx context.Context) error {
+		unfinished, err := uc.exportRepo.FindUnfinished(ctx, time.Now().Add(-dataExportSweepAfter), dataExportSweepBatch)
+		if err != nil {
+			return err
+		}
+		for _, export := range unfinished {
+			if err := uc.ProcessExport(ctx, export.ID); err != nil {
+				log.Printf("Data export sweep: %v", err) // Picked up again by the next sweep
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		expired, err := uc.exportRepo.FindExpired(ctx, time.Now(), dataExportSweepBatch)
+		if err != nil {
+			return err
+		}
+		for i := range expired {
+			if err := uc.purgeArchive(ctx, &expired[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to sweep data exports: %v", domain.ErrInternalServerError, err)
+	}
+	return nil
+}
+
+func (uc *dataExportUseCase) purgeArchive(ctx context.Context, export *domain.DataExport) error {
+	if export.FilePath != "" {
+		if err := os.Remove(export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete archive of data export %s: %w", export.ID, err)
+		}
+	}
+
+	export.Status = domain.DataExportStatusExpired
+	export.FilePath = ""
+	if err := uc.exportRepo.Update(ctx, export); err != nil {
+		return fmt.Errorf("failed to mark data export %s as expired: %w", export.ID, err)
+	}
+	return nil
+}
+
+func (uc *dataExportUseCase) processExport(ctx context.Context, export *domain.DataExport) error {
+	exportID := export.ID
+	export.Status = domain.DataExportStatusProcessing
+	if err := uc.exportRepo.Update(ctx, export); err != nil {
+		return fmt.Errorf("failed to mark data export %s as processing: %w", exportID, err)
+	}
+
+	filePath, err := uc.writeArchive(ctx, export)
+	if err != nil {
+		os.Remove(uc.archivePath(export)) // Half-written, if it got that far
+		export.Status = domain.DataExportStatusFailed
+		export.ErrorMessage = "failed to build export archive"
+		if updateErr := uc.exportRepo.Update(ctx, export); updateErr != nil {
+			log.Printf("Failed to mark data export %s as failed: %v", exportID, updateErr)
+		}
+		return fmt.Errorf("failed to build data export %s: %w", exportID, err)
+	}
+
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(uc.cfg.ExportLinkExpiry)
+	export.Status = domain.DataExportStatusCompleted
+	export.FilePath = filePath
+	export.CompletedAt = &completedAt
+	export.ExpiresAt = &expiresAt
+	if err := uc.exportRepo.Update(ctx, export); err != nil {
+		return fmt.Errorf("failed to mark data export %s as completed: %w", exportID, err)
+	}
+
+	return nil
+}
+
+func (uc *dataExportUseCase) GetExportArchive(ctx context.Context, customerID, exportID string, req *model.DataExportDownloadRequest) (string, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return "", domain.ErrInvalidInput
+	}
+
+	export, err := uc.findOwnedExport(ctx, customerID, exportID)
+	if err != nil {
+		return "", err
+	}
+
+	if !hmac.Equal([]byte(req.Signature), []byte(uc.signDownload(export.ID, export.CustomerID, req.Expires))) {
+		return "", fmt.Errorf("%w: invalid download signature", domain.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	if now.Unix() > req.Expires || export.ExpiresAt == nil || now.After(*export.ExpiresAt) {
+		return "", fmt.Errorf("%w: download link has expired", domain.ErrExpired)
+	}
+	if export.Status != domain.DataExportStatusCompleted {
+		return "", fmt.Errorf("%w: data export is not ready", domain.ErrNotFound)
+	}
+
+	return export.FilePath, nil
+}
+
+func (uc *dataExportUseCase) RunWorker(stop <-chan struct{}) {
+	ctx := context.Background()
+	ticker := time.NewTicker(uc.cfg.ExportSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case exportID := <-uc.queue:
+			if err := uc.ProcessExport(ctx, exportID); err != nil {
+				log.Printf("Data export worker: %v", err)
+			}
+		case <-ticker.C:
+			if err := uc.SweepExports(ctx); err != nil {
+				log.Printf("Data export worker: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Customers may only see their own exports, anything else is reported as not found
+func (uc *dataExportUseCase) findOwnedExport(ctx context.Context, customerID, exportID string) (*domain.DataExport, error) {
+	export, err := uc.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get data export: %v", domain.ErrInternalServerError, err)
+	}
+	if export.CustomerID != customerID {
+		return nil, domain.ErrNotFound
+	}
+
+	return export, nil
+}
+
+func (uc *dataExportUseCase) writeArchive(ctx context.Context, export *domain.DataExport) (string, error) {
+	customer, err := uc.customerRepo.FindByID(ctx, export.CustomerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load customer: %w", err)
+	}
+
+	creditLimits, err := uc.creditLimitRepo.GetCreditLimitsByCustomerID(ctx, export.CustomerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load credit limits: %w", err)
+	}
+
+	transactions, err := uc.transactionRepo.GetTransactionsByCustomerID(ctx, export.CustomerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load transactions: %w", err)
+	}
+
+	certificate, err := uc.certificateRepo.FindByCustomerID(ctx, export.CustomerID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return "", fmt.Errorf("failed to load erasure certificate: %w", err)
+	}
+
+	auditEntries, err := uc.loadAuditEntries(ctx, export.CustomerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load audit entries: %w", err)
+	}
+
+	// Response model leaves out the password hash
+	profile := model.CustomerResponse{
+		ID:            customer.ID,
+		NIK:           customer.NIK,
+		FullName:      customer.FullName,
+		LegalName:     customer.LegalName,
+		BirthPlace:    customer.BirthPlace,
+		BirthDate:     customer.BirthDate,
+		Salary:        customer.Salary,
+		KTPPhoto:      customer.KTPPhoto,
+		SelfiePhoto:   customer.SelfiePhoto,
+		KYCStatus:     customer.KYCStatus,
+		PhoneNumber:   customer.PhoneNumber,
+		PhoneVerified: customer.PhoneVerifiedAt != nil,
+		CreatedAt:     customer.CreatedAt,
+		UpdatedAt:     customer.UpdatedAt,
+	}
+
+	files := map[string]interface{}{
+		"manifest.json": map[string]interface{}{
+			"export_id":    export.ID,
+			"customer_id":  export.CustomerID,
+			"generated_at": time.Now(),
+			"files":        []string{"profile.json", "credit_limits.json", "transactions.json", "erasure_certificate.json", "audit_entries.json"},
+			"not_recorded": dataExportNotRecorded,
+		},
+		"profile.json":             profile,
+		"credit_limits.json":       creditLimits,
+		"transactions.json":        transactions,
+		"erasure_certificate.json": certificate,
+		"audit_entries.json":       auditEntries,
+	}
+
+	if err := os.MkdirAll(uc.cfg.ExportDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	filePath := uc.archivePath(export)
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export archive: %w", err)
+	}
+	if err := writeZip(file, files); err != nil {
+		file.Close()
+		return "", err
+	}
+	// The export is only marked completed once the archive is known to be complete on disk
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close export archive: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// writeZip writes each file as indented JSON
+func writeZip(w io.Writer, files map[string]interface{}) error {
+	archive := zip.NewWriter(w)
+	for name, content := range files {
+		entry, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(content); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize export archive: %w", err)
+	}
+	return nil
+}
+
+// loadAuditEntries returns the entries of requests the customer made and of requests about the customer,
+// oldest first. Staff who acted on the customer are named by role only.
+func (uc *dataExportUseCase) loadAuditEntries(ctx context.Context, customerID string) ([]model.AuditEntryResponse, error) {
+	byID := make(map[uint64]domain.AuditEntry)
+	for _, filter := range []domain.AuditFilter{
+		{ActorID: customerID},
+		{EntityType: "customer", EntityID: customerID},
+	} {
+		filter.Limit = auditExportPageSize
+		for {
+			page, err := uc.auditRepo.Find(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range page {
+				byID[entry.ID] = entry
+			}
+			if len(page) < filter.Limit {
+				break
+			}
+			filter.BeforeID = page[len(page)-1].ID
+		}
+	}
+
+	entries := make([]model.AuditEntryResponse, 0, len(byID))
+	for _, entry := range byID {
+		if entry.ActorID != customerID {
+			entry.ActorID, entry.IPAddress = "", ""
+		}
+		entries = append(entries, toAuditEntryResponse(entry))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func (uc *dataExportUseCase) archivePath(export *domain.DataExport) string {
+	return filepath.Join(uc.cfg.ExportDir, export.ID+".zip")
+}
+
+func (uc *dataExportUseCase) signDownload(exportID, customerID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(uc.cfg.ExportLinkSecret))
+	fmt.Fprintf(mac, "%s|%s|%d", exportID, customerID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (uc *dataExportUseCase) toDataExportResponse(export *domain.DataExport) *model.DataExportResponse {
+	res := &model.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		NotRecorded: dataExportNotRecorded,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	if export.Status == domain.DataExportStatusCompleted && export.ExpiresAt != nil && time.Now().Before(*export.ExpiresAt) {
+		expires := export.ExpiresAt.Unix()
+		res.DownloadURL = fmt.Sprintf("/api/v1/customers/me/exports/%s/download?expires=%d&signature=%s",
+			export.ID, expires, uc.signDownload(export.ID, export.CustomerID, expires))
+	}
+
+	return res
+}
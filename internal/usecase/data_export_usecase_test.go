@@ -0,0 +1,260 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDataExportUseCase_RequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExportRepository(ctrl)
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	cfg := &config.Config{ExportDir: t.TempDir(), ExportLinkSecret: "test-export-secret", ExportLinkExpiry: time.Hour}
+
+	dataExportUseCase := usecase.NewDataExportUseCase(mockExportRepo, mockCustomerRepo, nil, nil, nil, nil, nil, cfg)
+
+	testCustomerID := "test-customer-id-123"
+
+	// Test case 1: Export is queued as pending
+	t.Run("success_request_export", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(&domain.Customer{ID: testCustomerID}, nil).Times(1)
+		mockExportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, export *domain.DataExport) error {
+			export.ID = "export-1"
+			return nil
+		}).Times(1)
+
+		res, err := dataExportUseCase.RequestExport(context.Background(), testCustomerID)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Status != domain.DataExportStatusPending {
+			t.Errorf("Expected status %s, got %s", domain.DataExportStatusPending, res.Status)
+		}
+		if res.DownloadURL != "" {
+			t.Error("Expected no download URL before the export completes")
+		}
+		if len(res.NotRecorded) == 0 {
+			t.Error("Expected the categories this system does not record to be listed")
+		}
+	})
+
+	// Test case 2: Customer not found
+	t.Run("customer_not_found", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(nil, domain.ErrNotFound).Times(1)
+
+		_, err := dataExportUseCase.RequestExport(context.Background(), testCustomerID)
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestDataExportUseCase_ProcessAndDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExportRepository(ctrl)
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockCreditLimitRepo := mock.NewMockCreditLimitRepository(ctrl)
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockCertificateRepo := mock.NewMockErasureCertificateRepository(ctrl)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	cfg := &config.Config{ExportDir: t.TempDir(), ExportLinkSecret: "test-export-secret", ExportLinkExpiry: time.Hour}
+
+	dataExportUseCase := usecase.NewDataExportUseCase(mockExportRepo, mockCustomerRepo, mockCreditLimitRepo, mockTransactionRepo, mockCertificateRepo, mockAuditRepo, newFreeLocker(ctrl), cfg)
+
+	testCustomerID := "test-customer-id-123"
+	export := &domain.DataExport{ID: "export-1", CustomerID: testCustomerID, Status: domain.DataExportStatusPending}
+
+	mockExportRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(export, nil).AnyTimes()
+	mockExportRepo.EXPECT().Update(gomock.Any(), export).Return(nil).AnyTimes()
+
+	// Test case 1: Archive contains every data category
+	t.Run("success_process_export", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByID(gomock.Any(), testCustomerID).Return(&domain.Customer{ID: testCustomerID, Password: "hash"}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitsByCustomerID(gomock.Any(), testCustomerID).Return([]domain.CreditLimit{{CustomerID: testCustomerID}}, nil).Times(1)
+		mockTransactionRepo.EXPECT().GetTransactionsByCustomerID(gomock.Any(), testCustomerID).Return([]domain.Transaction{}, nil).Times(1)
+		mockCertificateRepo.EXPECT().FindByCustomerID(gomock.Any(), testCustomerID).Return(nil, domain.ErrNotFound).Times(1)
+		mockAuditRepo.EXPECT().Find(gomock.Any(), domain.AuditFilter{ActorID: testCustomerID, Limit: 500}).
+			Return([]domain.AuditEntry{{ID: 3, ActorType: domain.SubjectTypeCustomer, ActorID: testCustomerID, IPAddress: "10.0.0.1"}}, nil).Times(1)
+		mockAuditRepo.EXPECT().Find(gomock.Any(), domain.AuditFilter{EntityType: "customer", EntityID: testCustomerID, Limit: 500}).
+			Return([]domain.AuditEntry{{ID: 7, ActorType: domain.SubjectTypeStaff, ActorID: "staff-id-123", IPAddress: "10.0.0.2"}}, nil).Times(1)
+
+		if err := dataExportUseCase.ProcessExport(context.Background(), export.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if export.Status != domain.DataExportStatusCompleted {
+			t.Fatalf("Expected status %s, got %s", domain.DataExportStatusCompleted, export.Status)
+		}
+
+		archive, err := zip.OpenReader(export.FilePath)
+		if err != nil {
+			t.Fatalf("Expected a readable zip archive, got %v", err)
+		}
+		defer archive.Close()
+		if len(archive.File) != 6 {
+			t.Errorf("Expected 6 files in archive, got %d", len(archive.File))
+		}
+
+		auditFile, err := archive.Open("audit_entries.json")
+		if err != nil {
+			t.Fatalf("Expected audit_entries.json in archive, got %v", err)
+		}
+		defer auditFile.Close()
+		var entries []model.AuditEntryResponse
+		if err := json.NewDecoder(auditFile).Decode(&entries); err != nil {
+			t.Fatalf("Expected audit entries JSON, got %v", err)
+		}
+		if len(entries) != 2 || entries[0].ID != 3 || entries[1].ID != 7 {
+			t.Fatalf("Expected entries 3 and 7 oldest first, got %+v", entries)
+		}
+		if entries[0].IPAddress != "10.0.0.1" || entries[1].ActorID != "" || entries[1].IPAddress != "" {
+			t.Errorf("Expected staff actor details to be left out, got %+v", entries)
+		}
+	})
+
+	// Test case 2: Signed link from the status response downloads the archive
+	t.Run("success_download_with_signed_link", func(t *testing.T) {
+		res, err := dataExportUseCase.GetExport(context.Background(), testCustomerID, export.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		link, err := url.Parse(res.DownloadURL)
+		if err != nil || res.DownloadURL == "" {
+			t.Fatalf("Expected a download URL, got %q", res.DownloadURL)
+		}
+		expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+
+		filePath, err := dataExportUseCase.GetExportArchive(context.Background(), testCustomerID, export.ID, &model.DataExportDownloadRequest{
+			Expires: expires, Signature: link.Query().Get("signature"),
+		})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if filePath != export.FilePath {
+			t.Errorf("Expected file path %s, got %s", export.FilePath, filePath)
+		}
+	})
+
+	// Test case 3: Tampered expiry invalidates the signature
+	t.Run("tampered_signature", func(t *testing.T) {
+		res, _ := dataExportUseCase.GetExport(context.Background(), testCustomerID, export.ID)
+		link, _ := url.Parse(res.DownloadURL)
+
+		_, err := dataExportUseCase.GetExportArchive(context.Background(), testCustomerID, export.ID, &model.DataExportDownloadRequest{
+			Expires: time.Now().Add(24 * time.Hour).Unix(), Signature: link.Query().Get("signature"),
+		})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Another customer cannot see the export
+	t.Run("other_customer_export", func(t *testing.T) {
+		_, err := dataExportUseCase.GetExport(context.Background(), "other-customer-id", export.ID)
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	// Test case 5: Link past its expiry
+	t.Run("expired_link", func(t *testing.T) {
+		res, _ := dataExportUseCase.GetExport(context.Background(), testCustomerID, export.ID)
+		link, _ := url.Parse(res.DownloadURL)
+		expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+
+		expiredAt := time.Now().Add(-time.Minute)
+		export.ExpiresAt = &expiredAt
+
+		_, err := dataExportUseCase.GetExportArchive(context.Background(), testCustomerID, export.ID, &model.DataExportDownloadRequest{
+			Expires: expires, Signature: link.Query().Get("signature"),
+		})
+
+		if !errors.Is(err, domain.ErrExpired) {
+			t.Fatalf("Expected ErrExpired, got %v", err)
+		}
+	})
+}
+
+func TestDataExportUseCase_SweepExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExportRepo := mock.NewMockDataExportRepository(ctrl)
+	cfg := &config.Config{ExportDir: t.TempDir(), ExportLinkSecret: "test-export-secret", ExportLinkExpiry: time.Hour}
+
+	dataExportUseCase := usecase.NewDataExportUseCase(mockExportRepo, nil, nil, nil, nil, nil, newFreeLocker(ctrl), cfg)
+
+	// Test case 1: Export finished by another worker is skipped and expired archive is deleted
+	t.Run("success_sweep_exports", func(t *testing.T) {
+		archivePath := filepath.Join(cfg.ExportDir, "export-2.zip")
+		if err := os.WriteFile(archivePath, []byte("archive"), 0o600); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		finished := &domain.DataExport{ID: "export-1", CustomerID: "customer-1", Status: domain.DataExportStatusCompleted}
+		expired := domain.DataExport{ID: "export-2", CustomerID: "customer-2", Status: domain.DataExportStatusCompleted, FilePath: archivePath}
+
+		mockExportRepo.EXPECT().FindUnfinished(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.DataExport{{ID: "export-1", CustomerID: "customer-1", Status: domain.DataExportStatusPending}}, nil).Times(1)
+		mockExportRepo.EXPECT().FindByID(gomock.Any(), "export-1").Return(finished, nil).Times(2)
+		mockExportRepo.EXPECT().FindExpired(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.DataExport{expired}, nil).Times(1)
+		mockExportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, export *domain.DataExport) error {
+			if export.ID != "export-2" || export.Status != domain.DataExportStatusExpired || export.FilePath != "" {
+				t.Errorf("Expected export-2 to be marked expired without a file, got %+v", export)
+			}
+			return nil
+		}).Times(1)
+
+		if err := dataExportUseCase.SweepExports(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+			t.Errorf("Expected the expired archive to be deleted, got %v", err)
+		}
+	})
+
+	// Test case 2: Another instance is sweeping
+	t.Run("sweep_locked", func(t *testing.T) {
+		mockLocker := mock.NewMockLocker(ctrl)
+		mockLocker.EXPECT().TryAcquire(gomock.Any(), "job:data_export_sweep", gomock.Any()).Return(nil, domain.ErrLockHeld).Times(1)
+		lockedUseCase := usecase.NewDataExportUseCase(mockExportRepo, nil, nil, nil, nil, nil, mockLocker, cfg)
+
+		if err := lockedUseCase.SweepExports(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	// Test case 3: Database error
+	t.Run("database_error", func(t *testing.T) {
+		mockExportRepo.EXPECT().FindUnfinished(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+
+		err := dataExportUseCase.SweepExports(context.Background())
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+)
+
+// Fields pseudonymized on erasure. Financial records stay linked through the customer ID.
+var erasedCustomerFields = []string{"nik", "full_name", "legal_name", "birth_place", "birth_date", "ktp_photo", "selfie_photo", "password"}
+
+const erasedPlaceholder = "ERASED"
+
+type ErasureUseCase interface {
+	EraseCustomerData(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error)
+	GetErasureCertificate(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error)
+}
+
+type erasureUseCase struct {
+	uow             domain.UnitOfWork
+	certificateRepo domain.ErasureCertificateRepository
+	sessionRepo     domain.AuthSessionRepository
+	denylist        domain.TokenDenylist
+	cacheStore      domain.CacheStore
+	locker          domain.Locker
+	cfg             *config.Config
+}
+
+func NewErasureUseCase(
+	uow domain.UnitOfWork,
+	certificateRepo domain.ErasureCertificateRepository,
+	sessionRepo domain.AuthSessionRepository,
+	denylist domain.TokenDenylist,
+	cacheStore domain.CacheStore,
+	locker domain.Locker,
+	cfg *config.Config,
+) ErasureUseCase {
+	return &erasureUseCase{
+		uow:             uow,
+		certificateRepo: certificateRepo,
+		sessionRepo:     sessionRepo,
+		denylist:        denylist,
+		cacheStore:      cacheStore,
+		locker:          locker,
+		cfg:             cfg,
+	}
+}
+
+// EraseCustomerData holds the customer's lock, so no data export of the customer runs meanwhile
+func (uc *erasureUseCase) EraseCustomerData(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error) {
+	var res *model.ErasureCertificateResponse
+	err := withCustomerLock(ctx, uc.locker, customerID, func(ctx context.Context) error {
+		var err error
+		res, err = uc.eraseCustomerData(ctx, customerID)
+		return err
+	})
+	return res, err
+}
+
+func (uc *erasureUseCase) eraseCustomerData(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error) {
+	var certificate *domain.ErasureCertificate
+	var originalNIK string
+
+	err := uc.uow.Do(ctx, func(repos domain.TxRepositories) error {
+		// The row lock keeps CreateTransaction from adding a contract between the check below and the commit
+		customer, err := repos.Customers().FindByIDForUpdate(ctx, customerID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, customerID)
+			}
+			return fmt.Errorf("failed to retrieve customer: %w", err)
+		}
+		if customer.ErasedAt != nil {
+			return domain.ErrAlreadyErased
+		}
+
+		// Contracts must be retained until they mature
+		now := time.Now()
+		activeContracts, err := repos.Transactions().CountActiveByCustomerID(ctx, customerID, now)
+		if err != nil {
+			return fmt.Errorf("failed to check active contracts: %w", err)
+		}
+		if activeContracts > 0 {
+			return fmt.Errorf("%w: %d contract(s) still running", domain.ErrActiveContracts, activeContracts)
+		}
+
+		originalNIK = customer.NIK
+		pseudonymizeCustomer(customer, now)
+
+		if err := repos.Customers().Update(ctx, customer); err != nil {
+			return fmt.Errorf("failed to pseudonymize customer: %w", err)
+		}
+		if err := repos.Customers().UpdatePassword(ctx, customerID, customer.Password); err != nil {
+			return fmt.Errorf("failed to clear customer password: %w", err)
+		}
+
+		certificate = &domain.ErasureCertificate{
+			CustomerID:    customerID,
+			SubjectDigest: subjectDigest(customerID, originalNIK),
+			ErasedFields:  strings.Join(erasedCustomerFields, ","),
+			ErasedAt:      now,
+		}
+		if err := repos.ErasureCertificates().Create(ctx, certificate); err != nil {
+			return fmt.Errorf("failed to write erasure certificate: %w", err)
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, domain.ErrAlreadyErased) {
+		// Finishes a revocation that failed after an earlier erasure committed
+		if err := uc.revokeSessions(ctx, customerID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrAlreadyErased
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrActiveContracts):
+			return nil, err
+		default:
+			return nil, fmt.Errorf("%w: erasure process failed: %v", domain.ErrInternalServerError, err)
+		}
+	}
+
+	// Purge cached PII only after the pseudonymized row is committed. The keys are also deleted by name
+	// in case they were cached without the customer tag.
+	uc.cacheStore.InvalidateTag(ctx, domain.CustomerCacheTag(customerID))
+	uc.cacheStore.Del(ctx, fmt.Sprintf("customer:%s", customerID), fmt.Sprintf("customer_nik:%s", originalNIK))
+
+	if err := uc.revokeSessions(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return toErasureCertificateResponse(certificate), nil
+}
+
+// revokeSessions signs the erased customer out everywhere, their access tokens stop working right away
+func (uc *erasureUseCase) revokeSessions(ctx context.Context, customerID string) error {
+	sessions, err := uc.sessionRepo.FindActiveBySubject(ctx, domain.SubjectTypeCustomer, customerID, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: failed to retrieve sessions: %v", domain.ErrInternalServerError, err)
+	}
+	for _, session := range sessions {
+		if err := uc.sessionRepo.Revoke(ctx, session.ID, time.Now()); err != nil {
+			return fmt.Errorf("%w: failed to revoke session: %v", domain.ErrInternalServerError, err)
+		}
+		if err := uc.denylist.Deny(ctx, session.ID, uc.cfg.AccessTokenExpiry); err != nil {
+			return fmt.Errorf("%w: failed to deny session tokens: %v", domain.ErrInternalServerError, err)
+		}
+	}
+	return nil
+}
+
+func (uc *erasureUseCase) GetErasureCertificate(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error) {
+	certificate, err := uc.certificateRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get erasure certificate: %v", domain.ErrInternalServerError, err)
+	}
+
+	return toErasureCertificateResponse(certificate), nil
+}
+
+func pseudonymizeCustomer(customer *domain.Customer, erasedAt time.Time) {
+	digest := sha256.Sum256([]byte(customer.ID))
+
+	// NIK stays unique and 16 characters long, the leading X never collides with a real NIK
+	customer.NIK = "X" + hex.EncodeToString(digest[:])[:15]
+	customer.FullName = erasedPlaceholder
+	customer.LegalName = erasedPlaceholder
+	customer.BirthPlace = erasedPlaceholder
+	customer.BirthDate = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	customer.KTPPhoto = ""
+	customer.SelfiePhoto = ""
+	customer.PhoneNumber = ""
+	customer.PhoneVerifiedAt = nil
+	customer.Password = "" // Never matches a bcrypt hash, so the account can no longer log in
+	customer.ErasedAt = &erasedAt
+}
+
+func subjectDigest(customerID, nik string) string {
+	digest := sha256.Sum256([]byte(customerID + ":" + nik))
+	return hex.EncodeToString(digest[:])
+}
+
+func toErasureCertificateResponse(certificate *domain.ErasureCertificate) *model.ErasureCertificateResponse {
+	return &model.ErasureCertificateResponse{
+		ID:            certificate.ID,
+		CustomerID:    certificate.CustomerID,
+		SubjectDigest: certificate.SubjectDigest,
+		ErasedFields:  strings.Split(certificate.ErasedFields, ","),
+		ErasedAt:      certificate.ErasedAt,
+	}
+}
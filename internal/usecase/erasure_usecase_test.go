@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/repository"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/test/mock"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestErasureUseCase_EraseCustomerData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := setupTestDB(t)
+
+	mockCacheStore := mock.NewMockCacheStore(ctrl)
+	mockCacheStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", domain.ErrCacheMiss).AnyTimes()
+	mockCacheStore.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().SetWithTags(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	mockSessionRepo := mock.NewMockAuthSessionRepository(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	cfg := &config.Config{AccessTokenExpiry: 15 * time.Minute}
+
+	erasureUseCase := usecase.NewErasureUseCase(repository.NewUnitOfWork(db, mockCacheStore, nil), repository.NewErasureCertificateRepository(db),
+		mockSessionRepo, mockDenylist, mockCacheStore, newFreeLocker(ctrl), cfg)
+
+	resetTables := func() {
+		db.Exec("DELETE FROM `erasure_certificates`")
+		db.Exec("DELETE FROM `transactions`")
+		db.Exec("DELETE FROM `credit_limits`")
+		db.Exec("DELETE FROM `customers`")
+	}
+
+	// Test case 1: Successful erasure, matured contracts stay linked and the customer is signed out everywhere
+	t.Run("success_erase_customer_data", func(t *testing.T) {
+		resetTables()
+
+		testCustomerID := uuid.New().String()
+		testNIK := "2222222222222201"
+		matured := time.Now().AddDate(0, -1, 0)
+
+		customer := &domain.Customer{ID: testCustomerID, NIK: testNIK, FullName: "Erasure Test User", Password: "hash"}
+		transaction := &domain.Transaction{
+			ID: uuid.New().String(), CustomerID: testCustomerID, ContractNumber: "TRX-ERASE-001", TenorMonths: 1, MaturityDate: &matured,
+		}
+		if err := db.Create(customer).Error; err != nil {
+			t.Fatalf("Failed to pre-create customer in SQLite: %v", err)
+		}
+		if err := db.Create(transaction).Error; err != nil {
+			t.Fatalf("Failed to pre-create transaction in SQLite: %v", err)
+		}
+
+		mockCacheStore.EXPECT().InvalidateTag(gomock.Any(), "customer:"+testCustomerID).Return(nil).Times(1)
+		mockCacheStore.EXPECT().Del(gomock.Any(), "customer:"+testCustomerID, "customer_nik:"+testNIK).Return(nil).Times(1)
+		mockCacheStore.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeCustomer, testCustomerID, gomock.Any()).
+			Return([]domain.AuthSession{{ID: "session-1"}}, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), "session-1", gomock.Any()).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), "session-1", cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		res, err := erasureUseCase.EraseCustomerData(context.Background(), testCustomerID)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res == nil || res.SubjectDigest == "" {
+			t.Fatal("Expected a certificate with subject digest, got none")
+		}
+
+		var erased domain.Customer
+		db.First(&erased, "id = ?", testCustomerID)
+		if erased.NIK == testNIK || erased.FullName == customer.FullName || erased.Password != "" {
+			t.Errorf("Expected PII to be pseudonymized, got NIK %s and name %s", erased.NIK, erased.FullName)
+		}
+		if erased.ErasedAt == nil {
+			t.Error("Expected erased_at to be set")
+		}
+
+		var linked domain.Transaction
+		if db.First(&linked, "customer_id = ?", testCustomerID).Error != nil {
+			t.Error("Expected financial records to remain linked by customer ID")
+		}
+	})
+
+	// Test case 2: Active contract blocks erasure
+	t.Run("active_contract_blocks_erasure", func(t *testing.T) {
+		resetTables()
+
+		testCustomerID := uuid.New().String()
+		running := time.Now().AddDate(0, 3, 0)
+
+		customer := &domain.Customer{ID: testCustomerID, NIK: "2222222222222202", FullName: "Active Contract User"}
+		transaction := &domain.Transaction{
+			ID: uuid.New().String(), CustomerID: testCustomerID, ContractNumber: "TRX-ERASE-002", TenorMonths: 3, MaturityDate: &running,
+		}
+		db.Create(customer)
+		db.Create(transaction)
+
+		_, err := erasureUseCase.EraseCustomerData(context.Background(), testCustomerID)
+
+		if !errors.Is(err, domain.ErrActiveContracts) {
+			t.Fatalf("Expected ErrActiveContracts, got %v", err)
+		}
+
+		var unchanged domain.Customer
+		db.First(&unchanged, "id = ?", testCustomerID)
+		if unchanged.FullName != customer.FullName {
+			t.Errorf("Expected customer to remain untouched, got name %s", unchanged.FullName)
+		}
+	})
+
+	// Test case 3: Erasing twice still revokes sessions left over from a failed revocation
+	t.Run("already_erased", func(t *testing.T) {
+		resetTables()
+
+		testCustomerID := uuid.New().String()
+		erasedAt := time.Now()
+		db.Create(&domain.Customer{ID: testCustomerID, NIK: "X000000000000000", FullName: "ERASED", ErasedAt: &erasedAt})
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeCustomer, testCustomerID, gomock.Any()).
+			Return([]domain.AuthSession{{ID: "session-2"}}, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), "session-2", gomock.Any()).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), "session-2", cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		_, err := erasureUseCase.EraseCustomerData(context.Background(), testCustomerID)
+
+		if !errors.Is(err, domain.ErrAlreadyErased) {
+			t.Fatalf("Expected ErrAlreadyErased, got %v", err)
+		}
+	})
+
+	// Test case 4: Customer not found
+	t.Run("customer_not_found", func(t *testing.T) {
+		resetTables()
+
+		_, err := erasureUseCase.EraseCustomerData(context.Background(), uuid.New().String())
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestErasureUseCase_GetErasureCertificate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCertificateRepo := mock.NewMockErasureCertificateRepository(ctrl)
+	mockCacheStore := mock.NewMockCacheStore(ctrl)
+	erasureUseCase := usecase.NewErasureUseCase(nil, mockCertificateRepo, nil, nil, mockCacheStore, nil, &config.Config{})
+
+	testCustomerID := "test-customer-id-123"
+
+	// Test case 1: Certificate exists
+	t.Run("success_get_certificate", func(t *testing.T) {
+		mockCertificateRepo.EXPECT().FindByCustomerID(gomock.Any(), testCustomerID).Return(&domain.ErasureCertificate{
+			ID: "cert-1", CustomerID: testCustomerID, ErasedFields: "nik,full_name",
+		}, nil).Times(1)
+
+		res, err := erasureUseCase.GetErasureCertificate(context.Background(), testCustomerID)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(res.ErasedFields) != 2 {
+			t.Errorf("Expected 2 erased fields, got %d", len(res.ErasedFields))
+		}
+	})
+
+	// Test case 2: No certificate
+	t.Run("certificate_not_found", func(t *testing.T) {
+		mockCertificateRepo.EXPECT().FindByCustomerID(gomock.Any(), testCustomerID).Return(nil, domain.ErrNotFound).Times(1)
+
+		_, err := erasureUseCase.GetErasureCertificate(context.Background(), testCustomerID)
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
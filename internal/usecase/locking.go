@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"xyz-multifinance-api/internal/domain"
+)
+
+const (
+	customerLockLease = 30 * time.Second
+	customerLockWait  = 5 * time.Second // Callers get ErrResourceBusy after waiting this long
+)
+
+// withCustomerLock runs fn while holding the customer's lock, so operations on one customer that span
+// more than one database transaction do not interleave across instances. fn's context also ends when
+// the lock is lost.
+func withCustomerLock(ctx context.Context, locker domain.Locker, customerID string, fn func(ctx context.Context) error) error {
+	waitCtx, cancel := context.WithTimeout(ctx, customerLockWait)
+	defer cancel()
+
+	lock, err := locker.Acquire(waitCtx, domain.CustomerLockKey(customerID), customerLockLease)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err() // The caller gave up, not the lock wait
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &domain.RetryAfterError{Err: fmt.Errorf("%w: customer %s", domain.ErrResourceBusy, customerID), RetryAfter: customerLockWait}
+		}
+		return fmt.Errorf("%w: failed to lock customer: %v", domain.ErrInternalServerError, err)
+	}
+	defer releaseLock(lock, domain.CustomerLockKey(customerID))
+
+	return fn(lockedContext(ctx, lock))
+}
+
+// RunExclusive runs a scheduled job on one instance at a time. It returns nil without running the job
+// while another instance holds the job's lock.
+func RunExclusive(ctx context.Context, locker domain.Locker, job string, lease time.Duration, fn func(ctx context.Context) error) error {
+	lock, err := locker.TryAcquire(ctx, "job:"+job, lease)
+	if err != nil {
+		if errors.Is(err, domain.ErrLockHeld) {
+			return nil
+		}
+		return fmt.Errorf("failed to lock job %s: %w", job, err)
+	}
+	defer releaseLock(lock, "job:"+job)
+
+	return fn(lockedContext(ctx, lock))
+}
+
+// lockedContext ends with ctx or as soon as the lock is lost, whichever comes first. It is released
+// together with the lock.
+func lockedContext(ctx context.Context, lock domain.Lock) context.Context {
+	lockedCtx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(lock.Context(), cancel)
+	return lockedCtx
+}
+
+func releaseLock(lock domain.Lock, key string) {
+	if err := lock.Release(); err != nil {
+		log.Printf("Failed to release lock %s: %v", key, err)
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+)
+
+const otpDigits = 6
+
+// OTPService issues one-time codes to a destination and checks them, other use cases decide what a code unlocks
+type OTPService interface {
+	// Send issues a fresh code for purpose and subject, replacing any pending one. Payload is handed back by Verify.
+	Send(ctx context.Context, purpose, subject, destination, payload string) error
+	// Verify consumes the pending code and returns the payload it was issued with
+	Verify(ctx context.Context, purpose, subject, code string) (string, error)
+}
+
+type otpService struct {
+	store  domain.OTPStore
+	sender domain.OTPSender
+	cfg    *config.Config
+}
+
+func NewOTPService(store domain.OTPStore, sender domain.OTPSender, cfg *config.Config) OTPService {
+	return &otpService{
+		store:  store,
+		sender: sender,
+		cfg:    cfg,
+	}
+}
+
+func (s *otpService) Send(ctx context.Context, purpose, subject, destination, payload string) error {
+	remaining, err := s.store.StartCooldown(ctx, purpose, subject, s.cfg.OTPResendCooldown)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check OTP cooldown: %v", domain.ErrInternalServerError, err)
+	}
+	if remaining > 0 {
+		return &domain.RetryAfterError{Err: domain.ErrTooManyAttempts, RetryAfter: remaining}
+	}
+
+	upperBound := new(big.Int).Exp(big.NewInt(10), big.NewInt(otpDigits), nil)
+	n, err := rand.Int(rand.Reader, upperBound)
+	if err != nil {
+		return fmt.Errorf("%w: failed to generate OTP: %v", domain.ErrInternalServerError, err)
+	}
+	code := fmt.Sprintf("%0*d", otpDigits, n)
+
+	challenge := &domain.OTPChallenge{CodeHash: s.hashCode(purpose, subject, code), Payload: payload}
+	if err := s.store.Save(ctx, purpose, subject, challenge, s.cfg.OTPTTL); err != nil {
+		return fmt.Errorf("%w: failed to store OTP: %v", domain.ErrInternalServerError, err)
+	}
+
+	if err := s.sender.Send(destination, purpose, code); err != nil {
+		// A code the customer never received must not stay valid
+		_ = s.store.Delete(ctx, purpose, subject)
+		return fmt.Errorf("%w: failed to send OTP: %v", domain.ErrInternalServerError, err)
+	}
+
+	return nil
+}
+
+func (s *otpService) Verify(ctx context.Context, purpose, subject, code string) (string, error) {
+	payload, err := s.store.Consume(ctx, purpose, subject, s.hashCode(purpose, subject, code), s.cfg.OTPMaxAttempts)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound): // Never sent, expired, used up or already used
+			return "", fmt.Errorf("%w: no pending code, request a new one", domain.ErrInvalidInput)
+		case errors.Is(err, domain.ErrInvalidInput):
+			return "", fmt.Errorf("%w: invalid code", domain.ErrInvalidInput)
+		default:
+			return "", fmt.Errorf("%w: failed to check OTP: %v", domain.ErrInternalServerError, err)
+		}
+	}
+	return payload, nil
+}
+
+// Keyed so a leaked store cannot be brute-forced offline over the small code space
+func (s *otpService) hashCode(purpose, subject, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.OTPSecret))
+	fmt.Fprintf(mac, "%s|%s|%s", purpose, subject, code)
+	return hex.EncodeToString(mac.Sum(nil))
+}
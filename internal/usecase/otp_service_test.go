@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestOTPService_SendAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock.NewMockOTPStore(ctrl)
+	mockSender := mock.NewMockOTPSender(ctrl)
+	cfg := &config.Config{
+		OTPSecret:         "test-otp-secret",
+		OTPTTL:            time.Minute * 5,
+		OTPMaxAttempts:    3,
+		OTPResendCooldown: time.Minute,
+	}
+	otpService := usecase.NewOTPService(mockStore, mockSender, cfg)
+
+	purpose, subject, phone := domain.OTPPurposePhoneVerification, "customer-id-123", "+6281234567890"
+
+	// Sends a code and returns the stored challenge and the code the customer received
+	send := func(t *testing.T) (*domain.OTPChallenge, string) {
+		var challenge *domain.OTPChallenge
+		var code string
+		mockStore.EXPECT().StartCooldown(gomock.Any(), purpose, subject, cfg.OTPResendCooldown).Return(time.Duration(0), nil).Times(1)
+		mockStore.EXPECT().Save(gomock.Any(), purpose, subject, gomock.Any(), cfg.OTPTTL).DoAndReturn(func(_ context.Context, _, _ string, c *domain.OTPChallenge, _ time.Duration) error {
+			challenge = c
+			return nil
+		}).Times(1)
+		mockSender.EXPECT().Send(phone, purpose, gomock.Any()).DoAndReturn(func(_, _, c string) error {
+			code = c
+			return nil
+		}).Times(1)
+
+		if err := otpService.Send(context.Background(), purpose, subject, phone, phone); err != nil {
+			t.Fatalf("Expected no error sending OTP, got %v", err)
+		}
+		return challenge, code
+	}
+
+	// Test case 1: Correct code returns the payload and is consumed
+	t.Run("success_verify", func(t *testing.T) {
+		challenge, code := send(t)
+		if len(code) != 6 || challenge.CodeHash == code {
+			t.Fatalf("Expected a 6 digit code stored only as a hash, got %q", code)
+		}
+
+		mockStore.EXPECT().Consume(gomock.Any(), purpose, subject, challenge.CodeHash, cfg.OTPMaxAttempts).Return(phone, nil).Times(1)
+
+		payload, err := otpService.Verify(context.Background(), purpose, subject, code)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if payload != phone {
+			t.Errorf("Expected payload %s, got %s", phone, payload)
+		}
+	})
+
+	// Test case 2: Wrong code is checked against the store under its own hash
+	t.Run("wrong_code", func(t *testing.T) {
+		challenge, code := send(t)
+		wrongCode := "000000"
+		if code == wrongCode {
+			wrongCode = "111111"
+		}
+
+		mockStore.EXPECT().Consume(gomock.Any(), purpose, subject, gomock.Not(challenge.CodeHash), cfg.OTPMaxAttempts).Return("", domain.ErrInvalidInput).Times(1)
+
+		_, err := otpService.Verify(context.Background(), purpose, subject, wrongCode)
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: A code that was already consumed, or used up its attempts, is refused
+	t.Run("already_used", func(t *testing.T) {
+		challenge, code := send(t)
+
+		mockStore.EXPECT().Consume(gomock.Any(), purpose, subject, challenge.CodeHash, cfg.OTPMaxAttempts).Return("", domain.ErrNotFound).Times(1)
+
+		_, err := otpService.Verify(context.Background(), purpose, subject, code)
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Resend within the cooldown is refused with the time left
+	t.Run("resend_cooldown", func(t *testing.T) {
+		mockStore.EXPECT().StartCooldown(gomock.Any(), purpose, subject, cfg.OTPResendCooldown).Return(time.Second*40, nil).Times(1)
+		mockSender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := otpService.Send(context.Background(), purpose, subject, phone, phone)
+
+		var retryErr *domain.RetryAfterError
+		if !errors.As(err, &retryErr) || retryErr.RetryAfter != time.Second*40 {
+			t.Fatalf("Expected RetryAfterError of 40s, got %v", err)
+		}
+	})
+
+	// Test case 5: Code that could not be delivered does not stay valid
+	t.Run("send_failure", func(t *testing.T) {
+		mockStore.EXPECT().StartCooldown(gomock.Any(), purpose, subject, cfg.OTPResendCooldown).Return(time.Duration(0), nil).Times(1)
+		mockStore.EXPECT().Save(gomock.Any(), purpose, subject, gomock.Any(), cfg.OTPTTL).Return(nil).Times(1)
+		mockSender.EXPECT().Send(phone, purpose, gomock.Any()).Return(errors.New("gateway down")).Times(1)
+		mockStore.EXPECT().Delete(gomock.Any(), purpose, subject).Return(nil).Times(1)
+
+		err := otpService.Send(context.Background(), purpose, subject, phone, phone)
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/pkg/totp"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Back-office accounts can move money, so they get a higher bcrypt cost than customers
+const staffPasswordCost = 12
+
+type StaffUseCase interface {
+	CreateStaff(ctx context.Context, req *model.CreateStaffRequest) (*model.StaffResponse, error)
+	GetStaff(ctx context.Context, id string) (*model.StaffResponse, error)
+	SetStaffEnabled(ctx context.Context, id string, req *model.UpdateStaffStatusRequest) (*model.StaffResponse, error)
+	EnrollMFA(ctx context.Context, staffID string) (*model.MFAEnrollmentResponse, error)
+	ActivateMFA(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error)
+	ResetMFA(ctx context.Context, staffID string) (*model.StaffResponse, error)
+}
+
+type staffUseCase struct {
+	staffRepo        domain.StaffUserRepository
+	recoveryCodeRepo domain.StaffRecoveryCodeRepository
+	sessionRepo      domain.AuthSessionRepository
+	denylist         domain.TokenDenylist
+	cfg              *config.Config
+	validator        *validator.Validate
+}
+
+func NewStaffUseCase(
+	staffRepo domain.StaffUserRepository,
+	recoveryCodeRepo domain.StaffRecoveryCodeRepository,
+	sessionRepo domain.AuthSessionRepository,
+	denylist domain.TokenDenylist,
+	cfg *config.Config,
+) StaffUseCase {
+	return &staffUseCase{
+		staffRepo:        staffRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		sessionRepo:      sessionRepo,
+		denylist:         denylist,
+		cfg:              cfg,
+		validator:        validator.New(),
+	}
+}
+
+func (uc *staffUseCase) CreateStaff(ctx context.Context, req *model.CreateStaffRequest) (*model.StaffResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	if err := validateStaffPassword(req.Password, req.Username); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), staffPasswordCost)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to hash password: %v", domain.ErrInternalServerError, err)
+	}
+
+	staff := &domain.StaffUser{
+		Username:   strings.ToLower(req.Username),
+		Email:      strings.ToLower(req.Email),
+		FullName:   req.FullName,
+		Password:   string(hashedPassword),
+		Role:       req.Role,
+		BranchCode: req.BranchCode,
+		Enabled:    true,
+	}
+
+	if err := uc.staffRepo.Create(ctx, staff); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: staff user with this username or email already exists", domain.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("%w: failed to create staff user: %v", domain.ErrInternalServerError, err)
+	}
+
+	return toStaffResponse(staff), nil
+}
+
+func (uc *staffUseCase) GetStaff(ctx context.Context, id string) (*model.StaffResponse, error) {
+	staff, err := uc.staffRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get staff user: %v", domain.ErrInternalServerError, err)
+	}
+
+	return toStaffResponse(staff), nil
+}
+
+func (uc *staffUseCase) SetStaffEnabled(ctx context.Context, id string, req *model.UpdateStaffStatusRequest) (*model.StaffResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	staff, err := uc.staffRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get staff user: %v", domain.ErrInternalServerError, err)
+	}
+
+	staff.Enabled = *req.Enabled
+	if err := uc.staffRepo.SetEnabled(ctx, staff.ID, staff.Enabled); err != nil {
+		return nil, fmt.Errorf("%w: failed to update staff user: %v", domain.ErrInternalServerError, err)
+	}
+
+	// A disabled account is signed out everywhere, its access tokens stop working right away
+	if !staff.Enabled {
+		sessions, err := uc.sessionRepo.FindActiveBySubject(ctx, domain.SubjectTypeStaff, staff.ID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to retrieve sessions: %v", domain.ErrInternalServerError, err)
+		}
+		for _, session := range sessions {
+			if err := uc.sessionRepo.Revoke(ctx, session.ID, time.Now()); err != nil {
+				return nil, fmt.Errorf("%w: failed to revoke session: %v", domain.ErrInternalServerError, err)
+			}
+			if err := uc.denylist.Deny(ctx, session.ID, uc.cfg.AccessTokenExpiry); err != nil {
+				return nil, fmt.Errorf("%w: failed to deny session tokens: %v", domain.ErrInternalServerError, err)
+			}
+		}
+	}
+
+	return toStaffResponse(staff), nil
+}
+
+// EnrollMFA stores a fresh secret; MFA only becomes active once a code from it is confirmed
+func (uc *staffUseCase) EnrollMFA(ctx context.Context, staffID string) (*model.MFAEnrollmentResponse, error) {
+	staff, err := uc.findStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if staff.MFAEnabledAt != nil {
+		return nil, fmt.Errorf("%w: MFA is already enabled", domain.ErrAlreadyExists)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInternalServerError, err)
+	}
+	sealed, err := sealMFASecret(uc.cfg.MFAEncryptionKey, secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to encrypt MFA secret: %v", domain.ErrInternalServerError, err)
+	}
+
+	enrolled, err := uc.staffRepo.EnrollMFA(ctx, staff.ID, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update staff user: %v", domain.ErrInternalServerError, err)
+	}
+	if !enrolled {
+		return nil, fmt.Errorf("%w: MFA is already enabled", domain.ErrAlreadyExists)
+	}
+
+	return &model.MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.cfg.MFAIssuer, staff.Username, secret),
+	}, nil
+}
+
+func (uc *staffUseCase) ActivateMFA(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	staff, err := uc.findStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if staff.MFAEnabledAt != nil {
+		return nil, fmt.Errorf("%w: MFA is already enabled", domain.ErrAlreadyExists)
+	}
+	if err := verifyStaffTOTP(ctx, uc.staffRepo, staff, uc.cfg.MFAEncryptionKey, req.Code); err != nil {
+		return nil, err
+	}
+
+	// Only the secret the code was checked against is activated, a concurrent enrollment replaces it
+	activated, err := uc.staffRepo.ActivateMFA(ctx, staff.ID, staff.MFASecret, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update staff user: %v", domain.ErrInternalServerError, err)
+	}
+	if !activated {
+		return nil, fmt.Errorf("%w: MFA enrollment changed, enroll again", domain.ErrInvalidInput)
+	}
+
+	return uc.replaceRecoveryCodes(ctx, staff.ID)
+}
+
+func (uc *staffUseCase) RegenerateRecoveryCodes(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error) {
+	if err := uc.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	staff, err := uc.findStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if staff.MFAEnabledAt == nil {
+		return nil, fmt.Errorf("%w: MFA is not enabled", domain.ErrInvalidInput)
+	}
+	if err := verifyStaffTOTP(ctx, uc.staffRepo, staff, uc.cfg.MFAEncryptionKey, req.Code); err != nil {
+		return nil, err
+	}
+
+	return uc.replaceRecoveryCodes(ctx, staff.ID)
+}
+
+// ResetMFA is the admin path for staff who lost both their authenticator and recovery codes
+func (uc *staffUseCase) ResetMFA(ctx context.Context, staffID string) (*model.StaffResponse, error) {
+	staff, err := uc.findStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	staff.MFASecret = ""
+	staff.MFAEnabledAt = nil
+	staff.MFALastStep = 0
+	if err := uc.staffRepo.ResetMFA(ctx, staff.ID); err != nil {
+		return nil, fmt.Errorf("%w: failed to update staff user: %v", domain.ErrInternalServerError, err)
+	}
+	if err := uc.recoveryCodeRepo.Replace(ctx, staff.ID, nil); err != nil {
+		return nil, fmt.Errorf("%w: failed to delete recovery codes: %v", domain.ErrInternalServerError, err)
+	}
+
+	return toStaffResponse(staff), nil
+}
+
+func (uc *staffUseCase) replaceRecoveryCodes(ctx context.Context, staffID string) (*model.RecoveryCodesResponse, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInternalServerError, err)
+	}
+	if err := uc.recoveryCodeRepo.Replace(ctx, staffID, hashes); err != nil {
+		return nil, fmt.Errorf("%w: failed to store recovery codes: %v", domain.ErrInternalServerError, err)
+	}
+
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (uc *staffUseCase) findStaff(ctx context.Context, id string) (*domain.StaffUser, error) {
+	staff, err := uc.staffRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to get staff user: %v", domain.ErrInternalServerError, err)
+	}
+	return staff, nil
+}
+
+// Staff passwords need upper and lower case letters, a digit and a symbol, and must not contain the username
+func validateStaffPassword(password, username string) error {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if !hasUpper || !hasLower || !hasDigit || !hasSymbol {
+		return fmt.Errorf("%w: password must contain upper and lower case letters, a digit and a symbol", domain.ErrInvalidInput)
+	}
+	if strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return fmt.Errorf("%w: password must not contain the username", domain.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func toStaffResponse(staff *domain.StaffUser) *model.StaffResponse {
+	return &model.StaffResponse{
+		ID:          staff.ID,
+		Username:    staff.Username,
+		Email:       staff.Email,
+		FullName:    staff.FullName,
+		Role:        staff.Role,
+		BranchCode:  staff.BranchCode,
+		Enabled:     staff.Enabled,
+		MFAEnabled:  staff.MFAEnabledAt != nil,
+		LastLoginAt: staff.LastLoginAt,
+		CreatedAt:   staff.CreatedAt,
+	}
+}
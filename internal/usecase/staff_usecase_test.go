@@ -0,0 +1,293 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/internal/usecase"
+	"xyz-multifinance-api/pkg/totp"
+	"xyz-multifinance-api/test/mock"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestStaffUseCase_CreateStaff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
+	staffUseCase := usecase.NewStaffUseCase(mockStaffRepo, nil, nil, nil, &config.Config{})
+
+	validRequest := func() *model.CreateStaffRequest {
+		return &model.CreateStaffRequest{
+			Username:   "Collector1",
+			Email:      "Collector1@xyz.co.id",
+			FullName:   "Collector One",
+			Password:   "Str0ng!Passw0rd",
+			Role:       domain.RoleCollector,
+			BranchCode: "BDG02",
+		}
+	}
+
+	// Test case 1: Successful creation
+	t.Run("success_create_staff", func(t *testing.T) {
+		mockStaffRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, staff *domain.StaffUser) error {
+			if staff.Password == "Str0ng!Passw0rd" {
+				t.Error("Expected password to be hashed")
+			}
+			staff.ID = "staff-id-123"
+			return nil
+		}).Times(1)
+
+		res, err := staffUseCase.CreateStaff(context.Background(), validRequest())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Username != "collector1" || !res.Enabled {
+			t.Errorf("Expected enabled staff with normalized username, got %s enabled=%t", res.Username, res.Enabled)
+		}
+	})
+
+	// Test case 2: Password fails the policy
+	t.Run("weak_password", func(t *testing.T) {
+		req := validRequest()
+		req.Password = "alllowercasepassword"
+
+		_, err := staffUseCase.CreateStaff(context.Background(), req)
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Customer role cannot be given to staff
+	t.Run("invalid_role", func(t *testing.T) {
+		req := validRequest()
+		req.Role = domain.RoleCustomer
+
+		_, err := staffUseCase.CreateStaff(context.Background(), req)
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: Username or email taken
+	t.Run("staff_already_exists", func(t *testing.T) {
+		mockStaffRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrAlreadyExists).Times(1)
+
+		_, err := staffUseCase.CreateStaff(context.Background(), validRequest())
+
+		if !errors.Is(err, domain.ErrAlreadyExists) {
+			t.Fatalf("Expected ErrAlreadyExists, got %v", err)
+		}
+	})
+}
+
+func TestStaffUseCase_SetStaffEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockAuthSessionRepository(ctrl)
+	mockDenylist := mock.NewMockTokenDenylist(ctrl)
+	cfg := &config.Config{AccessTokenExpiry: 15 * time.Minute}
+	staffUseCase := usecase.NewStaffUseCase(mockStaffRepo, nil, mockSessionRepo, mockDenylist, cfg)
+
+	disabled := false
+	enabled := true
+
+	// Test case 1: Disable an account, which revokes its sessions and denies their access tokens
+	t.Run("success_disable_staff", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(&domain.StaffUser{ID: "staff-id-123", Enabled: true}, nil).Times(1)
+		mockStaffRepo.EXPECT().SetEnabled(gomock.Any(), "staff-id-123", false).Return(nil).Times(1)
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeStaff, "staff-id-123", gomock.Any()).
+			Return([]domain.AuthSession{{ID: "session-1"}, {ID: "session-2"}}, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), "session-1", gomock.Any()).Return(nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), "session-2", gomock.Any()).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), "session-1", cfg.AccessTokenExpiry).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), "session-2", cfg.AccessTokenExpiry).Return(nil).Times(1)
+
+		res, err := staffUseCase.SetStaffEnabled(context.Background(), "staff-id-123", &model.UpdateStaffStatusRequest{Enabled: &disabled})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Enabled {
+			t.Error("Expected staff to be disabled")
+		}
+	})
+
+	// Test case 2: Enabling an account leaves sessions alone
+	t.Run("success_enable_staff", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(&domain.StaffUser{ID: "staff-id-123", Enabled: false}, nil).Times(1)
+		mockStaffRepo.EXPECT().SetEnabled(gomock.Any(), "staff-id-123", true).Return(nil).Times(1)
+
+		res, err := staffUseCase.SetStaffEnabled(context.Background(), "staff-id-123", &model.UpdateStaffStatusRequest{Enabled: &enabled})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !res.Enabled {
+			t.Error("Expected staff to be enabled")
+		}
+	})
+
+	// Test case 3: The denylist is unreachable, so the caller learns the tokens may still work
+	t.Run("deny_failure", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(&domain.StaffUser{ID: "staff-id-123", Enabled: true}, nil).Times(1)
+		mockStaffRepo.EXPECT().SetEnabled(gomock.Any(), "staff-id-123", false).Return(nil).Times(1)
+		mockSessionRepo.EXPECT().FindActiveBySubject(gomock.Any(), domain.SubjectTypeStaff, "staff-id-123", gomock.Any()).
+			Return([]domain.AuthSession{{ID: "session-1"}}, nil).Times(1)
+		mockSessionRepo.EXPECT().Revoke(gomock.Any(), "session-1", gomock.Any()).Return(nil).Times(1)
+		mockDenylist.EXPECT().Deny(gomock.Any(), "session-1", gomock.Any()).Return(errors.New("redis down")).Times(1)
+
+		_, err := staffUseCase.SetStaffEnabled(context.Background(), "staff-id-123", &model.UpdateStaffStatusRequest{Enabled: &disabled})
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError, got %v", err)
+		}
+	})
+
+	// Test case 4: Unknown staff user
+	t.Run("staff_not_found", func(t *testing.T) {
+		mockStaffRepo.EXPECT().FindByID(gomock.Any(), "missing").Return(nil, domain.ErrNotFound).Times(1)
+
+		_, err := staffUseCase.SetStaffEnabled(context.Background(), "missing", &model.UpdateStaffStatusRequest{Enabled: &disabled})
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestStaffUseCase_MFAEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStaffRepo := mock.NewMockStaffUserRepository(ctrl)
+	mockRecoveryCodeRepo := mock.NewMockStaffRecoveryCodeRepository(ctrl)
+	cfg := &config.Config{MFAEncryptionKey: "test-mfa-key", MFAIssuer: "XYZ Multifinance"}
+	staffUseCase := usecase.NewStaffUseCase(mockStaffRepo, mockRecoveryCodeRepo, nil, nil, cfg)
+
+	staff := &domain.StaffUser{ID: "staff-id-123", Username: "officer1", Enabled: true}
+	mockStaffRepo.EXPECT().FindByID(gomock.Any(), "staff-id-123").Return(staff, nil).AnyTimes()
+
+	var secret string
+
+	// Test case 1: Enrollment stores an encrypted secret and returns a provisioning URI
+	t.Run("success_enroll", func(t *testing.T) {
+		mockStaffRepo.EXPECT().EnrollMFA(gomock.Any(), "staff-id-123", gomock.Any()).DoAndReturn(func(_ context.Context, _, sealed string) (bool, error) {
+			staff.MFASecret = sealed
+			return true, nil
+		}).Times(1)
+
+		res, err := staffUseCase.EnrollMFA(context.Background(), "staff-id-123")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(res.ProvisioningURI, "otpauth://totp/") || !strings.Contains(res.ProvisioningURI, "secret="+res.Secret) {
+			t.Errorf("Expected otpauth URI carrying the secret, got %s", res.ProvisioningURI)
+		}
+		if staff.MFASecret == "" || strings.Contains(staff.MFASecret, res.Secret) {
+			t.Error("Expected the stored secret to be encrypted")
+		}
+		if staff.MFAEnabledAt != nil {
+			t.Error("Expected MFA to stay disabled until activation")
+		}
+		secret = res.Secret
+	})
+
+	// Test case 2: A wrong code does not activate MFA
+	t.Run("activate_wrong_code", func(t *testing.T) {
+		code, _ := totp.CodeAt(secret, totp.Step(time.Now())+10)
+
+		_, err := staffUseCase.ActivateMFA(context.Background(), "staff-id-123", &model.MFACodeRequest{Code: code})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: A code for a secret that was replaced meanwhile does not activate MFA
+	t.Run("activate_replaced_secret", func(t *testing.T) {
+		code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+		mockStaffRepo.EXPECT().ClaimMFAStep(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).Times(1)
+		mockStaffRepo.EXPECT().ActivateMFA(gomock.Any(), "staff-id-123", gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+
+		_, err := staffUseCase.ActivateMFA(context.Background(), "staff-id-123", &model.MFACodeRequest{Code: code})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 4: A current code activates MFA and returns recovery codes
+	t.Run("success_activate", func(t *testing.T) {
+		code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+		mockStaffRepo.EXPECT().ClaimMFAStep(gomock.Any(), "staff-id-123", gomock.Any()).Return(true, nil).Times(1)
+		mockStaffRepo.EXPECT().ActivateMFA(gomock.Any(), "staff-id-123", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, sealed string, at time.Time) (bool, error) {
+			if sealed != staff.MFASecret {
+				t.Errorf("Expected the checked secret to be activated, got %q", sealed)
+			}
+			staff.MFAEnabledAt = &at
+			return true, nil
+		}).Times(1)
+		mockRecoveryCodeRepo.EXPECT().Replace(gomock.Any(), "staff-id-123", gomock.Len(10)).Return(nil).Times(1)
+
+		res, err := staffUseCase.ActivateMFA(context.Background(), "staff-id-123", &model.MFACodeRequest{Code: code})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(res.RecoveryCodes) != 10 {
+			t.Errorf("Expected 10 recovery codes, got %d", len(res.RecoveryCodes))
+		}
+		if staff.MFAEnabledAt == nil || staff.MFALastStep == 0 {
+			t.Error("Expected MFA to be enabled and the code's step recorded")
+		}
+	})
+
+	// Test case 5: Enrolling again is refused once MFA is active
+	t.Run("enroll_already_enabled", func(t *testing.T) {
+		_, err := staffUseCase.EnrollMFA(context.Background(), "staff-id-123")
+
+		if !errors.Is(err, domain.ErrAlreadyExists) {
+			t.Fatalf("Expected ErrAlreadyExists, got %v", err)
+		}
+	})
+
+	// Test case 6: Regenerating recovery codes rejects a replayed code
+	t.Run("regenerate_replayed_code", func(t *testing.T) {
+		code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+		mockStaffRepo.EXPECT().ClaimMFAStep(gomock.Any(), "staff-id-123", gomock.Any()).Return(false, nil).Times(1)
+
+		_, err := staffUseCase.RegenerateRecoveryCodes(context.Background(), "staff-id-123", &model.MFACodeRequest{Code: code})
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 7: Admin reset clears the secret and recovery codes
+	t.Run("success_reset", func(t *testing.T) {
+		mockStaffRepo.EXPECT().ResetMFA(gomock.Any(), "staff-id-123").Return(nil).Times(1)
+		mockRecoveryCodeRepo.EXPECT().Replace(gomock.Any(), "staff-id-123", gomock.Nil()).Return(nil).Times(1)
+
+		res, err := staffUseCase.ResetMFA(context.Background(), "staff-id-123")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.MFAEnabled || staff.MFASecret != "" {
+			t.Error("Expected MFA to be cleared")
+		}
+	})
+}
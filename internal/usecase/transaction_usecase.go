@@ -1,77 +1,95 @@
 package usecase
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
+	"xyz-multifinance-api/config"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
-	"xyz-multifinance-api/internal/repository"
+	"xyz-multifinance-api/pkg/metrics"
 
 	"github.com/go-playground/validator/v10"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
+)
+
+var (
+	transactionsCreated = metrics.NewCounterVec("transactions_created_total",
+		"Transactions committed, by tenor in months.", "tenor_months")
+	amountDisbursed = metrics.NewCounterVec("transaction_disbursed_amount_total",
+		"OTR amount financed by committed transactions, by tenor in months.", "tenor_months")
+	insufficientCreditRejections = metrics.NewCounterVec("transaction_insufficient_credit_total",
+		"Transactions rejected because the credit limit for the tenor was too low.", "tenor_months")
 )
 
 type TransactionUseCase interface {
-	CreateTransaction(req *model.CreateTransactionRequest) (*model.TransactionResponse, error)
-	GetTransactionByContractNumber(contractNumber string) (*model.TransactionResponse, error)
-	GetTransactionsByCustomerID(customerID string) ([]model.TransactionResponse, error)
+	CreateTransaction(ctx context.Context, req *model.CreateTransactionRequest) (*model.TransactionResponse, error)
+	GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*model.TransactionResponse, error)
+	GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]model.TransactionResponse, error)
 }
 
 type transactionUseCase struct {
-	db              *gorm.DB // DB instance for transaction management
+	uow             domain.UnitOfWork
 	transactionRepo domain.TransactionRepository
 	customerRepo    domain.CustomerRepository
 	creditLimitRepo domain.CreditLimitRepository
 	validator       *validator.Validate
-	cacheStore      domain.CacheStore
+	otpService      OTPService
+	cfg             *config.Config
 }
 
 func NewTransactionUseCase(
-	db *gorm.DB,
+	uow domain.UnitOfWork,
 	transactionRepo domain.TransactionRepository,
 	customerRepo domain.CustomerRepository,
 	creditLimitRepo domain.CreditLimitRepository,
-	cacheStore domain.CacheStore,
+	otpService OTPService,
+	cfg *config.Config,
 ) TransactionUseCase {
 	return &transactionUseCase{
-		db:              db,
+		uow:             uow,
 		transactionRepo: transactionRepo,
 		customerRepo:    customerRepo,
 		creditLimitRepo: creditLimitRepo,
 		validator:       validator.New(),
-		cacheStore:      cacheStore,
+		otpService:      otpService,
+		cfg:             cfg,
 	}
 }
 
-func (uc *transactionUseCase) CreateTransaction(req *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
+func (uc *transactionUseCase) CreateTransaction(ctx context.Context, req *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
 	if err := uc.validator.Struct(req); err != nil {
 		return nil, domain.ErrInvalidInput
 	}
 
-	var createdTransaction *domain.Transaction // The transaction created in GORM
+	if uc.cfg.TransactionOTPThreshold > 0 && req.OTRAmount >= uc.cfg.TransactionOTPThreshold {
+		if err := uc.confirmTransaction(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 
-	err := uc.db.Transaction(func(tx *gorm.DB) error {
-		txCustomerRepo := repository.NewCustomerRepository(tx, uc.cacheStore)
-		txCreditLimitRepo := repository.NewCreditLimitRepository(tx, uc.cacheStore)
-		txTransactionRepo := repository.NewTransactionRepository(tx)
+	var createdTransaction *domain.Transaction
 
-		_, err := txCustomerRepo.FindByID(req.CustomerID)
+	err := uc.uow.Do(ctx, func(repos domain.TxRepositories) error {
+		// Locked like in erasure, so a customer is never erased while one of their contracts is being created
+		customer, err := repos.Customers().FindByIDForUpdate(ctx, req.CustomerID)
 		if err != nil {
 			if errors.Is(err, domain.ErrNotFound) {
 				return fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, req.CustomerID)
 			}
 			return fmt.Errorf("failed to verify customer existence: %w", err)
 		}
+		if customer.ErasedAt != nil {
+			return fmt.Errorf("%w: customer %s cannot take new contracts", domain.ErrAlreadyErased, req.CustomerID)
+		}
 
-		// Retrieve Credit Limit with Pessimistic Locking
-		creditLimit := &domain.CreditLimit{}
-		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("customer_id = ? AND tenor_months = ?", req.CustomerID, req.TenorMonths).
-			First(creditLimit).Error
-
+		// The row stays locked until commit, so concurrent transactions cannot spend the same limit
+		creditLimit, err := repos.CreditLimits().GetCreditLimitForUpdate(ctx, req.CustomerID, req.TenorMonths)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+			if errors.Is(err, domain.ErrNotFound) {
 				return fmt.Errorf("%w: credit limit for tenor %d not found for customer %s", domain.ErrNotFound, req.TenorMonths, req.CustomerID)
 			}
 			return fmt.Errorf("failed to retrieve credit limit with lock: %w", err)
@@ -86,22 +104,25 @@ func (uc *transactionUseCase) CreateTransaction(req *model.CreateTransactionRequ
 
 		// Update Credit Limit
 		creditLimit.LimitAmount -= totalTransactionCost
-		err = txCreditLimitRepo.UpdateCreditLimit(creditLimit)
+		err = repos.CreditLimits().UpdateCreditLimit(ctx, creditLimit)
 		if err != nil {
 			return fmt.Errorf("failed to deduct credit limit: %w", err)
 		}
 
+		maturityDate := time.Now().AddDate(0, req.TenorMonths, 0)
 		transaction := &domain.Transaction{
 			CustomerID:        req.CustomerID,
 			ContractNumber:    req.ContractNumber,
+			TenorMonths:       req.TenorMonths,
 			OTRAmount:         req.OTRAmount,
 			AdminFee:          req.AdminFee,
 			InstallmentAmount: req.InstallmentAmount,
 			InterestAmount:    req.InterestAmount,
 			AssetName:         req.AssetName,
+			MaturityDate:      &maturityDate,
 		}
 
-		err = txTransactionRepo.CreateTransaction(transaction)
+		err = repos.Transactions().CreateTransaction(ctx, transaction)
 		if err != nil {
 			if errors.Is(err, domain.ErrAlreadyExists) {
 				return fmt.Errorf("%w: transaction with contract number %s already exists", domain.ErrAlreadyExists, req.ContractNumber)
@@ -113,29 +134,81 @@ func (uc *transactionUseCase) CreateTransaction(req *model.CreateTransactionRequ
 		return nil
 	})
 
+	tenor := strconv.Itoa(req.TenorMonths)
 	if err != nil {
 		switch {
-		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInsufficientCredit), errors.Is(err, domain.ErrAlreadyExists):
+		case errors.Is(err, domain.ErrInsufficientCredit):
+			insufficientCreditRejections.Inc(tenor)
+			return nil, err
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrAlreadyExists), errors.Is(err, domain.ErrAlreadyErased):
 			return nil, err
 		default:
 			return nil, fmt.Errorf("%w: transaction process failed: %v", domain.ErrInternalServerError, err)
 		}
 	}
+	transactionsCreated.Inc(tenor)
+	amountDisbursed.Add(createdTransaction.OTRAmount, tenor)
 
 	return &model.TransactionResponse{
 		ID:                createdTransaction.ID,
 		CustomerID:        createdTransaction.CustomerID,
 		ContractNumber:    createdTransaction.ContractNumber,
+		TenorMonths:       createdTransaction.TenorMonths,
 		OTRAmount:         createdTransaction.OTRAmount,
 		AdminFee:          createdTransaction.AdminFee,
 		InstallmentAmount: createdTransaction.InstallmentAmount,
 		InterestAmount:    createdTransaction.InterestAmount,
 		AssetName:         createdTransaction.AssetName,
+		MaturityDate:      createdTransaction.MaturityDate,
 	}, nil
 }
 
-func (uc *transactionUseCase) GetTransactionByContractNumber(contractNumber string) (*model.TransactionResponse, error) {
-	transaction, err := uc.transactionRepo.GetTransactionByContractNumber(contractNumber)
+// confirmTransaction sends a code to the customer's verified phone on the first call and checks it on the retry.
+// The code is bound to the exact request, so it cannot confirm a different amount or asset.
+func (uc *transactionUseCase) confirmTransaction(ctx context.Context, req *model.CreateTransactionRequest) error {
+	customer, err := uc.customerRepo.FindByID(ctx, req.CustomerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: customer with ID %s not found", domain.ErrNotFound, req.CustomerID)
+		}
+		return fmt.Errorf("%w: failed to verify customer existence: %v", domain.ErrInternalServerError, err)
+	}
+	if customer.ErasedAt != nil {
+		return fmt.Errorf("%w: customer %s cannot take new contracts", domain.ErrAlreadyErased, req.CustomerID)
+	}
+	if customer.PhoneVerifiedAt == nil {
+		return fmt.Errorf("%w: transactions from %.2f need a verified phone number for confirmation", domain.ErrPhoneNotVerified, uc.cfg.TransactionOTPThreshold)
+	}
+
+	subject := req.CustomerID + ":" + req.ContractNumber
+	fingerprint := transactionFingerprint(req)
+
+	if req.OTPCode == "" {
+		if err := uc.otpService.Send(ctx, domain.OTPPurposeTransactionConfirmation, subject, customer.PhoneNumber, fingerprint); err != nil {
+			return err
+		}
+		return domain.ErrOTPRequired
+	}
+
+	confirmed, err := uc.otpService.Verify(ctx, domain.OTPPurposeTransactionConfirmation, subject, req.OTPCode)
+	if err != nil {
+		return err
+	}
+	if confirmed != fingerprint {
+		return fmt.Errorf("%w: code was issued for different transaction details", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+func transactionFingerprint(req *model.CreateTransactionRequest) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%.2f|%.2f|%.2f|%.2f|%s",
+		req.CustomerID, req.ContractNumber, req.TenorMonths, req.OTRAmount, req.AdminFee,
+		req.InstallmentAmount, req.InterestAmount, req.AssetName)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (uc *transactionUseCase) GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*model.TransactionResponse, error) {
+	transaction, err := uc.transactionRepo.GetTransactionByContractNumber(ctx, contractNumber)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			return nil, domain.ErrNotFound
@@ -147,16 +220,18 @@ func (uc *transactionUseCase) GetTransactionByContractNumber(contractNumber stri
 		ID:                transaction.ID,
 		CustomerID:        transaction.CustomerID,
 		ContractNumber:    transaction.ContractNumber,
+		TenorMonths:       transaction.TenorMonths,
 		OTRAmount:         transaction.OTRAmount,
 		AdminFee:          transaction.AdminFee,
 		InstallmentAmount: transaction.InstallmentAmount,
 		InterestAmount:    transaction.InterestAmount,
 		AssetName:         transaction.AssetName,
+		MaturityDate:      transaction.MaturityDate,
 	}, nil
 }
 
-func (uc *transactionUseCase) GetTransactionsByCustomerID(customerID string) ([]model.TransactionResponse, error) {
-	transactions, err := uc.transactionRepo.GetTransactionsByCustomerID(customerID)
+func (uc *transactionUseCase) GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]model.TransactionResponse, error) {
+	transactions, err := uc.transactionRepo.GetTransactionsByCustomerID(ctx, customerID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to retrieve transactions: %v", domain.ErrInternalServerError, err)
 	}
@@ -167,11 +242,13 @@ func (uc *transactionUseCase) GetTransactionsByCustomerID(customerID string) ([]
 			ID:                transaction.ID,
 			CustomerID:        transaction.CustomerID,
 			ContractNumber:    transaction.ContractNumber,
+			TenorMonths:       transaction.TenorMonths,
 			OTRAmount:         transaction.OTRAmount,
 			AdminFee:          transaction.AdminFee,
 			InstallmentAmount: transaction.InstallmentAmount,
 			InterestAmount:    transaction.InterestAmount,
 			AssetName:         transaction.AssetName,
+			MaturityDate:      transaction.MaturityDate,
 		})
 	}
 	return responses, nil
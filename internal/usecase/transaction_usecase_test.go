@@ -1,9 +1,13 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"log"
+	"slices"
 	"testing"
+	"time"
+	"xyz-multifinance-api/config"
 	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
 	"xyz-multifinance-api/internal/repository"
@@ -23,7 +27,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to connect to in-memory SQLite: %v", err)
 	}
 
-	err = db.AutoMigrate(&domain.Customer{}, &domain.CreditLimit{}, &domain.Transaction{})
+	err = db.AutoMigrate(&domain.Customer{}, &domain.CreditLimit{}, &domain.Transaction{}, &domain.ErasureCertificate{})
 	if err != nil {
 		t.Fatalf("Failed to auto migrate SQLite DB: %v", err)
 	}
@@ -38,21 +42,23 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 	db := setupTestDB(t) // DB initialized once for the whole test function
 
 	mockCacheStore := mock.NewMockCacheStore(ctrl)
-	mockCacheStore.EXPECT().Get(gomock.Any()).Return("", errors.New("not found in cache")).AnyTimes()
-	mockCacheStore.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockCacheStore.EXPECT().Del(gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", domain.ErrCacheMiss).AnyTimes()
+	mockCacheStore.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().SetWithTags(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockCacheStore.EXPECT().Close().Return(nil).AnyTimes()
 
-	customerRepo := repository.NewCustomerRepository(db, mockCacheStore)
-	creditLimitRepo := repository.NewCreditLimitRepository(db, mockCacheStore)
+	customerRepo := repository.NewCustomerRepository(db, mockCacheStore, nil)
+	creditLimitRepo := repository.NewCreditLimitRepository(db, mockCacheStore, nil)
 	transactionRepo := repository.NewTransactionRepository(db)
 
 	transactionUseCase := usecase.NewTransactionUseCase(
-		db,
+		repository.NewUnitOfWork(db, mockCacheStore, nil),
 		transactionRepo,
 		customerRepo,
 		creditLimitRepo,
-		mockCacheStore,
+		nil,
+		&config.Config{},
 	)
 
 	contractNumberPrefix := "TRX-TEST-01"
@@ -92,7 +98,7 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 			t.Fatalf("Failed to pre-create credit limit in SQLite: %v", err)
 		}
 
-		res, err := transactionUseCase.CreateTransaction(&req)
+		res, err := transactionUseCase.CreateTransaction(context.Background(), &req)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -154,7 +160,7 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 			t.Fatalf("Failed to pre-create low limit in SQLite: %v", err)
 		}
 
-		_, err := transactionUseCase.CreateTransaction(&req)
+		_, err := transactionUseCase.CreateTransaction(context.Background(), &req)
 
 		if !errors.Is(err, domain.ErrInsufficientCredit) {
 			t.Fatalf("Expected ErrInsufficientCredit, got %v", err)
@@ -218,7 +224,7 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 
 		// totalCost = req.OTRAmount + req.AdminFee + req.InterestAmount
 
-		_, err := transactionUseCase.CreateTransaction(&req)
+		_, err := transactionUseCase.CreateTransaction(context.Background(), &req)
 
 		if !errors.Is(err, domain.ErrInternalServerError) {
 			t.Fatalf("Expected ErrInternalServerError (due to duplicate key), got %v", err)
@@ -257,7 +263,7 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 			ContractNumber:    contractNumberPrefix + "004",
 		}
 
-		_, err := transactionUseCase.CreateTransaction(&req)
+		_, err := transactionUseCase.CreateTransaction(context.Background(), &req)
 		log.Println(err)
 
 		if !errors.Is(err, domain.ErrNotFound) {
@@ -301,7 +307,7 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 			ContractNumber:    contractNumberPrefix + "005",
 		}
 
-		_, err := transactionUseCase.CreateTransaction(&req)
+		_, err := transactionUseCase.CreateTransaction(context.Background(), &req)
 
 		if !errors.Is(err, domain.ErrNotFound) {
 			t.Fatalf("Expected ErrNotFound for credit limit, got %v", err)
@@ -334,10 +340,272 @@ func TestTransactionUseCase_CreateTransaction(t *testing.T) {
 		db.Exec("DELETE FROM `credit_limits`")
 		db.Exec("DELETE FROM `customers`")
 
-		_, err := transactionUseCase.CreateTransaction(&invalidReq)
+		_, err := transactionUseCase.CreateTransaction(context.Background(), &invalidReq)
 
 		if !errors.Is(err, domain.ErrInvalidInput) {
 			t.Fatalf("Expected ErrInvalidInput, got %v", err)
 		}
 	})
 }
+
+func TestTransactionUseCase_CacheChangesFollowCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := setupTestDB(t)
+
+	var deletedKeys []string
+	mockCacheStore := mock.NewMockCacheStore(ctrl)
+	mockCacheStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", domain.ErrCacheMiss).AnyTimes()
+	mockCacheStore.EXPECT().SetWithTags(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().Del(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, keys ...string) error {
+		deletedKeys = append(deletedKeys, keys...)
+		return nil
+	}).AnyTimes()
+
+	transactionUseCase := usecase.NewTransactionUseCase(
+		repository.NewUnitOfWork(db, mockCacheStore, nil),
+		repository.NewTransactionRepository(db),
+		repository.NewCustomerRepository(db, mockCacheStore, nil),
+		repository.NewCreditLimitRepository(db, mockCacheStore, nil),
+		nil,
+		&config.Config{},
+	)
+
+	setup := func(t *testing.T) (string, string) {
+		db.Exec("DELETE FROM `transactions`")
+		db.Exec("DELETE FROM `credit_limits`")
+		db.Exec("DELETE FROM `customers`")
+		deletedKeys = nil
+
+		customerID := uuid.New().String()
+		if err := db.Create(&domain.Customer{ID: customerID, NIK: "1111111111111201", FullName: "Cache Test User"}).Error; err != nil {
+			t.Fatalf("Failed to pre-create customer in SQLite: %v", err)
+		}
+		if err := db.Create(&domain.CreditLimit{ID: uuid.New().String(), CustomerID: customerID, TenorMonths: 3, LimitAmount: 5000000}).Error; err != nil {
+			t.Fatalf("Failed to pre-create credit limit in SQLite: %v", err)
+		}
+		return customerID, "credit_limit:" + customerID + ":3"
+	}
+
+	newRequest := func(customerID, contractNumber string) *model.CreateTransactionRequest {
+		return &model.CreateTransactionRequest{
+			CustomerID: customerID, TenorMonths: 3, OTRAmount: 1000000, AdminFee: 50000, InstallmentAmount: 350000, InterestAmount: 50000,
+			AssetName: "Test Asset", ContractNumber: contractNumber,
+		}
+	}
+
+	// Test case 1: The cached limit is invalidated once the transaction commits
+	t.Run("invalidated_after_commit", func(t *testing.T) {
+		customerID, limitKey := setup(t)
+
+		if _, err := transactionUseCase.CreateTransaction(context.Background(), newRequest(customerID, "TRX-CACHE-001")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !slices.Contains(deletedKeys, limitKey) {
+			t.Errorf("Expected %s to be invalidated, got %v", limitKey, deletedKeys)
+		}
+	})
+
+	// Test case 2: A rolled back deduction leaves the cache alone
+	t.Run("discarded_on_rollback", func(t *testing.T) {
+		customerID, limitKey := setup(t)
+		if err := db.Create(&domain.Transaction{ID: uuid.New().String(), CustomerID: customerID, ContractNumber: "TRX-CACHE-002", AssetName: "dummy"}).Error; err != nil {
+			t.Fatalf("Failed to pre-create conflicting transaction: %v", err)
+		}
+
+		_, err := transactionUseCase.CreateTransaction(context.Background(), newRequest(customerID, "TRX-CACHE-002"))
+
+		if !errors.Is(err, domain.ErrInternalServerError) {
+			t.Fatalf("Expected ErrInternalServerError (due to duplicate key), got %v", err)
+		}
+		if slices.Contains(deletedKeys, limitKey) {
+			t.Errorf("Expected no cache change after rollback, got %v", deletedKeys)
+		}
+	})
+}
+
+func TestTransactionUseCase_UnitOfWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomerRepo := mock.NewMockCustomerRepository(ctrl)
+	mockCreditLimitRepo := mock.NewMockCreditLimitRepository(ctrl)
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockRepos := mock.NewMockTxRepositories(ctrl)
+	mockRepos.EXPECT().Customers().Return(mockCustomerRepo).AnyTimes()
+	mockRepos.EXPECT().CreditLimits().Return(mockCreditLimitRepo).AnyTimes()
+	mockRepos.EXPECT().Transactions().Return(mockTransactionRepo).AnyTimes()
+
+	mockUnitOfWork := mock.NewMockUnitOfWork(ctrl)
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(domain.TxRepositories) error) error {
+		return fn(mockRepos)
+	}).AnyTimes()
+
+	transactionUseCase := usecase.NewTransactionUseCase(mockUnitOfWork, mockTransactionRepo, mockCustomerRepo, mockCreditLimitRepo, nil, &config.Config{})
+
+	testCustomerID := uuid.New().String()
+	req := &model.CreateTransactionRequest{
+		CustomerID: testCustomerID, TenorMonths: 3, OTRAmount: 1000000, AdminFee: 50000, InstallmentAmount: 350000, InterestAmount: 50000,
+		AssetName: "Test Asset", ContractNumber: "TRX-UOW-001",
+	}
+
+	// Test case 1: The customer and the limit are read with row locks and the limit is deducted inside the unit of work
+	t.Run("success_deducts_locked_limit", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), testCustomerID).Return(&domain.Customer{ID: testCustomerID}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitForUpdate(gomock.Any(), testCustomerID, 3).Return(&domain.CreditLimit{ID: "limit-id", CustomerID: testCustomerID, TenorMonths: 3, LimitAmount: 5000000}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, creditLimit *domain.CreditLimit) error {
+			if creditLimit.LimitAmount != 3900000 {
+				t.Errorf("Expected remaining limit 3900000, got %v", creditLimit.LimitAmount)
+			}
+			return nil
+		}).Times(1)
+		mockTransactionRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		res, err := transactionUseCase.CreateTransaction(context.Background(), req)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.ContractNumber != req.ContractNumber {
+			t.Errorf("Expected contract number %s, got %s", req.ContractNumber, res.ContractNumber)
+		}
+	})
+
+	// Test case 2: Insufficient credit writes nothing
+	t.Run("insufficient_credit", func(t *testing.T) {
+		mockCustomerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), testCustomerID).Return(&domain.Customer{ID: testCustomerID}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitForUpdate(gomock.Any(), testCustomerID, 3).Return(&domain.CreditLimit{ID: "limit-id", CustomerID: testCustomerID, TenorMonths: 3, LimitAmount: 500000}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().UpdateCreditLimit(gomock.Any(), gomock.Any()).Times(0)
+		mockTransactionRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := transactionUseCase.CreateTransaction(context.Background(), req)
+
+		if !errors.Is(err, domain.ErrInsufficientCredit) {
+			t.Fatalf("Expected ErrInsufficientCredit, got %v", err)
+		}
+	})
+
+	// Test case 3: An erased customer takes no new contracts
+	t.Run("erased_customer", func(t *testing.T) {
+		erasedAt := time.Now()
+		mockCustomerRepo.EXPECT().FindByIDForUpdate(gomock.Any(), testCustomerID).Return(&domain.Customer{ID: testCustomerID, ErasedAt: &erasedAt}, nil).Times(1)
+		mockCreditLimitRepo.EXPECT().GetCreditLimitForUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockTransactionRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := transactionUseCase.CreateTransaction(context.Background(), req)
+
+		if !errors.Is(err, domain.ErrAlreadyErased) {
+			t.Fatalf("Expected ErrAlreadyErased, got %v", err)
+		}
+	})
+}
+
+func TestTransactionUseCase_OTPConfirmation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := setupTestDB(t)
+
+	mockCacheStore := mock.NewMockCacheStore(ctrl)
+	mockCacheStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", domain.ErrCacheMiss).AnyTimes()
+	mockCacheStore.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().SetWithTags(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCacheStore.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockOTPService := mock.NewMockOTPService(ctrl)
+
+	transactionUseCase := usecase.NewTransactionUseCase(
+		repository.NewUnitOfWork(db, mockCacheStore, nil),
+		repository.NewTransactionRepository(db),
+		repository.NewCustomerRepository(db, mockCacheStore, nil),
+		repository.NewCreditLimitRepository(db, mockCacheStore, nil),
+		mockOTPService,
+		&config.Config{TransactionOTPThreshold: 1000000},
+	)
+
+	db.Exec("DELETE FROM `transactions`")
+	db.Exec("DELETE FROM `credit_limits`")
+	db.Exec("DELETE FROM `customers`")
+
+	verifiedAt := time.Now()
+	customer := &domain.Customer{ID: uuid.New().String(), NIK: "1111111111111201", FullName: "OTP Test User", PhoneNumber: "+6281234567890", PhoneVerifiedAt: &verifiedAt}
+	db.Create(customer)
+	db.Create(&domain.CreditLimit{ID: uuid.New().String(), CustomerID: customer.ID, TenorMonths: 3, LimitAmount: 10000000})
+
+	newRequest := func() *model.CreateTransactionRequest {
+		return &model.CreateTransactionRequest{
+			CustomerID:        customer.ID,
+			ContractNumber:    "TRX-OTP-001",
+			TenorMonths:       3,
+			OTRAmount:         2000000,
+			AdminFee:          50000,
+			InstallmentAmount: 700000,
+			InterestAmount:    50000,
+			AssetName:         "Motorcycle",
+		}
+	}
+
+	var sentFingerprint string
+
+	// Test case 1: First submission above the threshold only sends a code
+	t.Run("otp_required", func(t *testing.T) {
+		mockOTPService.EXPECT().Send(gomock.Any(), domain.OTPPurposeTransactionConfirmation, customer.ID+":TRX-OTP-001", customer.PhoneNumber, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _, _, payload string) error {
+				sentFingerprint = payload
+				return nil
+			}).Times(1)
+
+		_, err := transactionUseCase.CreateTransaction(context.Background(), newRequest())
+
+		if !errors.Is(err, domain.ErrOTPRequired) {
+			t.Fatalf("Expected ErrOTPRequired, got %v", err)
+		}
+		var count int64
+		db.Model(&domain.Transaction{}).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no transaction before confirmation, got %d", count)
+		}
+	})
+
+	// Test case 2: Code issued for other details is rejected
+	t.Run("changed_amount", func(t *testing.T) {
+		req := newRequest()
+		req.OTRAmount = 3000000
+		req.OTPCode = "123456"
+		mockOTPService.EXPECT().Verify(gomock.Any(), domain.OTPPurposeTransactionConfirmation, customer.ID+":TRX-OTP-001", "123456").Return(sentFingerprint, nil).Times(1)
+
+		_, err := transactionUseCase.CreateTransaction(context.Background(), req)
+
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	// Test case 3: Resubmission with the code commits the transaction
+	t.Run("success_confirmed", func(t *testing.T) {
+		req := newRequest()
+		req.OTPCode = "654321"
+		mockOTPService.EXPECT().Verify(gomock.Any(), domain.OTPPurposeTransactionConfirmation, customer.ID+":TRX-OTP-001", "654321").Return(sentFingerprint, nil).Times(1)
+
+		res, err := transactionUseCase.CreateTransaction(context.Background(), req)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.ContractNumber != "TRX-OTP-001" {
+			t.Errorf("Expected contract TRX-OTP-001, got %s", res.ContractNumber)
+		}
+	})
+
+	// Test case 4: Below the threshold no code is needed
+	t.Run("below_threshold", func(t *testing.T) {
+		req := newRequest()
+		req.ContractNumber = "TRX-OTP-002"
+		req.OTRAmount = 500000
+		mockOTPService.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := transactionUseCase.CreateTransaction(context.Background(), req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,384 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	rsaKeyBits = 3072
+	// How often the rotation worker looks at the key directory, also picks up keys written by other instances
+	checkInterval = time.Minute
+	// Lookups of an unknown kid reload the directory at most this often
+	reloadThrottle = 30 * time.Second
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key is one signing or verification key. Verify-only keys have no private part.
+type Key struct {
+	ID        string
+	Algorithm string
+	CreatedAt time.Time
+	path      string
+	private   interface{}
+	public    interface{}
+}
+
+// KeySet signs tokens with its newest private key and verifies them with any key it holds.
+// Asymmetric key sets are backed by a directory of PEM files named <kid>.pem (private) or <kid>.pub.pem (public only).
+type KeySet struct {
+	mu         sync.RWMutex
+	dir        string
+	algorithm  string
+	rotation   time.Duration
+	retention  time.Duration
+	keys       map[string]*Key
+	signing    *Key
+	lastReload time.Time
+	// Runs each rotation, e.g. under a lock so instances sharing the directory do not rotate at once
+	rotationGuard func(rotate func() error) error
+}
+
+// NewHMACKeySet keeps the shared-secret HS256 behaviour for setups without a key directory.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{ID: "hs256", Algorithm: AlgorithmHS256, private: []byte(secret), public: []byte(secret)}
+	return &KeySet{keys: map[string]*Key{key.ID: key}, signing: key}
+}
+
+// LoadKeySet reads every PEM key in dir and generates a first key of the given algorithm when there is no private key yet.
+// Replaced private keys are deleted once they are older than rotation plus retention.
+func LoadKeySet(dir, algorithm string, rotation, retention time.Duration) (*KeySet, error) {
+	if algorithm != AlgorithmRS256 && algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", algorithm)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create JWT key directory: %w", err)
+	}
+
+	ks := &KeySet{dir: dir, algorithm: algorithm, rotation: rotation, retention: retention}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	if ks.signing == nil {
+		if err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) Reload() error {
+	if ks.dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read JWT key directory: %w", err)
+	}
+
+	keys := map[string]*Key{}
+	var signing *Key
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat JWT key %s: %w", entry.Name(), err)
+		}
+
+		key, err := readKeyFile(filepath.Join(ks.dir, entry.Name()), info.ModTime())
+		if err != nil {
+			return fmt.Errorf("failed to load JWT key %s: %w", entry.Name(), err)
+		}
+		keys[key.ID] = key
+
+		if key.private != nil && (signing == nil || key.CreatedAt.After(signing.CreatedAt)) {
+			signing = key
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.signing = signing
+	ks.lastReload = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Rotate generates a new signing key. The previous keys stay available for verification until they are retired.
+func (ks *KeySet) Rotate() error {
+	if ks.dir == "" {
+		return errors.New("key rotation needs a key directory")
+	}
+
+	var private interface{}
+	switch ks.algorithm {
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		private = key
+	case AlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		private = key
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to generate key ID: %w", err)
+	}
+	now := time.Now()
+	kid := now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+
+	path := filepath.Join(ks.dir, kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	// File times are only as fine as the kernel tick, so a key written right after another could look
+	// just as old. Setting them explicitly keeps the newest key the signing key.
+	if err := os.Chtimes(path, now, now); err != nil {
+		return fmt.Errorf("failed to timestamp private key: %w", err)
+	}
+
+	ks.pruneRetired()
+	return ks.Reload()
+}
+
+// SetRotationGuard makes RunRotation rotate through guard. guard may return without calling rotate,
+// e.g. while another instance holds the rotation lock; the key that instance writes is loaded on the next check.
+func (ks *KeySet) SetRotationGuard(guard func(rotate func() error) error) {
+	ks.rotationGuard = guard
+}
+
+// RunRotation rotates the signing key once it is older than the rotation interval.
+func (ks *KeySet) RunRotation(stop <-chan struct{}) {
+	if ks.dir == "" || ks.rotation <= 0 {
+		return
+	}
+	if ks.rotationGuard == nil {
+		ks.rotationGuard = func(rotate func() error) error { return rotate() }
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := ks.Reload(); err != nil {
+				log.Printf("JWT key reload failed: %v", err)
+				continue
+			}
+
+			if !ks.rotationDue() {
+				continue
+			}
+			if err := ks.rotationGuard(ks.rotateIfDue); err != nil {
+				log.Printf("JWT key rotation failed: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (ks *KeySet) rotationDue() bool {
+	ks.mu.RLock()
+	signing := ks.signing
+	ks.mu.RUnlock()
+
+	return signing == nil || time.Since(signing.CreatedAt) >= ks.rotation
+}
+
+// rotateIfDue reloads first, another instance may have rotated while this one waited for the guard
+func (ks *KeySet) rotateIfDue() error {
+	if err := ks.Reload(); err != nil {
+		return err
+	}
+	if !ks.rotationDue() {
+		return nil
+	}
+	return ks.Rotate()
+}
+
+// Sign signs the claims with the current signing key and records its ID in the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	key := ks.signing
+	ks.mu.RUnlock()
+
+	if key == nil {
+		return "", errors.New("no signing key available")
+	}
+
+	token := jwt.NewWithClaims(signingMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc resolves the verification key from the kid header, for use with jwt.Parse.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key := ks.lookup(kid)
+	if key == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	// The algorithm comes from the key, never from the token header
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.public, nil
+}
+
+// Methods lists the algorithms of the held keys, for jwt.WithValidMethods.
+func (ks *KeySet) Methods() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	methods := []string{}
+	for _, key := range ks.keys {
+		if !slices.Contains(methods, key.Algorithm) {
+			methods = append(methods, key.Algorithm)
+		}
+	}
+	return methods
+}
+
+func (ks *KeySet) lookup(kid string) *Key {
+	ks.mu.RLock()
+	key := ks.keys[kid]
+	// Shared-secret tokens issued before key IDs were introduced carry no kid
+	if key == nil && kid == "" && ks.signing != nil && ks.signing.Algorithm == AlgorithmHS256 {
+		key = ks.signing
+	}
+	stale := ks.dir != "" && time.Since(ks.lastReload) > reloadThrottle
+	ks.mu.RUnlock()
+
+	// Another instance may have rotated to a key we have not seen yet
+	if key == nil && kid != "" && stale {
+		if err := ks.Reload(); err != nil {
+			log.Printf("JWT key reload failed: %v", err)
+			return nil
+		}
+		ks.mu.RLock()
+		key = ks.keys[kid]
+		ks.mu.RUnlock()
+	}
+
+	return key
+}
+
+func (ks *KeySet) pruneRetired() {
+	if ks.rotation <= 0 {
+		return
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	// A replaced key signed tokens for at most one rotation interval, and those live for the retention period
+	cutoff := time.Now().Add(-(ks.rotation + ks.retention))
+	for _, key := range ks.keys {
+		if key.private == nil || key == ks.signing || key.CreatedAt.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(key.path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove retired JWT key %s: %v", key.ID, err)
+		}
+	}
+}
+
+func readKeyFile(path string, modTime time.Time) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	key := &Key{
+		ID:        strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub"),
+		CreatedAt: modTime,
+		path:      path,
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.private, key.public = AlgorithmRS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.Algorithm, key.private, key.public = AlgorithmEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.Algorithm, key.public = AlgorithmRS256, k
+	case ed25519.PublicKey:
+		key.Algorithm, key.public = AlgorithmEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return key, nil
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
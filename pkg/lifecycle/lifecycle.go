@@ -0,0 +1,43 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Workers runs background loops of the RunX(stop) form and stops them together
+type Workers struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	return &Workers{stop: make(chan struct{})}
+}
+
+func (w *Workers) Go(run func(stop <-chan struct{})) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		run(w.stop)
+	}()
+}
+
+// Stop signals every worker and waits until they have returned or ctx ends. Workers finish the
+// job at hand first, e.g. the usage flusher writes its last batch.
+func (w *Workers) Stop(ctx context.Context) error {
+	close(w.stop)
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
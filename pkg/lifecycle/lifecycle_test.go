@@ -0,0 +1,55 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkers_Stop(t *testing.T) {
+	// Test case 1: Every worker is signalled and Stop waits until each has finished its job
+	t.Run("waits_for_workers", func(t *testing.T) {
+		workers := NewWorkers()
+		var finished atomic.Int32
+		for range 3 {
+			workers.Go(func(stop <-chan struct{}) {
+				<-stop
+				time.Sleep(10 * time.Millisecond) // Last job, e.g. a final flush
+				finished.Add(1)
+			})
+		}
+		workers.Go(func(stop <-chan struct{}) {}) // Returned on its own already
+
+		if err := workers.Stop(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if finished.Load() != 3 {
+			t.Errorf("Expected 3 workers to finish before Stop returned, got %d", finished.Load())
+		}
+	})
+
+	// Test case 2: A worker outliving the budget makes Stop give up with the context's error
+	t.Run("gives_up_at_deadline", func(t *testing.T) {
+		workers := NewWorkers()
+		release := make(chan struct{})
+		defer close(release)
+		workers.Go(func(stop <-chan struct{}) {
+			<-stop
+			<-release
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := workers.Stop(ctx)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected Stop to return at the deadline, took %s", elapsed)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package lifecycle
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check probes one dependency. Run gets a context that ends after Timeout.
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type ReadinessReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+func (r *ReadinessReport) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Readiness tells load balancers whether the instance takes new traffic. Reports are reused for
+// cacheFor, so frequent probes from many sources reach the dependencies at most once per period.
+// Readiness turns not-ready before shutdown starts, so traffic moves away while in-flight requests
+// still complete.
+type Readiness struct {
+	checks    []Check
+	cacheFor  time.Duration
+	draining  atomic.Bool
+	mu        sync.Mutex
+	report    *ReadinessReport
+	checkedAt time.Time
+}
+
+func NewReadiness(cacheFor time.Duration, checks ...Check) *Readiness {
+	return &Readiness{checks: checks, cacheFor: cacheFor}
+}
+
+func (r *Readiness) StartDraining() {
+	r.draining.Store(true)
+}
+
+// Report runs the checks in parallel, or returns the last report while it is fresh. Probes that
+// arrive during a run wait for its result instead of starting another one.
+func (r *Readiness) Report(ctx context.Context) *ReadinessReport {
+	if r.draining.Load() {
+		return &ReadinessReport{Status: StatusShuttingDown}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report != nil && time.Since(r.checkedAt) < r.cacheFor {
+		return r.report
+	}
+
+	results := make([]CheckResult, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := &ReadinessReport{Status: StatusReady, Checks: make(map[string]CheckResult, len(r.checks))}
+	for i, check := range r.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusNotReady
+		}
+	}
+
+	r.report, r.checkedAt = report, time.Now()
+	return report
+}
+
+// runCheck ignores the probe's own cancellation, the result is shared with other probes
+func runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{Status: StatusUp, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		// Errors may name hosts and users, they are logged rather than served to anonymous probes
+		log.Printf("Readiness check %s failed: %v", check.Name, err)
+		result.Status = StatusDown
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countedCheck returns a check that counts its runs and answers with err after delay
+func countedCheck(name string, runs *atomic.Int32, delay time.Duration, err error) Check {
+	return Check{Name: name, Timeout: time.Second, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		select {
+		case <-time.After(delay):
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}}
+}
+
+func TestReadiness_Report(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: Ready when every check passes
+	t.Run("all_up", func(t *testing.T) {
+		var runs atomic.Int32
+		readiness := NewReadiness(time.Minute, countedCheck("mysql", &runs, 0, nil), countedCheck("redis", &runs, 0, nil))
+
+		report := readiness.Report(ctx)
+
+		if !report.Ready() || report.Checks["mysql"].Status != StatusUp || report.Checks["redis"].Status != StatusUp {
+			t.Errorf("Expected ready with both checks up, got %+v", report)
+		}
+	})
+
+	// Test case 2: One failing check makes the instance not ready
+	t.Run("one_down", func(t *testing.T) {
+		var runs atomic.Int32
+		readiness := NewReadiness(time.Minute, countedCheck("mysql", &runs, 0, nil), countedCheck("redis", &runs, 0, errors.New("dial tcp: refused")))
+
+		report := readiness.Report(ctx)
+
+		if report.Status != StatusNotReady || report.Checks["mysql"].Status != StatusUp || report.Checks["redis"].Status != StatusDown {
+			t.Errorf("Expected not ready with redis down, got %+v", report)
+		}
+	})
+
+	// Test case 3: A check that hangs is down after its timeout
+	t.Run("check_timeout", func(t *testing.T) {
+		var runs atomic.Int32
+		check := countedCheck("mysql", &runs, time.Hour, nil)
+		check.Timeout = 10 * time.Millisecond
+		readiness := NewReadiness(time.Minute, check)
+
+		start := time.Now()
+		report := readiness.Report(ctx)
+
+		if report.Checks["mysql"].Status != StatusDown {
+			t.Errorf("Expected the hanging check to be down, got %+v", report)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the report after the check timeout, took %s", elapsed)
+		}
+	})
+
+	// Test case 4: A probe that gives up does not fail the checks shared with other probes
+	t.Run("probe_cancelled", func(t *testing.T) {
+		var runs atomic.Int32
+		readiness := NewReadiness(time.Minute, countedCheck("mysql", &runs, 5*time.Millisecond, nil))
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if report := readiness.Report(cancelled); !report.Ready() {
+			t.Errorf("Expected ready, got %+v", report)
+		}
+	})
+}
+
+func TestReadiness_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	// Test case 1: Reports are reused while fresh and refreshed afterwards
+	t.Run("reused_while_fresh", func(t *testing.T) {
+		var runs atomic.Int32
+		readiness := NewReadiness(50*time.Millisecond, countedCheck("mysql", &runs, 0, nil))
+
+		readiness.Report(ctx)
+		readiness.Report(ctx)
+		if runs.Load() != 1 {
+			t.Fatalf("Expected 1 run while fresh, got %d", runs.Load())
+		}
+
+		time.Sleep(60 * time.Millisecond)
+		readiness.Report(ctx)
+		if runs.Load() != 2 {
+			t.Errorf("Expected a new run once stale, got %d", runs.Load())
+		}
+	})
+
+	// Test case 2: Probes arriving during a run share its result
+	t.Run("concurrent_probes_share_run", func(t *testing.T) {
+		var runs atomic.Int32
+		readiness := NewReadiness(time.Minute, countedCheck("mysql", &runs, 20*time.Millisecond, nil))
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if report := readiness.Report(ctx); !report.Ready() {
+					t.Errorf("Expected ready, got %+v", report)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if runs.Load() != 1 {
+			t.Errorf("Expected 1 run for 10 probes, got %d", runs.Load())
+		}
+	})
+}
+
+func TestReadiness_Draining(t *testing.T) {
+	var runs atomic.Int32
+	readiness := NewReadiness(time.Minute, countedCheck("mysql", &runs, 0, nil))
+	readiness.Report(context.Background())
+
+	readiness.StartDraining()
+	report := readiness.Report(context.Background())
+
+	// Test case 1: Draining reports shutting down at once, even over a fresh cached report
+	if report.Status != StatusShuttingDown || report.Ready() {
+		t.Errorf("Expected shutting down, got %+v", report)
+	}
+	// Test case 2: Draining does not probe the dependencies
+	if runs.Load() != 1 {
+		t.Errorf("Expected no checks while draining, got %d runs", runs.Load())
+	}
+}
@@ -0,0 +1,306 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets suit request latencies in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample is one value of a metric read at scrape time
+type Sample struct {
+	Labels []string // Values in the order of the metric's label names
+	Value  float64
+}
+
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics served by Handler. Metrics created through the package functions are
+// registered with the default one, like expvar variables.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+var defaultRegistry = &Registry{}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.ContainsFunc(r.collectors, func(existing collector) bool { return existing.name() == c.name() }) {
+		panic("metrics: duplicate metric " + c.name())
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Handler serves the default registry in the Prometheus text exposition format
+func Handler() http.Handler {
+	return defaultRegistry
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	buf.Flush()
+}
+
+// series holds the values of one metric per label combination
+type series[V any] struct {
+	mu     sync.Mutex
+	values map[string]*V
+	labels map[string][]string
+}
+
+func (s *series[V]) get(labelCount int, labels []string, create func() *V) *V {
+	if len(labels) != labelCount {
+		panic(fmt.Sprintf("metrics: got %d label values, want %d", len(labels), labelCount))
+	}
+	key := strings.Join(labels, "\xff")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values, s.labels = make(map[string]*V), make(map[string][]string)
+	}
+	value, ok := s.values[key]
+	if !ok {
+		value = create()
+		s.values[key], s.labels[key] = value, slices.Clone(labels)
+	}
+	return value
+}
+
+// sorted calls fn for every label combination in a stable order, holding the lock
+func (s *series[V]) sorted(fn func(labels []string, value *V)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fn(s.labels[key], s.values[key])
+	}
+}
+
+type CounterVec struct {
+	metric     string
+	help       string
+	labelNames []string
+	series     series[float64]
+}
+
+// NewCounterVec registers a counter partitioned by the given labels
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{metric: name, help: help, labelNames: labelNames}
+	defaultRegistry.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add increases the counter by value, which must not be negative
+func (c *CounterVec) Add(value float64, labels ...string) {
+	counter := c.series.get(len(c.labelNames), labels, func() *float64 { return new(float64) })
+	c.series.mu.Lock()
+	*counter += value
+	c.series.mu.Unlock()
+}
+
+func (c *CounterVec) name() string {
+	return c.metric
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, c.metric, c.help, "counter")
+	c.series.sorted(func(labels []string, value *float64) {
+		writeSample(w, c.metric, c.labelNames, labels, "", "", *value)
+	})
+}
+
+type GaugeVec struct {
+	metric     string
+	help       string
+	labelNames []string
+	series     series[float64]
+}
+
+// NewGaugeVec registers a gauge partitioned by the given labels
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{metric: name, help: help, labelNames: labelNames}
+	defaultRegistry.register(g)
+	return g
+}
+
+func (g *GaugeVec) Set(value float64, labels ...string) {
+	gauge := g.series.get(len(g.labelNames), labels, func() *float64 { return new(float64) })
+	g.series.mu.Lock()
+	*gauge = value
+	g.series.mu.Unlock()
+}
+
+func (g *GaugeVec) name() string {
+	return g.metric
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	writeHeader(w, g.metric, g.help, "gauge")
+	g.series.sorted(func(labels []string, value *float64) {
+		writeSample(w, g.metric, g.labelNames, labels, "", "", *value)
+	})
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+type HistogramVec struct {
+	metric     string
+	help       string
+	labelNames []string
+	buckets    []float64
+	series     series[histogram]
+}
+
+// NewHistogramVec registers a histogram with the given upper bucket bounds, partitioned by the given labels
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{metric: name, help: help, labelNames: labelNames, buckets: slices.Sorted(slices.Values(buckets))}
+	defaultRegistry.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labels ...string) {
+	hist := h.series.get(len(h.labelNames), labels, func() *histogram {
+		return &histogram{counts: make([]uint64, len(h.buckets))}
+	})
+
+	h.series.mu.Lock()
+	defer h.series.mu.Unlock()
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.buckets) {
+		hist.counts[i]++
+	}
+	hist.sum += value
+	hist.count++
+}
+
+func (h *HistogramVec) name() string {
+	return h.metric
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, h.metric, h.help, "histogram")
+	h.series.sorted(func(labels []string, hist *histogram) {
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += hist.counts[i]
+			writeSample(w, h.metric+"_bucket", h.labelNames, labels, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, h.metric+"_bucket", h.labelNames, labels, "le", "+Inf", float64(hist.count))
+		writeSample(w, h.metric+"_sum", h.labelNames, labels, "", "", hist.sum)
+		writeSample(w, h.metric+"_count", h.labelNames, labels, "", "", float64(hist.count))
+	})
+}
+
+// sampled reads its values when scraped, for numbers kept elsewhere such as connection pool stats
+type sampled struct {
+	metric     string
+	help       string
+	kind       string
+	labelNames []string
+	collect    func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose samples are read from collect at every scrape
+func NewGaugeFunc(name, help string, collect func() []Sample, labelNames ...string) {
+	defaultRegistry.register(&sampled{metric: name, help: help, kind: "gauge", labelNames: labelNames, collect: collect})
+}
+
+// NewCounterFunc registers a counter kept elsewhere, read from collect at every scrape
+func NewCounterFunc(name, help string, collect func() []Sample, labelNames ...string) {
+	defaultRegistry.register(&sampled{metric: name, help: help, kind: "counter", labelNames: labelNames, collect: collect})
+}
+
+func (s *sampled) name() string {
+	return s.metric
+}
+
+func (s *sampled) write(w *bufio.Writer) {
+	writeHeader(w, s.metric, s.help, s.kind)
+	for _, sample := range s.collect() {
+		writeSample(w, s.metric, s.labelNames, sample.Labels, "", "", sample.Value)
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeSample writes one line. extraName and extraValue add a label after the metric's own, e.g. le.
+func writeSample(w *bufio.Writer, name string, labelNames, labels []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, labelName, labels[i])
+		}
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	labelEscaper.WriteString(w, value)
+	w.WriteByte('"')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
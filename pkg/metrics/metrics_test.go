@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Lines of the text exposition format: https://prometheus.io/docs/instrumenting/exposition_formats/
+var (
+	helpLine   = regexp.MustCompile(`^# HELP ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	typeLine   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (counter|gauge|histogram)$`)
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(?:\{((?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*",?)*)\})? (\S+)$`)
+	labelPair  = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\.)*)"`)
+)
+
+type family struct {
+	help    string
+	kind    string
+	samples map[string]float64 // Keyed by name{labels} as written
+}
+
+// scrape serves the registry and parses the output, failing on any line that is not valid exposition format
+func scrape(t *testing.T, registry *Registry) map[string]*family {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Expected the text exposition content type, got %q", ct)
+	}
+
+	families := make(map[string]*family)
+	var current *family
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case helpLine.MatchString(line):
+			m := helpLine.FindStringSubmatch(line)
+			if _, ok := families[m[1]]; ok {
+				t.Fatalf("Metric %s written twice", m[1])
+			}
+			current = &family{help: m[2], samples: make(map[string]float64)}
+			families[m[1]] = current
+		case typeLine.MatchString(line):
+			m := typeLine.FindStringSubmatch(line)
+			if current == nil || families[m[1]] != current {
+				t.Fatalf("TYPE line without its HELP line: %q", line)
+			}
+			current.kind = m[2]
+		case sampleLine.MatchString(line):
+			m := sampleLine.FindStringSubmatch(line)
+			if current == nil {
+				t.Fatalf("Sample before any HELP line: %q", line)
+			}
+			value, err := strconv.ParseFloat(m[3], 64)
+			if err != nil {
+				t.Fatalf("Invalid sample value in %q: %v", line, err)
+			}
+			key := m[1] + "{" + strings.TrimSuffix(m[2], ",") + "}"
+			if _, ok := current.samples[key]; ok {
+				t.Fatalf("Sample %s written twice", key)
+			}
+			current.samples[key] = value
+		default:
+			t.Fatalf("Invalid exposition line: %q", line)
+		}
+	}
+	return families
+}
+
+func TestRegistry_Counter(t *testing.T) {
+	registry := &Registry{}
+	counter := &CounterVec{metric: "requests_total", help: "Requests by method.", labelNames: []string{"method"}}
+	registry.register(counter)
+
+	counter.Inc("GET")
+	counter.Inc("GET")
+	counter.Add(2.5, "POST")
+
+	families := scrape(t, registry)
+
+	requests := families["requests_total"]
+	if requests == nil || requests.kind != "counter" || requests.help != "Requests by method." {
+		t.Fatalf("Expected the requests_total counter, got %+v", requests)
+	}
+	if requests.samples[`requests_total{method="GET"}`] != 2 || requests.samples[`requests_total{method="POST"}`] != 2.5 {
+		t.Errorf("Expected GET 2 and POST 2.5, got %v", requests.samples)
+	}
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	registry := &Registry{}
+	histogram := &HistogramVec{metric: "latency_seconds", help: "Latency.", labelNames: []string{"route"}, buckets: []float64{0.1, 1}}
+	registry.register(histogram)
+
+	histogram.Observe(0.05, "/a")
+	histogram.Observe(0.1, "/a") // Upper bounds are inclusive
+	histogram.Observe(0.5, "/a")
+	histogram.Observe(5, "/a")
+
+	samples := scrape(t, registry)["latency_seconds"].samples
+
+	expected := map[string]float64{
+		`latency_seconds_bucket{route="/a",le="0.1"}`:  2,
+		`latency_seconds_bucket{route="/a",le="1"}`:    3,
+		`latency_seconds_bucket{route="/a",le="+Inf"}`: 4,
+		`latency_seconds_sum{route="/a"}`:              5.65,
+		`latency_seconds_count{route="/a"}`:            4,
+	}
+	for key, want := range expected {
+		if got, ok := samples[key]; !ok || math.Abs(got-want) > 1e-9 {
+			t.Errorf("Expected %s = %v, got %v (present %v)", key, want, got, ok)
+		}
+	}
+}
+
+func TestRegistry_GaugeAndFuncs(t *testing.T) {
+	registry := &Registry{}
+	gauge := &GaugeVec{metric: "degraded", help: "Degraded.", labelNames: []string{"mode"}}
+	registry.register(gauge)
+	registry.register(&sampled{metric: "pool_open", help: "Open connections.", kind: "gauge", collect: func() []Sample {
+		return []Sample{{Value: 7}}
+	}})
+
+	gauge.Set(1, "local")
+	gauge.Set(0, "local")
+
+	families := scrape(t, registry)
+
+	if families["degraded"].kind != "gauge" || families["degraded"].samples[`degraded{mode="local"}`] != 0 {
+		t.Errorf("Expected the gauge to hold its last value, got %+v", families["degraded"])
+	}
+	if families["pool_open"].samples["pool_open{}"] != 7 {
+		t.Errorf("Expected the collected sample, got %+v", families["pool_open"])
+	}
+}
+
+func TestRegistry_Escaping(t *testing.T) {
+	registry := &Registry{}
+	counter := &CounterVec{metric: "escaped_total", help: "Help with \\ and\nnewline.", labelNames: []string{"value"}}
+	registry.register(counter)
+
+	counter.Inc("quote \" backslash \\ newline \n")
+
+	families := scrape(t, registry)
+
+	escaped := families["escaped_total"]
+	if escaped.help != `Help with \\ and\nnewline.` {
+		t.Errorf("Expected help text to be escaped, got %q", escaped.help)
+	}
+	for key := range escaped.samples {
+		m := labelPair.FindStringSubmatch(key)
+		if m == nil || m[2] != `quote \" backslash \\ newline \n` {
+			t.Errorf("Expected the label value to be escaped, got %q", key)
+		}
+	}
+}
+
+func TestRegistry_Misuse(t *testing.T) {
+	// Test case 1: Registering a name twice
+	t.Run("duplicate_metric", func(t *testing.T) {
+		registry := &Registry{}
+		registry.register(&CounterVec{metric: "dup_total"})
+
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a panic for a duplicate metric")
+			}
+		}()
+		registry.register(&GaugeVec{metric: "dup_total"})
+	})
+
+	// Test case 2: Wrong number of label values
+	t.Run("label_count_mismatch", func(t *testing.T) {
+		counter := &CounterVec{metric: "labels_total", labelNames: []string{"a", "b"}}
+
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a panic for a missing label value")
+			}
+		}()
+		counter.Inc("only-a")
+	})
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+var auditAnonymousRejections = metrics.NewCounterVec("audit_anonymous_rejections_total",
+	"Anonymous requests answered 401 or 429, counted instead of written to the audit log.", "method", "route", "status")
+
+// Route parameters that identify the entity when a handler does not name one itself
+var auditEntityParams = []struct {
+	param      string
+	entityType string
+}{
+	{"staff_id", "staff"},
+	{"session_id", "session"},
+	{"export_id", "data_export"},
+	{"customer_id", "customer"},
+}
+
+// AuditTrail records every request that is not a read once the handler has finished.
+// It runs before authentication so rejected attempts are recorded as well. Anonymous requests answered
+// 401 or 429 are only counted: every entry locks the chain head, so a flood of them would queue on that lock.
+func AuditTrail(logger domain.AuditLogger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		ctx.Next()
+
+		entry := &domain.AuditEntry{
+			ActorType:  "anonymous",
+			Role:       ctx.GetString("role"),
+			IPAddress:  ctx.ClientIP(),
+			RequestID:  GetRequestIDFromContext(ctx),
+			Action:     ctx.GetString("auditAction"),
+			EntityType: ctx.GetString("auditEntityType"),
+			EntityID:   ctx.GetString("auditEntityID"),
+			Before:     ctx.GetString("auditBefore"),
+			After:      ctx.GetString("auditAfter"),
+			StatusCode: ctx.Writer.Status(),
+		}
+
+		if staffID, ok := GetStaffIDFromContext(ctx); ok {
+			entry.ActorType, entry.ActorID = domain.SubjectTypeStaff, staffID
+		} else if customerID := ctx.GetString("customerID"); customerID != "" {
+			entry.ActorType, entry.ActorID = domain.SubjectTypeCustomer, customerID
+		}
+
+		if entry.ActorType == "anonymous" && (entry.StatusCode == http.StatusUnauthorized || entry.StatusCode == http.StatusTooManyRequests) {
+			route := ctx.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := ctx.Request.Method
+			if !knownMethods[method] {
+				method = "other"
+			}
+			auditAnonymousRejections.Inc(method, route, strconv.Itoa(entry.StatusCode))
+			return
+		}
+
+		if entry.Action == "" {
+			path := ctx.FullPath()
+			if path == "" {
+				path = ctx.Request.URL.Path
+			}
+			entry.Action = ctx.Request.Method + " " + path
+		}
+		if entry.EntityType == "" {
+			for _, candidate := range auditEntityParams {
+				if id := ctx.Param(candidate.param); id != "" {
+					entry.EntityType, entry.EntityID = candidate.entityType, id
+					break
+				}
+			}
+		}
+
+		switch status := entry.StatusCode; {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			entry.Result = domain.AuditResultDenied
+		case status >= http.StatusBadRequest:
+			entry.Result = domain.AuditResultFailure
+		default:
+			entry.Result = domain.AuditResultSuccess
+		}
+
+		// The response is already sent, a lost entry is logged rather than surfaced to the client. The request
+		// ran either way, so the entry is written even after its deadline or once the client has gone away.
+		if err := logger.Record(context.WithoutCancel(ctx.Request.Context()), entry); err != nil {
+			log.Printf("Failed to record audit entry for request %s (%s): %v", entry.RequestID, entry.Action, err)
+		}
+	}
+}
+
+// SetAuditAction replaces the default "METHOD /route" action with a domain name such as "credit_limit.set"
+func SetAuditAction(ctx *gin.Context, action string) {
+	ctx.Set("auditAction", action)
+}
+
+func SetAuditEntity(ctx *gin.Context, entityType, entityID string) {
+	ctx.Set("auditEntityType", entityType)
+	ctx.Set("auditEntityID", entityID)
+}
+
+// SetAuditSnapshots stores the state before and after the change. Pass response models, never domain structs holding secrets.
+func SetAuditSnapshots(ctx *gin.Context, before, after interface{}) {
+	for key, snapshot := range map[string]interface{}{"auditBefore": before, "auditAfter": after} {
+		if snapshot == nil {
+			continue
+		}
+		encoded, err := json.Marshal(snapshot)
+		if err != nil {
+			log.Printf("Failed to encode audit snapshot: %v", err)
+			continue
+		}
+		if string(encoded) == "null" { // Typed nil pointer
+			continue
+		}
+		ctx.Set(key, string(encoded))
+	}
+}
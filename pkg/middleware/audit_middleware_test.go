@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recordingAuditLogger struct {
+	entries []*domain.AuditEntry
+}
+
+func (l *recordingAuditLogger) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func TestAuditTrail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(logger domain.AuditLogger, status int, staffID string) *gin.Engine {
+		router := gin.New()
+		router.Use(AuditTrail(logger))
+		router.POST("/audit-test/:customer_id", func(c *gin.Context) {
+			if staffID != "" {
+				c.Set("staffID", staffID)
+			}
+			c.Status(status)
+		})
+		return router
+	}
+
+	// Test case 1: A write by staff is recorded with its actor, entity and result
+	t.Run("records_staff_write", func(t *testing.T) {
+		logger := &recordingAuditLogger{}
+		newRouter(logger, http.StatusCreated, "staff-id-123").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/audit-test/cust-1", nil))
+
+		if len(logger.entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(logger.entries))
+		}
+		entry := logger.entries[0]
+		if entry.ActorType != domain.SubjectTypeStaff || entry.EntityID != "cust-1" || entry.Result != domain.AuditResultSuccess {
+			t.Errorf("Expected a successful staff write on cust-1, got %+v", entry)
+		}
+	})
+
+	// Test case 2: Anonymous 401 and 429 are counted instead of recorded
+	t.Run("counts_anonymous_rejections", func(t *testing.T) {
+		logger := &recordingAuditLogger{}
+		for _, status := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+			newRouter(logger, status, "").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/audit-test/cust-1", nil))
+		}
+
+		if len(logger.entries) != 0 {
+			t.Fatalf("Expected no entries, got %d", len(logger.entries))
+		}
+		rec := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		for _, status := range []string{"401", "429"} {
+			series := `audit_anonymous_rejections_total{method="POST",route="/audit-test/:customer_id",status="` + status + `"} 1`
+			if !strings.Contains(rec.Body.String(), series) {
+				t.Errorf("Expected %s, got:\n%s", series, rec.Body.String())
+			}
+		}
+	})
+
+	// Test case 3: Other anonymous failures and denials of signed-in users are still recorded
+	t.Run("records_other_rejections", func(t *testing.T) {
+		logger := &recordingAuditLogger{}
+		newRouter(logger, http.StatusBadRequest, "").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/audit-test/cust-1", nil))
+		newRouter(logger, http.StatusForbidden, "staff-id-123").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/audit-test/cust-1", nil))
+
+		if len(logger.entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(logger.entries))
+		}
+		if logger.entries[0].Result != domain.AuditResultFailure || logger.entries[1].Result != domain.AuditResultDenied {
+			t.Errorf("Expected a failure and a denial, got %s and %s", logger.entries[0].Result, logger.entries[1].Result)
+		}
+	})
+}
@@ -2,17 +2,17 @@ package middleware
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"strings"
-	"xyz-multifinance-api/config"
+	"xyz-multifinance-api/internal/domain"
 	"xyz-multifinance-api/internal/model"
+	"xyz-multifinance-api/pkg/jwtkeys"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+func JWTAuthMiddleware(keySet *jwtkeys.KeySet, denylist domain.TokenDenylist) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -29,12 +29,7 @@ func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		claims := &model.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(cfg.JWTSecret), nil
-		}, jwt.WithValidMethods([]string{"HS256"}))
+		token, err := jwt.ParseWithClaims(tokenString, claims, keySet.Keyfunc, jwt.WithValidMethods(keySet.Methods()))
 
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenNotValidYet) {
@@ -45,13 +40,31 @@ func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		if !token.Valid {
+		if !token.Valid || claims.TokenType != model.TokenTypeAccess {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
+		// Fail closed, a revoked session must not slip through while Redis is unreachable
+		denied, err := denylist.IsDenied(ctx.Request.Context(), claims.SessionID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify token"})
+			ctx.Error(err)
+			return
+		}
+		if denied {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			return
+		}
+
 		ctx.Set("customerID", claims.CustomerID)
 		ctx.Set("customerNIK", claims.NIK)
+		ctx.Set("staffID", claims.StaffID)
+		ctx.Set("branchCode", claims.BranchCode)
+		ctx.Set("role", claims.Role)
+		ctx.Set("permissions", claims.Permissions)
+		ctx.Set("sessionID", claims.SessionID)
+		ctx.Set("mfaVerified", claims.MFAVerified)
 
 		ctx.Next()
 	}
@@ -72,3 +85,24 @@ func GetCustomerNIKFromContext(ctx *gin.Context) (string, bool) {
 	}
 	return customerNIK.(string), true
 }
+
+func GetStaffIDFromContext(ctx *gin.Context) (string, bool) {
+	staffID, exists := ctx.Get("staffID")
+	if !exists || staffID.(string) == "" {
+		return "", false
+	}
+	return staffID.(string), true
+}
+
+func GetBranchCodeFromContext(ctx *gin.Context) (string, bool) {
+	branchCode, exists := ctx.Get("branchCode")
+	if !exists {
+		return "", false
+	}
+	return branchCode.(string), true
+}
+
+func IsMFAVerified(ctx *gin.Context) bool {
+	mfaVerified, exists := ctx.Get("mfaVerified")
+	return exists && mfaVerified.(bool)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission lets the request through when the token carries at least one of the given permissions.
+// Permissions flagged by domain.PermissionRequiresMFA only count when the session passed multi-factor authentication.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		mfaMissing := false
+		for _, permission := range permissions {
+			if !HasPermission(ctx, permission) {
+				continue
+			}
+			if domain.PermissionRequiresMFA(permission) && !IsMFAVerified(ctx) {
+				mfaMissing = true
+				continue
+			}
+			ctx.Next()
+			return
+		}
+
+		if mfaMissing {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Multi-factor authentication required", "mfa_required": true})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// RequireCustomerAccess guards routes scoped by the :customer_id path parameter.
+// Holders of anyPermission may access every customer, holders of ownPermission only themselves.
+func RequireCustomerAccess(anyPermission, ownPermission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !CanAccessCustomer(ctx, ctx.Param("customer_id"), anyPermission, ownPermission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// CanAccessCustomer is the ownership check for handlers that only learn the customer from the body or a lookup.
+func CanAccessCustomer(ctx *gin.Context, customerID, anyPermission, ownPermission string) bool {
+	if HasPermission(ctx, anyPermission) {
+		return true
+	}
+	if !HasPermission(ctx, ownPermission) {
+		return false
+	}
+
+	authCustomerID, exists := GetCustomerIDFromContext(ctx)
+	return exists && authCustomerID != "" && authCustomerID == customerID
+}
+
+func HasPermission(ctx *gin.Context, permission string) bool {
+	return slices.Contains(GetPermissionsFromContext(ctx), permission)
+}
+
+func GetPermissionsFromContext(ctx *gin.Context) []string {
+	permissions, exists := ctx.Get("permissions")
+	if !exists {
+		return nil
+	}
+	return permissions.([]string)
+}
+
+func GetRoleFromContext(ctx *gin.Context) (string, bool) {
+	role, exists := ctx.Get("role")
+	if !exists {
+		return "", false
+	}
+	return role.(string), true
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"xyz-multifinance-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authenticated stands in for JWTAuthMiddleware and sets the claims it would set
+func authenticated(customerID string, mfaVerified bool, permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set("customerID", customerID)
+		ctx.Set("permissions", permissions)
+		ctx.Set("mfaVerified", mfaVerified)
+		ctx.Next()
+	}
+}
+
+func serve(t *testing.T, route, path string, handlers ...gin.HandlerFunc) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(route, append(handlers, func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"ok": true}) })...)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got %q", rec.Body.String())
+	}
+	return rec.Code, body
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		auth        gin.HandlerFunc
+		required    []string
+		wantStatus  int
+		wantMFAFlag bool
+	}{
+		{
+			name:       "has_permission",
+			auth:       authenticated("", false, domain.PermCustomerRead),
+			required:   []string{domain.PermCustomerRead},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "has_one_of_several",
+			auth:       authenticated("", false, domain.PermTransactionCreateOwn),
+			required:   []string{domain.PermTransactionCreate, domain.PermTransactionCreateOwn},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing_permission",
+			auth:       authenticated("", false, domain.PermCustomerReadOwn),
+			required:   []string{domain.PermCustomerRead},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "no_claims",
+			auth:       func(ctx *gin.Context) { ctx.Next() },
+			required:   []string{domain.PermCustomerRead},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:        "mfa_permission_without_mfa",
+			auth:        authenticated("", false, domain.PermCreditLimitWrite),
+			required:    []string{domain.PermCreditLimitWrite},
+			wantStatus:  http.StatusForbidden,
+			wantMFAFlag: true,
+		},
+		{
+			name:       "mfa_permission_with_mfa",
+			auth:       authenticated("", true, domain.PermCreditLimitWrite),
+			required:   []string{domain.PermCreditLimitWrite},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other_permission_without_mfa",
+			auth:       authenticated("", false, domain.PermCreditLimitWrite, domain.PermCreditLimitRead),
+			required:   []string{domain.PermCreditLimitWrite, domain.PermCreditLimitRead},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "mfa_does_not_grant_permissions",
+			auth:       authenticated("", true, domain.PermCreditLimitRead),
+			required:   []string{domain.PermCreditLimitWrite},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serve(t, "/resource", "/resource", tt.auth, RequirePermission(tt.required...))
+
+			if status != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d (%v)", tt.wantStatus, status, body)
+			}
+			if mfaRequired, _ := body["mfa_required"].(bool); mfaRequired != tt.wantMFAFlag {
+				t.Errorf("Expected mfa_required %v, got %v", tt.wantMFAFlag, body)
+			}
+		})
+	}
+}
+
+func TestRequireCustomerAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       gin.HandlerFunc
+		path       string
+		wantStatus int
+	}{
+		{
+			name:       "any_permission_other_customer",
+			auth:       authenticated("", false, domain.PermCustomerRead),
+			path:       "/customers/customer-2",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "own_permission_same_customer",
+			auth:       authenticated("customer-1", false, domain.PermCustomerReadOwn),
+			path:       "/customers/customer-1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "own_permission_other_customer",
+			auth:       authenticated("customer-1", false, domain.PermCustomerReadOwn),
+			path:       "/customers/customer-2",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "same_customer_without_permission",
+			auth:       authenticated("customer-1", false, domain.PermTransactionReadOwn),
+			path:       "/customers/customer-1",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serve(t, "/customers/:customer_id", tt.path, tt.auth,
+				RequireCustomerAccess(domain.PermCustomerRead, domain.PermCustomerReadOwn))
+
+			if status != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d (%v)", tt.wantStatus, status, body)
+			}
+		})
+	}
+}
+
+func TestCanAccessCustomer(t *testing.T) {
+	tests := []struct {
+		name        string
+		claims      map[string]any
+		customerID  string
+		wantAllowed bool
+	}{
+		{
+			name:        "any_permission",
+			claims:      map[string]any{"permissions": []string{domain.PermTransactionCreate}},
+			customerID:  "customer-2",
+			wantAllowed: true,
+		},
+		{
+			name:        "own_customer",
+			claims:      map[string]any{"customerID": "customer-1", "permissions": []string{domain.PermTransactionCreateOwn}},
+			customerID:  "customer-1",
+			wantAllowed: true,
+		},
+		{
+			name:        "other_customer",
+			claims:      map[string]any{"customerID": "customer-1", "permissions": []string{domain.PermTransactionCreateOwn}},
+			customerID:  "customer-2",
+			wantAllowed: false,
+		},
+		{
+			// Staff tokens carry no customer ID, an empty target must not match it
+			name:        "empty_ids_do_not_match",
+			claims:      map[string]any{"customerID": "", "permissions": []string{domain.PermTransactionCreateOwn}},
+			customerID:  "",
+			wantAllowed: false,
+		},
+		{
+			name:        "customer_id_missing_from_context",
+			claims:      map[string]any{"permissions": []string{domain.PermTransactionCreateOwn}},
+			customerID:  "customer-1",
+			wantAllowed: false,
+		},
+		{
+			name:        "no_claims",
+			claims:      map[string]any{},
+			customerID:  "customer-1",
+			wantAllowed: false,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			for key, value := range tt.claims {
+				ctx.Set(key, value)
+			}
+
+			allowed := CanAccessCustomer(ctx, tt.customerID, domain.PermTransactionCreate, domain.PermTransactionCreateOwn)
+
+			if allowed != tt.wantAllowed {
+				t.Errorf("Expected allowed %v, got %v", tt.wantAllowed, allowed)
+			}
+		})
+	}
+}
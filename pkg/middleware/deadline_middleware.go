@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusClientClosedRequest answers requests the client gave up on before the response was ready.
+// Nobody reads it, it marks the request in access logs and metrics.
+const StatusClientClosedRequest = 499
+
+// DeadlineConfig holds the default request deadline and other ones for individual routes,
+// keyed by method and route pattern, e.g. "GET /api/v1/admin/audit-logs/verify".
+type DeadlineConfig struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// RequestDeadline puts a deadline on the request context. Queries and Redis calls made for the request
+// use that context, so they are cancelled once the deadline passes or the client disconnects.
+func RequestDeadline(config DeadlineConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := config.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = config.Default
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// AbortWithServerError answers an unexpected error with 500, unless the request failed because it ran
+// past its deadline (504) or the client went away (499). Use cases wrap errors without keeping the chain,
+// so the request context tells which it was.
+func AbortWithServerError(c *gin.Context, err error) {
+	c.Error(err)
+	switch ctxErr := c.Request.Context().Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case errors.Is(ctxErr, context.Canceled):
+		c.AbortWithStatusJSON(StatusClientClosedRequest, gin.H{"error": "client closed request"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"xyz-multifinance-api/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec("http_requests_total",
+		"HTTP requests by method, route template and status.", "method", "route", "status")
+	httpRequestDuration = metrics.NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency by method, route template and status.", metrics.DefaultBuckets, "method", "route", "status")
+	rateLimitRejections = metrics.NewCounterVec("rate_limit_rejections_total",
+		"Requests rejected with 429 by rate limit policy.", "policy")
+)
+
+// Methods other than these are labelled "other", clients choose the method and could create any number of series
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// RequestMetrics counts requests and their latency. Requests are labelled with the route template rather
+// than the path, so IDs in the path do not create a series each, and unmatched paths share one label.
+func RequestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = "other"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.Inc(method, route, status)
+		httpRequestDuration.Observe(time.Since(start).Seconds(), method, route, status)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"xyz-multifinance-api/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestMetrics_MethodLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestMetrics())
+	router.GET("/metrics-test/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-test/123", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/metrics-test/123", nil))
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	// Test case 1: Known methods keep their name and the route template is used
+	if !strings.Contains(body, `http_requests_total{method="GET",route="/metrics-test/:id",status="200"} 1`) {
+		t.Errorf("Expected a GET series for the route template, got:\n%s", body)
+	}
+	// Test case 2: Methods chosen by the client are folded into one label
+	if strings.Contains(body, `method="BREW"`) || !strings.Contains(body, `http_requests_total{method="other",`) {
+		t.Errorf("Expected the unknown method to be labelled other, got:\n%s", body)
+	}
+}
@@ -2,64 +2,52 @@ package middleware
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
-
-	internalredis "xyz-multifinance-api/internal/infrastructure/redis"
+	"xyz-multifinance-api/internal/domain"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
+// RateLimiterConfig holds the default policy and stricter ones for individual routes,
+// keyed by method and route pattern, e.g. "POST /api/v1/auth/login".
 type RateLimiterConfig struct {
-	RequestsPerSecond int
-	Burst             int
-	Window            time.Duration
+	Default domain.RateLimitPolicy
+	Routes  map[string]domain.RateLimitPolicy
 }
 
-func RateLimitMiddleware(config RateLimiterConfig, rdbClient *redis.Client) gin.HandlerFunc { // NEW: rdbClient argument
-	if config.Window == 0 {
-		config.Window = time.Second // Default window to 1 second
-	}
-
+// RateLimitMiddleware throttles with a token bucket per client. Behind JWTAuthMiddleware the
+// bucket belongs to the authenticated customer or staff user, otherwise to the client IP.
+func RateLimitMiddleware(config RateLimiterConfig, limiter domain.RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		if ip == "" {
-			ip = "unknown" // Fallback
+		policy, ok := config.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			policy = config.Default
 		}
 
-		// Use a fixed-window key based on current time window
-		windowStart := time.Now().Truncate(config.Window).UnixNano() / int64(config.Window)
-		key := fmt.Sprintf("rate_limit:%s:%d", ip, windowStart)
-
-		pipe := rdbClient.Pipeline() // Use injected rdbClient
-		incr := pipe.Incr(internalredis.Ctx, key)
-		pipe.Expire(internalredis.Ctx, key, config.Window) // Set/reset expiration for the window
-		_, err := pipe.Exec(internalredis.Ctx)
-
+		result, err := limiter.Allow(c.Request.Context(), rateLimitIdentity(c), policy)
+		if err != nil && c.Request.Context().Err() != nil {
+			AbortWithServerError(c, err)
+			return
+		}
 		if err != nil {
 			// Log the error but don't expose Redis details to client
-			c.Error(fmt.Errorf("redis pipeline error for rate limit: %w", err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Rate limit service unavailable"})
+			c.Error(fmt.Errorf("rate limit check failed: %w", err))
+			c.Header("Retry-After", "5")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limit service unavailable"})
 			return
 		}
 
-		currentRequests := incr.Val()
-
-		// Calculate Retry-After based on remaining time in the current window
-		ttl := rdbClient.TTL(internalredis.Ctx, key).Val() // Use injected rdbClient
-		retryAfterSeconds := int(ttl.Seconds())
-		if retryAfterSeconds <= 0 { // If TTL is already expired or negative, set to 1s
-			retryAfterSeconds = 1
-		}
-
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", config.RequestsPerSecond))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", max(0, config.RequestsPerSecond-int(currentRequests))))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(ttl).Unix())) // Unix timestamp of reset
+		c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10)) // Unix time the bucket is full again
 
-		if currentRequests > int64(config.RequestsPerSecond) {
-			c.Header("Retry-After", fmt.Sprintf("%d", retryAfterSeconds))
+		if !result.Allowed {
+			rateLimitRejections.Inc(policy.Name)
+			retryAfterSeconds := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error":   "Too Many Requests",
 				"message": fmt.Sprintf("You have exceeded your request rate limit. Please try again after %d seconds.", retryAfterSeconds),
@@ -67,6 +55,21 @@ func RateLimitMiddleware(config RateLimiterConfig, rdbClient *redis.Client) gin.
 			return
 		}
 
-		c.Next() // Continue to the next handler
+		c.Next()
+	}
+}
+
+func rateLimitIdentity(c *gin.Context) string {
+	if customerID, ok := GetCustomerIDFromContext(c); ok && customerID != "" {
+		return "customer:" + customerID
+	}
+	if staffID, ok := GetStaffIDFromContext(c); ok {
+		return "staff:" + staffID
+	}
+
+	ip := c.ClientIP()
+	if ip == "" {
+		ip = "unknown" // Fallback
 	}
+	return "ip:" + ip
 }
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"xyz-multifinance-api/internal/domain"
+	"xyz-multifinance-api/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIQuotaMeter identifies a partner by API key and counts the request against its monthly quota
+type APIQuotaMeter interface {
+	Meter(ctx context.Context, apiKey, endpoint string) (*domain.APIClient, *model.QuotaStatus, error)
+}
+
+// UsageMetering counts partner requests per client and endpoint. Requests without an API key pass untouched.
+func UsageMetering(meter APIQuotaMeter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader(APIKeyHeader)
+		if apiKey == "" {
+			c.Next()
+			return
+		}
+
+		client, status, err := meter.Meter(c.Request.Context(), apiKey, c.Request.Method+" "+c.FullPath())
+		if status != nil {
+			setQuotaHeaders(c, status)
+		}
+		if err != nil {
+			var retryErr *domain.RetryAfterError
+			switch {
+			case errors.As(err, &retryErr) && errors.Is(err, domain.ErrQuotaExceeded):
+				c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryErr.RetryAfter.Seconds())))))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"error":   "Quota Exceeded",
+					"message": fmt.Sprintf("The monthly quota of %d requests for this API key is used up.", status.Limit),
+				})
+			case errors.Is(err, domain.ErrInvalidInput):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			case errors.Is(err, domain.ErrAccountDisabled):
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key is disabled"})
+			default:
+				AbortWithServerError(c, err)
+			}
+			return
+		}
+
+		c.Set("apiClientID", client.ID)
+		c.Next()
+	}
+}
+
+func GetAPIClientIDFromContext(c *gin.Context) (string, bool) {
+	clientID, exists := c.Get("apiClientID")
+	if !exists {
+		return "", false
+	}
+	return clientID.(string), true
+}
+
+func setQuotaHeaders(c *gin.Context, status *model.QuotaStatus) {
+	c.Header("X-Quota-Reset", strconv.FormatInt(status.ResetAt.Unix(), 10)) // Unix time the monthly quota starts over
+	if status.Limit == 0 {
+		c.Header("X-Quota-Limit", "unlimited")
+		return
+	}
+	c.Header("X-Quota-Limit", strconv.FormatInt(status.Limit, 10))
+	c.Header("X-Quota-Remaining", strconv.FormatInt(max(0, status.Limit-status.Used), 10))
+}
@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/api_client.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/api_client.go -destination=test/mock/api_client_mock.go -package=mock APIClientRepository,APIUsageRepository,UsageMeter
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIClientRepository is a mock of APIClientRepository interface.
+type MockAPIClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIClientRepositoryMockRecorder is the mock recorder for MockAPIClientRepository.
+type MockAPIClientRepositoryMockRecorder struct {
+	mock *MockAPIClientRepository
+}
+
+// NewMockAPIClientRepository creates a new mock instance.
+func NewMockAPIClientRepository(ctrl *gomock.Controller) *MockAPIClientRepository {
+	mock := &MockAPIClientRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIClientRepository) EXPECT() *MockAPIClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIClientRepository) Create(ctx context.Context, client *domain.APIClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIClientRepositoryMockRecorder) Create(ctx, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIClientRepository)(nil).Create), ctx, client)
+}
+
+// FindByID mocks base method.
+func (m *MockAPIClientRepository) FindByID(ctx context.Context, id string) (*domain.APIClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.APIClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockAPIClientRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAPIClientRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByKeyHash mocks base method.
+func (m *MockAPIClientRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.APIClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByKeyHash", ctx, keyHash)
+	ret0, _ := ret[0].(*domain.APIClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByKeyHash indicates an expected call of FindByKeyHash.
+func (mr *MockAPIClientRepositoryMockRecorder) FindByKeyHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByKeyHash", reflect.TypeOf((*MockAPIClientRepository)(nil).FindByKeyHash), ctx, keyHash)
+}
+
+// MockAPIUsageRepository is a mock of APIUsageRepository interface.
+type MockAPIUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIUsageRepositoryMockRecorder is the mock recorder for MockAPIUsageRepository.
+type MockAPIUsageRepositoryMockRecorder struct {
+	mock *MockAPIUsageRepository
+}
+
+// NewMockAPIUsageRepository creates a new mock instance.
+func NewMockAPIUsageRepository(ctrl *gomock.Controller) *MockAPIUsageRepository {
+	mock := &MockAPIUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIUsageRepository) EXPECT() *MockAPIUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddUsage mocks base method.
+func (m *MockAPIUsageRepository) AddUsage(ctx context.Context, batchID string, usage []domain.APIUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUsage", ctx, batchID, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUsage indicates an expected call of AddUsage.
+func (mr *MockAPIUsageRepositoryMockRecorder) AddUsage(ctx, batchID, usage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUsage", reflect.TypeOf((*MockAPIUsageRepository)(nil).AddUsage), ctx, batchID, usage)
+}
+
+// CountSince mocks base method.
+func (m *MockAPIUsageRepository) CountSince(ctx context.Context, clientID string, from time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSince", ctx, clientID, from)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSince indicates an expected call of CountSince.
+func (mr *MockAPIUsageRepositoryMockRecorder) CountSince(ctx, clientID, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSince", reflect.TypeOf((*MockAPIUsageRepository)(nil).CountSince), ctx, clientID, from)
+}
+
+// FindByClient mocks base method.
+func (m *MockAPIUsageRepository) FindByClient(ctx context.Context, clientID string, from, to time.Time) ([]domain.APIUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByClient", ctx, clientID, from, to)
+	ret0, _ := ret[0].([]domain.APIUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByClient indicates an expected call of FindByClient.
+func (mr *MockAPIUsageRepositoryMockRecorder) FindByClient(ctx, clientID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByClient", reflect.TypeOf((*MockAPIUsageRepository)(nil).FindByClient), ctx, clientID, from, to)
+}
+
+// MockUsageMeter is a mock of UsageMeter interface.
+type MockUsageMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageMeterMockRecorder
+	isgomock struct{}
+}
+
+// MockUsageMeterMockRecorder is the mock recorder for MockUsageMeter.
+type MockUsageMeterMockRecorder struct {
+	mock *MockUsageMeter
+}
+
+// NewMockUsageMeter creates a new mock instance.
+func NewMockUsageMeter(ctrl *gomock.Controller) *MockUsageMeter {
+	mock := &MockUsageMeter{ctrl: ctrl}
+	mock.recorder = &MockUsageMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageMeter) EXPECT() *MockUsageMeterMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockUsageMeter) Consume(ctx context.Context, clientID, endpoint string, at time.Time, quota, seed int64) (bool, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, clientID, endpoint, at, quota, seed)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockUsageMeterMockRecorder) Consume(ctx, clientID, endpoint, at, quota, seed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockUsageMeter)(nil).Consume), ctx, clientID, endpoint, at, quota, seed)
+}
+
+// Drain mocks base method.
+func (m *MockUsageMeter) Drain(ctx context.Context, store func(string, []domain.APIUsage) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx, store)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockUsageMeterMockRecorder) Drain(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockUsageMeter)(nil).Drain), ctx, store)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/api_client_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/api_client_usecase.go -destination=test/mock/api_client_usecase_mock.go -package=mock APIClientUseCase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "xyz-multifinance-api/internal/domain"
+	model "xyz-multifinance-api/internal/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIClientUseCase is a mock of APIClientUseCase interface.
+type MockAPIClientUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIClientUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIClientUseCaseMockRecorder is the mock recorder for MockAPIClientUseCase.
+type MockAPIClientUseCaseMockRecorder struct {
+	mock *MockAPIClientUseCase
+}
+
+// NewMockAPIClientUseCase creates a new mock instance.
+func NewMockAPIClientUseCase(ctrl *gomock.Controller) *MockAPIClientUseCase {
+	mock := &MockAPIClientUseCase{ctrl: ctrl}
+	mock.recorder = &MockAPIClientUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIClientUseCase) EXPECT() *MockAPIClientUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateClient mocks base method.
+func (m *MockAPIClientUseCase) CreateClient(ctx context.Context, req *model.CreateAPIClientRequest) (*model.CreateAPIClientResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, req)
+	ret0, _ := ret[0].(*model.CreateAPIClientResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockAPIClientUseCaseMockRecorder) CreateClient(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockAPIClientUseCase)(nil).CreateClient), ctx, req)
+}
+
+// FlushUsage mocks base method.
+func (m *MockAPIClientUseCase) FlushUsage(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushUsage", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushUsage indicates an expected call of FlushUsage.
+func (mr *MockAPIClientUseCaseMockRecorder) FlushUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushUsage", reflect.TypeOf((*MockAPIClientUseCase)(nil).FlushUsage), ctx)
+}
+
+// GetUsageReport mocks base method.
+func (m *MockAPIClientUseCase) GetUsageReport(ctx context.Context, clientID string, req *model.APIUsageReportRequest) (*model.APIUsageReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsageReport", ctx, clientID, req)
+	ret0, _ := ret[0].(*model.APIUsageReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsageReport indicates an expected call of GetUsageReport.
+func (mr *MockAPIClientUseCaseMockRecorder) GetUsageReport(ctx, clientID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsageReport", reflect.TypeOf((*MockAPIClientUseCase)(nil).GetUsageReport), ctx, clientID, req)
+}
+
+// Meter mocks base method.
+func (m *MockAPIClientUseCase) Meter(ctx context.Context, apiKey, endpoint string) (*domain.APIClient, *model.QuotaStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Meter", ctx, apiKey, endpoint)
+	ret0, _ := ret[0].(*domain.APIClient)
+	ret1, _ := ret[1].(*model.QuotaStatus)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Meter indicates an expected call of Meter.
+func (mr *MockAPIClientUseCaseMockRecorder) Meter(ctx, apiKey, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Meter", reflect.TypeOf((*MockAPIClientUseCase)(nil).Meter), ctx, apiKey, endpoint)
+}
+
+// RunUsageFlusher mocks base method.
+func (m *MockAPIClientUseCase) RunUsageFlusher(stop <-chan struct{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunUsageFlusher", stop)
+}
+
+// RunUsageFlusher indicates an expected call of RunUsageFlusher.
+func (mr *MockAPIClientUseCaseMockRecorder) RunUsageFlusher(stop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunUsageFlusher", reflect.TypeOf((*MockAPIClientUseCase)(nil).RunUsageFlusher), stop)
+}
@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/auth_session.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/auth_session.go -destination=test/mock/auth_session_mock.go -package=mock AuthSessionRepository,TokenDenylist
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthSessionRepository is a mock of AuthSessionRepository interface.
+type MockAuthSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthSessionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthSessionRepositoryMockRecorder is the mock recorder for MockAuthSessionRepository.
+type MockAuthSessionRepositoryMockRecorder struct {
+	mock *MockAuthSessionRepository
+}
+
+// NewMockAuthSessionRepository creates a new mock instance.
+func NewMockAuthSessionRepository(ctrl *gomock.Controller) *MockAuthSessionRepository {
+	mock := &MockAuthSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthSessionRepository) EXPECT() *MockAuthSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuthSessionRepository) Create(ctx context.Context, session *domain.AuthSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuthSessionRepositoryMockRecorder) Create(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthSessionRepository)(nil).Create), ctx, session)
+}
+
+// FindActiveBySubject mocks base method.
+func (m *MockAuthSessionRepository) FindActiveBySubject(ctx context.Context, subjectType, subjectID string, now time.Time) ([]domain.AuthSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveBySubject", ctx, subjectType, subjectID, now)
+	ret0, _ := ret[0].([]domain.AuthSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveBySubject indicates an expected call of FindActiveBySubject.
+func (mr *MockAuthSessionRepositoryMockRecorder) FindActiveBySubject(ctx, subjectType, subjectID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveBySubject", reflect.TypeOf((*MockAuthSessionRepository)(nil).FindActiveBySubject), ctx, subjectType, subjectID, now)
+}
+
+// FindByID mocks base method.
+func (m *MockAuthSessionRepository) FindByID(ctx context.Context, id string) (*domain.AuthSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.AuthSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockAuthSessionRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAuthSessionRepository)(nil).FindByID), ctx, id)
+}
+
+// Revoke mocks base method.
+func (m *MockAuthSessionRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAuthSessionRepositoryMockRecorder) Revoke(ctx, id, revokedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAuthSessionRepository)(nil).Revoke), ctx, id, revokedAt)
+}
+
+// Rotate mocks base method.
+func (m *MockAuthSessionRepository) Rotate(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, id, currentTokenID, nextTokenID, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockAuthSessionRepositoryMockRecorder) Rotate(ctx, id, currentTokenID, nextTokenID, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockAuthSessionRepository)(nil).Rotate), ctx, id, currentTokenID, nextTokenID, expiresAt)
+}
+
+// MockTokenDenylist is a mock of TokenDenylist interface.
+type MockTokenDenylist struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenDenylistMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenDenylistMockRecorder is the mock recorder for MockTokenDenylist.
+type MockTokenDenylistMockRecorder struct {
+	mock *MockTokenDenylist
+}
+
+// NewMockTokenDenylist creates a new mock instance.
+func NewMockTokenDenylist(ctrl *gomock.Controller) *MockTokenDenylist {
+	mock := &MockTokenDenylist{ctrl: ctrl}
+	mock.recorder = &MockTokenDenylistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenDenylist) EXPECT() *MockTokenDenylistMockRecorder {
+	return m.recorder
+}
+
+// Deny mocks base method.
+func (m *MockTokenDenylist) Deny(ctx context.Context, sessionID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deny", ctx, sessionID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deny indicates an expected call of Deny.
+func (mr *MockTokenDenylistMockRecorder) Deny(ctx, sessionID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deny", reflect.TypeOf((*MockTokenDenylist)(nil).Deny), ctx, sessionID, ttl)
+}
+
+// IsDenied mocks base method.
+func (m *MockTokenDenylist) IsDenied(ctx context.Context, sessionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDenied", ctx, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDenied indicates an expected call of IsDenied.
+func (mr *MockTokenDenylistMockRecorder) IsDenied(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDenied", reflect.TypeOf((*MockTokenDenylist)(nil).IsDenied), ctx, sessionID)
+}
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "xyz-multifinance-api/internal/model"
 
@@ -40,47 +41,162 @@ func (m *MockAuthUseCase) EXPECT() *MockAuthUseCaseMockRecorder {
 	return m.recorder
 }
 
+// ForgotPassword mocks base method.
+func (m *MockAuthUseCase) ForgotPassword(ctx context.Context, req *model.ForgotPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthUseCaseMockRecorder) ForgotPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthUseCase)(nil).ForgotPassword), ctx, req)
+}
+
+// ListCustomerSessions mocks base method.
+func (m *MockAuthUseCase) ListCustomerSessions(ctx context.Context, customerID string) ([]model.SessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerSessions", ctx, customerID)
+	ret0, _ := ret[0].([]model.SessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerSessions indicates an expected call of ListCustomerSessions.
+func (mr *MockAuthUseCaseMockRecorder) ListCustomerSessions(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerSessions", reflect.TypeOf((*MockAuthUseCase)(nil).ListCustomerSessions), ctx, customerID)
+}
+
 // Login mocks base method.
-func (m *MockAuthUseCase) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
+func (m *MockAuthUseCase) Login(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", req)
+	ret := m.ctrl.Call(m, "Login", ctx, req)
 	ret0, _ := ret[0].(*model.LoginResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthUseCaseMockRecorder) Login(req any) *gomock.Call {
+func (mr *MockAuthUseCaseMockRecorder) Login(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCase)(nil).Login), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCase)(nil).Login), ctx, req)
+}
+
+// Logout mocks base method.
+func (m *MockAuthUseCase) Logout(ctx context.Context, req *model.LogoutRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUseCaseMockRecorder) Logout(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUseCase)(nil).Logout), ctx, req)
 }
 
 // RefreshToken mocks base method.
-func (m *MockAuthUseCase) RefreshToken(req *model.RefreshTokenRequest) (*model.LoginResponse, error) {
+func (m *MockAuthUseCase) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.LoginResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshToken", req)
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, req)
 	ret0, _ := ret[0].(*model.LoginResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RefreshToken indicates an expected call of RefreshToken.
-func (mr *MockAuthUseCaseMockRecorder) RefreshToken(req any) *gomock.Call {
+func (mr *MockAuthUseCaseMockRecorder) RefreshToken(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUseCase)(nil).RefreshToken), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUseCase)(nil).RefreshToken), ctx, req)
 }
 
 // Register mocks base method.
-func (m *MockAuthUseCase) Register(req *model.RegisterCustomerRequest) (*model.CustomerResponse, error) {
+func (m *MockAuthUseCase) Register(ctx context.Context, req *model.RegisterCustomerRequest) (*model.CustomerResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", req)
+	ret := m.ctrl.Call(m, "Register", ctx, req)
 	ret0, _ := ret[0].(*model.CustomerResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockAuthUseCaseMockRecorder) Register(req any) *gomock.Call {
+func (mr *MockAuthUseCaseMockRecorder) Register(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCase)(nil).Register), ctx, req)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthUseCase) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthUseCaseMockRecorder) ResetPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthUseCase)(nil).ResetPassword), ctx, req)
+}
+
+// RevokeCustomerSession mocks base method.
+func (m *MockAuthUseCase) RevokeCustomerSession(ctx context.Context, customerID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCustomerSession", ctx, customerID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeCustomerSession indicates an expected call of RevokeCustomerSession.
+func (mr *MockAuthUseCaseMockRecorder) RevokeCustomerSession(ctx, customerID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCustomerSession", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeCustomerSession), ctx, customerID, sessionID)
+}
+
+// StaffLogin mocks base method.
+func (m *MockAuthUseCase) StaffLogin(ctx context.Context, req *model.StaffLoginRequest) (*model.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StaffLogin", ctx, req)
+	ret0, _ := ret[0].(*model.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StaffLogin indicates an expected call of StaffLogin.
+func (mr *MockAuthUseCaseMockRecorder) StaffLogin(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StaffLogin", reflect.TypeOf((*MockAuthUseCase)(nil).StaffLogin), ctx, req)
+}
+
+// UnlockCustomerLogin mocks base method.
+func (m *MockAuthUseCase) UnlockCustomerLogin(ctx context.Context, customerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockCustomerLogin", ctx, customerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockCustomerLogin indicates an expected call of UnlockCustomerLogin.
+func (mr *MockAuthUseCaseMockRecorder) UnlockCustomerLogin(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockCustomerLogin", reflect.TypeOf((*MockAuthUseCase)(nil).UnlockCustomerLogin), ctx, customerID)
+}
+
+// VerifyStaffMFA mocks base method.
+func (m *MockAuthUseCase) VerifyStaffMFA(ctx context.Context, req *model.StaffMFAVerifyRequest) (*model.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyStaffMFA", ctx, req)
+	ret0, _ := ret[0].(*model.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyStaffMFA indicates an expected call of VerifyStaffMFA.
+func (mr *MockAuthUseCaseMockRecorder) VerifyStaffMFA(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCase)(nil).Register), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyStaffMFA", reflect.TypeOf((*MockAuthUseCase)(nil).VerifyStaffMFA), ctx, req)
 }
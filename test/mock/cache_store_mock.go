@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -55,44 +56,147 @@ func (mr *MockCacheStoreMockRecorder) Close() *gomock.Call {
 }
 
 // Del mocks base method.
-func (m *MockCacheStore) Del(key string) error {
+func (m *MockCacheStore) Del(ctx context.Context, keys ...string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Del", key)
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Del indicates an expected call of Del.
-func (mr *MockCacheStoreMockRecorder) Del(key any) *gomock.Call {
+func (mr *MockCacheStoreMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockCacheStore)(nil).Del), key)
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockCacheStore)(nil).Del), varargs...)
 }
 
 // Get mocks base method.
-func (m *MockCacheStore) Get(key string) (string, error) {
+func (m *MockCacheStore) Get(ctx context.Context, key string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", key)
+	ret := m.ctrl.Call(m, "Get", ctx, key)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCacheStoreMockRecorder) Get(key any) *gomock.Call {
+func (mr *MockCacheStoreMockRecorder) Get(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheStore)(nil).Get), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheStore)(nil).Get), ctx, key)
+}
+
+// Incr mocks base method.
+func (m *MockCacheStore) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockCacheStoreMockRecorder) Incr(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockCacheStore)(nil).Incr), ctx, key)
+}
+
+// InvalidateTag mocks base method.
+func (m *MockCacheStore) InvalidateTag(ctx context.Context, tag string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateTag", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateTag indicates an expected call of InvalidateTag.
+func (mr *MockCacheStoreMockRecorder) InvalidateTag(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateTag", reflect.TypeOf((*MockCacheStore)(nil).InvalidateTag), ctx, tag)
+}
+
+// MGet mocks base method.
+func (m *MockCacheStore) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockCacheStoreMockRecorder) MGet(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockCacheStore)(nil).MGet), varargs...)
 }
 
 // Set mocks base method.
-func (m *MockCacheStore) Set(key string, value any, expiration time.Duration) error {
+func (m *MockCacheStore) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", key, value, expiration)
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockCacheStoreMockRecorder) Set(key, value, expiration any) *gomock.Call {
+func (mr *MockCacheStoreMockRecorder) Set(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheStore)(nil).Set), ctx, key, value, expiration)
+}
+
+// SetNX mocks base method.
+func (m *MockCacheStore) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockCacheStoreMockRecorder) SetNX(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockCacheStore)(nil).SetNX), ctx, key, value, expiration)
+}
+
+// SetWithTags mocks base method.
+func (m *MockCacheStore) SetWithTags(ctx context.Context, key string, value any, expiration time.Duration, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, value, expiration}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetWithTags", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithTags indicates an expected call of SetWithTags.
+func (mr *MockCacheStoreMockRecorder) SetWithTags(ctx, key, value, expiration any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, value, expiration}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTags", reflect.TypeOf((*MockCacheStore)(nil).SetWithTags), varargs...)
+}
+
+// TTL mocks base method.
+func (m *MockCacheStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockCacheStoreMockRecorder) TTL(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheStore)(nil).Set), key, value, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockCacheStore)(nil).TTL), ctx, key)
 }
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	domain "xyz-multifinance-api/internal/domain"
 
@@ -41,59 +42,74 @@ func (m *MockCreditLimitRepository) EXPECT() *MockCreditLimitRepositoryMockRecor
 }
 
 // CreateCreditLimit mocks base method.
-func (m *MockCreditLimitRepository) CreateCreditLimit(creditLimit *domain.CreditLimit) error {
+func (m *MockCreditLimitRepository) CreateCreditLimit(ctx context.Context, creditLimit *domain.CreditLimit) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCreditLimit", creditLimit)
+	ret := m.ctrl.Call(m, "CreateCreditLimit", ctx, creditLimit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateCreditLimit indicates an expected call of CreateCreditLimit.
-func (mr *MockCreditLimitRepositoryMockRecorder) CreateCreditLimit(creditLimit any) *gomock.Call {
+func (mr *MockCreditLimitRepositoryMockRecorder) CreateCreditLimit(ctx, creditLimit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLimit", reflect.TypeOf((*MockCreditLimitRepository)(nil).CreateCreditLimit), creditLimit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLimit", reflect.TypeOf((*MockCreditLimitRepository)(nil).CreateCreditLimit), ctx, creditLimit)
 }
 
 // GetCreditLimitByCustomerAndTenor mocks base method.
-func (m *MockCreditLimitRepository) GetCreditLimitByCustomerAndTenor(customerID string, tenorMonths int) (*domain.CreditLimit, error) {
+func (m *MockCreditLimitRepository) GetCreditLimitByCustomerAndTenor(ctx context.Context, customerID string, tenorMonths int) (*domain.CreditLimit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCreditLimitByCustomerAndTenor", customerID, tenorMonths)
+	ret := m.ctrl.Call(m, "GetCreditLimitByCustomerAndTenor", ctx, customerID, tenorMonths)
 	ret0, _ := ret[0].(*domain.CreditLimit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCreditLimitByCustomerAndTenor indicates an expected call of GetCreditLimitByCustomerAndTenor.
-func (mr *MockCreditLimitRepositoryMockRecorder) GetCreditLimitByCustomerAndTenor(customerID, tenorMonths any) *gomock.Call {
+func (mr *MockCreditLimitRepositoryMockRecorder) GetCreditLimitByCustomerAndTenor(ctx, customerID, tenorMonths any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLimitByCustomerAndTenor", reflect.TypeOf((*MockCreditLimitRepository)(nil).GetCreditLimitByCustomerAndTenor), customerID, tenorMonths)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLimitByCustomerAndTenor", reflect.TypeOf((*MockCreditLimitRepository)(nil).GetCreditLimitByCustomerAndTenor), ctx, customerID, tenorMonths)
+}
+
+// GetCreditLimitForUpdate mocks base method.
+func (m *MockCreditLimitRepository) GetCreditLimitForUpdate(ctx context.Context, customerID string, tenorMonths int) (*domain.CreditLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditLimitForUpdate", ctx, customerID, tenorMonths)
+	ret0, _ := ret[0].(*domain.CreditLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditLimitForUpdate indicates an expected call of GetCreditLimitForUpdate.
+func (mr *MockCreditLimitRepositoryMockRecorder) GetCreditLimitForUpdate(ctx, customerID, tenorMonths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLimitForUpdate", reflect.TypeOf((*MockCreditLimitRepository)(nil).GetCreditLimitForUpdate), ctx, customerID, tenorMonths)
 }
 
 // GetCreditLimitsByCustomerID mocks base method.
-func (m *MockCreditLimitRepository) GetCreditLimitsByCustomerID(customerID string) ([]domain.CreditLimit, error) {
+func (m *MockCreditLimitRepository) GetCreditLimitsByCustomerID(ctx context.Context, customerID string) ([]domain.CreditLimit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCreditLimitsByCustomerID", customerID)
+	ret := m.ctrl.Call(m, "GetCreditLimitsByCustomerID", ctx, customerID)
 	ret0, _ := ret[0].([]domain.CreditLimit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCreditLimitsByCustomerID indicates an expected call of GetCreditLimitsByCustomerID.
-func (mr *MockCreditLimitRepositoryMockRecorder) GetCreditLimitsByCustomerID(customerID any) *gomock.Call {
+func (mr *MockCreditLimitRepositoryMockRecorder) GetCreditLimitsByCustomerID(ctx, customerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLimitsByCustomerID", reflect.TypeOf((*MockCreditLimitRepository)(nil).GetCreditLimitsByCustomerID), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLimitsByCustomerID", reflect.TypeOf((*MockCreditLimitRepository)(nil).GetCreditLimitsByCustomerID), ctx, customerID)
 }
 
 // UpdateCreditLimit mocks base method.
-func (m *MockCreditLimitRepository) UpdateCreditLimit(creditLimit *domain.CreditLimit) error {
+func (m *MockCreditLimitRepository) UpdateCreditLimit(ctx context.Context, creditLimit *domain.CreditLimit) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCreditLimit", creditLimit)
+	ret := m.ctrl.Call(m, "UpdateCreditLimit", ctx, creditLimit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateCreditLimit indicates an expected call of UpdateCreditLimit.
-func (mr *MockCreditLimitRepositoryMockRecorder) UpdateCreditLimit(creditLimit any) *gomock.Call {
+func (mr *MockCreditLimitRepositoryMockRecorder) UpdateCreditLimit(ctx, creditLimit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLimit", reflect.TypeOf((*MockCreditLimitRepository)(nil).UpdateCreditLimit), creditLimit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLimit", reflect.TypeOf((*MockCreditLimitRepository)(nil).UpdateCreditLimit), ctx, creditLimit)
 }
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "xyz-multifinance-api/internal/model"
 
@@ -41,46 +42,46 @@ func (m *MockCreditLimitUseCase) EXPECT() *MockCreditLimitUseCaseMockRecorder {
 }
 
 // GetCustomerCreditLimitByTenor mocks base method.
-func (m *MockCreditLimitUseCase) GetCustomerCreditLimitByTenor(customerID string, tenorMonths int) (*model.CreditLimitResponse, error) {
+func (m *MockCreditLimitUseCase) GetCustomerCreditLimitByTenor(ctx context.Context, customerID string, tenorMonths int) (*model.CreditLimitResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerCreditLimitByTenor", customerID, tenorMonths)
+	ret := m.ctrl.Call(m, "GetCustomerCreditLimitByTenor", ctx, customerID, tenorMonths)
 	ret0, _ := ret[0].(*model.CreditLimitResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerCreditLimitByTenor indicates an expected call of GetCustomerCreditLimitByTenor.
-func (mr *MockCreditLimitUseCaseMockRecorder) GetCustomerCreditLimitByTenor(customerID, tenorMonths any) *gomock.Call {
+func (mr *MockCreditLimitUseCaseMockRecorder) GetCustomerCreditLimitByTenor(ctx, customerID, tenorMonths any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerCreditLimitByTenor", reflect.TypeOf((*MockCreditLimitUseCase)(nil).GetCustomerCreditLimitByTenor), customerID, tenorMonths)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerCreditLimitByTenor", reflect.TypeOf((*MockCreditLimitUseCase)(nil).GetCustomerCreditLimitByTenor), ctx, customerID, tenorMonths)
 }
 
 // GetCustomerCreditLimits mocks base method.
-func (m *MockCreditLimitUseCase) GetCustomerCreditLimits(customerID string) ([]model.CreditLimitResponse, error) {
+func (m *MockCreditLimitUseCase) GetCustomerCreditLimits(ctx context.Context, customerID string) ([]model.CreditLimitResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerCreditLimits", customerID)
+	ret := m.ctrl.Call(m, "GetCustomerCreditLimits", ctx, customerID)
 	ret0, _ := ret[0].([]model.CreditLimitResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerCreditLimits indicates an expected call of GetCustomerCreditLimits.
-func (mr *MockCreditLimitUseCaseMockRecorder) GetCustomerCreditLimits(customerID any) *gomock.Call {
+func (mr *MockCreditLimitUseCaseMockRecorder) GetCustomerCreditLimits(ctx, customerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerCreditLimits", reflect.TypeOf((*MockCreditLimitUseCase)(nil).GetCustomerCreditLimits), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerCreditLimits", reflect.TypeOf((*MockCreditLimitUseCase)(nil).GetCustomerCreditLimits), ctx, customerID)
 }
 
 // SetCustomerCreditLimit mocks base method.
-func (m *MockCreditLimitUseCase) SetCustomerCreditLimit(req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error) {
+func (m *MockCreditLimitUseCase) SetCustomerCreditLimit(ctx context.Context, req *model.SetCreditLimitRequest) (*model.CreditLimitResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetCustomerCreditLimit", req)
+	ret := m.ctrl.Call(m, "SetCustomerCreditLimit", ctx, req)
 	ret0, _ := ret[0].(*model.CreditLimitResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SetCustomerCreditLimit indicates an expected call of SetCustomerCreditLimit.
-func (mr *MockCreditLimitUseCaseMockRecorder) SetCustomerCreditLimit(req any) *gomock.Call {
+func (mr *MockCreditLimitUseCaseMockRecorder) SetCustomerCreditLimit(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomerCreditLimit", reflect.TypeOf((*MockCreditLimitUseCase)(nil).SetCustomerCreditLimit), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomerCreditLimit", reflect.TypeOf((*MockCreditLimitUseCase)(nil).SetCustomerCreditLimit), ctx, req)
 }
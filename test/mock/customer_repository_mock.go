@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	domain "xyz-multifinance-api/internal/domain"
 
@@ -41,45 +42,118 @@ func (m *MockCustomerRepository) EXPECT() *MockCustomerRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockCustomerRepository) Create(customer *domain.Customer) error {
+func (m *MockCustomerRepository) Create(ctx context.Context, customer *domain.Customer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", customer)
+	ret := m.ctrl.Call(m, "Create", ctx, customer)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCustomerRepositoryMockRecorder) Create(customer any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Create(ctx, customer any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepository)(nil).Create), customer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepository)(nil).Create), ctx, customer)
 }
 
 // FindByID mocks base method.
-func (m *MockCustomerRepository) FindByID(id string) (*domain.Customer, error) {
+func (m *MockCustomerRepository) FindByID(ctx context.Context, id string) (*domain.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
 	ret0, _ := ret[0].(*domain.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockCustomerRepositoryMockRecorder) FindByID(id any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCustomerRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCustomerRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByIDForUpdate mocks base method.
+func (m *MockCustomerRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDForUpdate", ctx, id)
+	ret0, _ := ret[0].(*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDForUpdate indicates an expected call of FindByIDForUpdate.
+func (mr *MockCustomerRepositoryMockRecorder) FindByIDForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDForUpdate", reflect.TypeOf((*MockCustomerRepository)(nil).FindByIDForUpdate), ctx, id)
 }
 
 // FindByNIK mocks base method.
-func (m *MockCustomerRepository) FindByNIK(nik string) (*domain.Customer, error) {
+func (m *MockCustomerRepository) FindByNIK(ctx context.Context, nik string) (*domain.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByNIK", nik)
+	ret := m.ctrl.Call(m, "FindByNIK", ctx, nik)
 	ret0, _ := ret[0].(*domain.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByNIK indicates an expected call of FindByNIK.
-func (mr *MockCustomerRepositoryMockRecorder) FindByNIK(nik any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) FindByNIK(ctx, nik any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNIK", reflect.TypeOf((*MockCustomerRepository)(nil).FindByNIK), ctx, nik)
+}
+
+// FindCredentialsByNIK mocks base method.
+func (m *MockCustomerRepository) FindCredentialsByNIK(ctx context.Context, nik string) (*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCredentialsByNIK", ctx, nik)
+	ret0, _ := ret[0].(*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCredentialsByNIK indicates an expected call of FindCredentialsByNIK.
+func (mr *MockCustomerRepositoryMockRecorder) FindCredentialsByNIK(ctx, nik any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCredentialsByNIK", reflect.TypeOf((*MockCustomerRepository)(nil).FindCredentialsByNIK), ctx, nik)
+}
+
+// Search mocks base method.
+func (m *MockCustomerRepository) Search(ctx context.Context, filter domain.CustomerFilter) ([]domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter)
+	ret0, _ := ret[0].([]domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockCustomerRepositoryMockRecorder) Search(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockCustomerRepository)(nil).Search), ctx, filter)
+}
+
+// Update mocks base method.
+func (m *MockCustomerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, customer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, customer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), ctx, customer)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockCustomerRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockCustomerRepositoryMockRecorder) UpdatePassword(ctx, id, passwordHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNIK", reflect.TypeOf((*MockCustomerRepository)(nil).FindByNIK), nik)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockCustomerRepository)(nil).UpdatePassword), ctx, id, passwordHash)
 }
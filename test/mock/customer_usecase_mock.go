@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "xyz-multifinance-api/internal/model"
 
@@ -41,31 +42,75 @@ func (m *MockCustomerUseCase) EXPECT() *MockCustomerUseCaseMockRecorder {
 }
 
 // GetCustomerProfileByID mocks base method.
-func (m *MockCustomerUseCase) GetCustomerProfileByID(id string) (*model.CustomerResponse, error) {
+func (m *MockCustomerUseCase) GetCustomerProfileByID(ctx context.Context, id string) (*model.CustomerResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerProfileByID", id)
+	ret := m.ctrl.Call(m, "GetCustomerProfileByID", ctx, id)
 	ret0, _ := ret[0].(*model.CustomerResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerProfileByID indicates an expected call of GetCustomerProfileByID.
-func (mr *MockCustomerUseCaseMockRecorder) GetCustomerProfileByID(id any) *gomock.Call {
+func (mr *MockCustomerUseCaseMockRecorder) GetCustomerProfileByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfileByID", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomerProfileByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfileByID", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomerProfileByID), ctx, id)
 }
 
 // GetCustomerProfileByNIK mocks base method.
-func (m *MockCustomerUseCase) GetCustomerProfileByNIK(nik string) (*model.CustomerResponse, error) {
+func (m *MockCustomerUseCase) GetCustomerProfileByNIK(ctx context.Context, nik string) (*model.CustomerResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerProfileByNIK", nik)
+	ret := m.ctrl.Call(m, "GetCustomerProfileByNIK", ctx, nik)
 	ret0, _ := ret[0].(*model.CustomerResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerProfileByNIK indicates an expected call of GetCustomerProfileByNIK.
-func (mr *MockCustomerUseCaseMockRecorder) GetCustomerProfileByNIK(nik any) *gomock.Call {
+func (mr *MockCustomerUseCaseMockRecorder) GetCustomerProfileByNIK(ctx, nik any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfileByNIK", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomerProfileByNIK), nik)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfileByNIK", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomerProfileByNIK), ctx, nik)
+}
+
+// RequestPhoneVerification mocks base method.
+func (m *MockCustomerUseCase) RequestPhoneVerification(ctx context.Context, customerID string, req *model.RequestPhoneVerificationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPhoneVerification", ctx, customerID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPhoneVerification indicates an expected call of RequestPhoneVerification.
+func (mr *MockCustomerUseCaseMockRecorder) RequestPhoneVerification(ctx, customerID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPhoneVerification", reflect.TypeOf((*MockCustomerUseCase)(nil).RequestPhoneVerification), ctx, customerID, req)
+}
+
+// SearchCustomers mocks base method.
+func (m *MockCustomerUseCase) SearchCustomers(ctx context.Context, req *model.SearchCustomersRequest) (*model.CustomerListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCustomers", ctx, req)
+	ret0, _ := ret[0].(*model.CustomerListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCustomers indicates an expected call of SearchCustomers.
+func (mr *MockCustomerUseCaseMockRecorder) SearchCustomers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCustomers", reflect.TypeOf((*MockCustomerUseCase)(nil).SearchCustomers), ctx, req)
+}
+
+// VerifyPhone mocks base method.
+func (m *MockCustomerUseCase) VerifyPhone(ctx context.Context, customerID string, req *model.VerifyPhoneRequest) (*model.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPhone", ctx, customerID, req)
+	ret0, _ := ret[0].(*model.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPhone indicates an expected call of VerifyPhone.
+func (mr *MockCustomerUseCaseMockRecorder) VerifyPhone(ctx, customerID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPhone", reflect.TypeOf((*MockCustomerUseCase)(nil).VerifyPhone), ctx, customerID, req)
 }
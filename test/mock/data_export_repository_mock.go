@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/data_export.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/data_export.go -destination=test/mock/data_export_repository_mock.go -package=mock DataExportRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportRepository is a mock of DataExportRepository interface.
+type MockDataExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportRepositoryMockRecorder is the mock recorder for MockDataExportRepository.
+type MockDataExportRepositoryMockRecorder struct {
+	mock *MockDataExportRepository
+}
+
+// NewMockDataExportRepository creates a new mock instance.
+func NewMockDataExportRepository(ctrl *gomock.Controller) *MockDataExportRepository {
+	mock := &MockDataExportRepository{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepository) EXPECT() *MockDataExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDataExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportRepositoryMockRecorder) Create(ctx, export any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportRepository)(nil).Create), ctx, export)
+}
+
+// FindByID mocks base method.
+func (m *MockDataExportRepository) FindByID(ctx context.Context, id string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockDataExportRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDataExportRepository)(nil).FindByID), ctx, id)
+}
+
+// FindExpired mocks base method.
+func (m *MockDataExportRepository) FindExpired(ctx context.Context, expiredBefore time.Time, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, expiredBefore, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired.
+func (mr *MockDataExportRepositoryMockRecorder) FindExpired(ctx, expiredBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockDataExportRepository)(nil).FindExpired), ctx, expiredBefore, limit)
+}
+
+// FindUnfinished mocks base method.
+func (m *MockDataExportRepository) FindUnfinished(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnfinished", ctx, updatedBefore, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnfinished indicates an expected call of FindUnfinished.
+func (mr *MockDataExportRepositoryMockRecorder) FindUnfinished(ctx, updatedBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnfinished", reflect.TypeOf((*MockDataExportRepository)(nil).FindUnfinished), ctx, updatedBefore, limit)
+}
+
+// Update mocks base method.
+func (m *MockDataExportRepository) Update(ctx context.Context, export *domain.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDataExportRepositoryMockRecorder) Update(ctx, export any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDataExportRepository)(nil).Update), ctx, export)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/data_export_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/data_export_usecase.go -destination=test/mock/data_export_usecase_mock.go -package=mock DataExportUseCase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	model "xyz-multifinance-api/internal/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDataExportUseCase is a mock of DataExportUseCase interface.
+type MockDataExportUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockDataExportUseCaseMockRecorder is the mock recorder for MockDataExportUseCase.
+type MockDataExportUseCaseMockRecorder struct {
+	mock *MockDataExportUseCase
+}
+
+// NewMockDataExportUseCase creates a new mock instance.
+func NewMockDataExportUseCase(ctrl *gomock.Controller) *MockDataExportUseCase {
+	mock := &MockDataExportUseCase{ctrl: ctrl}
+	mock.recorder = &MockDataExportUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportUseCase) EXPECT() *MockDataExportUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetExport mocks base method.
+func (m *MockDataExportUseCase) GetExport(ctx context.Context, customerID, exportID string) (*model.DataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, customerID, exportID)
+	ret0, _ := ret[0].(*model.DataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockDataExportUseCaseMockRecorder) GetExport(ctx, customerID, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockDataExportUseCase)(nil).GetExport), ctx, customerID, exportID)
+}
+
+// GetExportArchive mocks base method.
+func (m *MockDataExportUseCase) GetExportArchive(ctx context.Context, customerID, exportID string, req *model.DataExportDownloadRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportArchive", ctx, customerID, exportID, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportArchive indicates an expected call of GetExportArchive.
+func (mr *MockDataExportUseCaseMockRecorder) GetExportArchive(ctx, customerID, exportID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportArchive", reflect.TypeOf((*MockDataExportUseCase)(nil).GetExportArchive), ctx, customerID, exportID, req)
+}
+
+// ProcessExport mocks base method.
+func (m *MockDataExportUseCase) ProcessExport(ctx context.Context, exportID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessExport", ctx, exportID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessExport indicates an expected call of ProcessExport.
+func (mr *MockDataExportUseCaseMockRecorder) ProcessExport(ctx, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessExport", reflect.TypeOf((*MockDataExportUseCase)(nil).ProcessExport), ctx, exportID)
+}
+
+// RequestExport mocks base method.
+func (m *MockDataExportUseCase) RequestExport(ctx context.Context, customerID string) (*model.DataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx, customerID)
+	ret0, _ := ret[0].(*model.DataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockDataExportUseCaseMockRecorder) RequestExport(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockDataExportUseCase)(nil).RequestExport), ctx, customerID)
+}
+
+// RunWorker mocks base method.
+func (m *MockDataExportUseCase) RunWorker(stop <-chan struct{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunWorker", stop)
+}
+
+// RunWorker indicates an expected call of RunWorker.
+func (mr *MockDataExportUseCaseMockRecorder) RunWorker(stop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWorker", reflect.TypeOf((*MockDataExportUseCase)(nil).RunWorker), stop)
+}
+
+// SweepExports mocks base method.
+func (m *MockDataExportUseCase) SweepExports(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SweepExports", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SweepExports indicates an expected call of SweepExports.
+func (mr *MockDataExportUseCaseMockRecorder) SweepExports(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SweepExports", reflect.TypeOf((*MockDataExportUseCase)(nil).SweepExports), ctx)
+}
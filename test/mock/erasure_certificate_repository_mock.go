@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/erasure.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/erasure.go -destination=test/mock/erasure_certificate_repository_mock.go -package=mock ErasureCertificateRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockErasureCertificateRepository is a mock of ErasureCertificateRepository interface.
+type MockErasureCertificateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockErasureCertificateRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockErasureCertificateRepositoryMockRecorder is the mock recorder for MockErasureCertificateRepository.
+type MockErasureCertificateRepositoryMockRecorder struct {
+	mock *MockErasureCertificateRepository
+}
+
+// NewMockErasureCertificateRepository creates a new mock instance.
+func NewMockErasureCertificateRepository(ctrl *gomock.Controller) *MockErasureCertificateRepository {
+	mock := &MockErasureCertificateRepository{ctrl: ctrl}
+	mock.recorder = &MockErasureCertificateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockErasureCertificateRepository) EXPECT() *MockErasureCertificateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockErasureCertificateRepository) Create(ctx context.Context, certificate *domain.ErasureCertificate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, certificate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockErasureCertificateRepositoryMockRecorder) Create(ctx, certificate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockErasureCertificateRepository)(nil).Create), ctx, certificate)
+}
+
+// FindByCustomerID mocks base method.
+func (m *MockErasureCertificateRepository) FindByCustomerID(ctx context.Context, customerID string) (*domain.ErasureCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", ctx, customerID)
+	ret0, _ := ret[0].(*domain.ErasureCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID.
+func (mr *MockErasureCertificateRepositoryMockRecorder) FindByCustomerID(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockErasureCertificateRepository)(nil).FindByCustomerID), ctx, customerID)
+}
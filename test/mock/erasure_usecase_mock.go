@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/erasure_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/erasure_usecase.go -destination=test/mock/erasure_usecase_mock.go -package=mock ErasureUseCase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	model "xyz-multifinance-api/internal/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockErasureUseCase is a mock of ErasureUseCase interface.
+type MockErasureUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockErasureUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockErasureUseCaseMockRecorder is the mock recorder for MockErasureUseCase.
+type MockErasureUseCaseMockRecorder struct {
+	mock *MockErasureUseCase
+}
+
+// NewMockErasureUseCase creates a new mock instance.
+func NewMockErasureUseCase(ctrl *gomock.Controller) *MockErasureUseCase {
+	mock := &MockErasureUseCase{ctrl: ctrl}
+	mock.recorder = &MockErasureUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockErasureUseCase) EXPECT() *MockErasureUseCaseMockRecorder {
+	return m.recorder
+}
+
+// EraseCustomerData mocks base method.
+func (m *MockErasureUseCase) EraseCustomerData(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseCustomerData", ctx, customerID)
+	ret0, _ := ret[0].(*model.ErasureCertificateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EraseCustomerData indicates an expected call of EraseCustomerData.
+func (mr *MockErasureUseCaseMockRecorder) EraseCustomerData(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseCustomerData", reflect.TypeOf((*MockErasureUseCase)(nil).EraseCustomerData), ctx, customerID)
+}
+
+// GetErasureCertificate mocks base method.
+func (m *MockErasureUseCase) GetErasureCertificate(ctx context.Context, customerID string) (*model.ErasureCertificateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetErasureCertificate", ctx, customerID)
+	ret0, _ := ret[0].(*model.ErasureCertificateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetErasureCertificate indicates an expected call of GetErasureCertificate.
+func (mr *MockErasureUseCaseMockRecorder) GetErasureCertificate(ctx, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetErasureCertificate", reflect.TypeOf((*MockErasureUseCase)(nil).GetErasureCertificate), ctx, customerID)
+}
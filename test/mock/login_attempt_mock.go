@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/login_attempt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/login_attempt.go -destination=test/mock/login_attempt_mock.go -package=mock LoginAttemptTracker,CustomerNotifier
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttemptTracker is a mock of LoginAttemptTracker interface.
+type MockLoginAttemptTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptTrackerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptTrackerMockRecorder is the mock recorder for MockLoginAttemptTracker.
+type MockLoginAttemptTrackerMockRecorder struct {
+	mock *MockLoginAttemptTracker
+}
+
+// NewMockLoginAttemptTracker creates a new mock instance.
+func NewMockLoginAttemptTracker(ctrl *gomock.Controller) *MockLoginAttemptTracker {
+	mock := &MockLoginAttemptTracker{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptTracker) EXPECT() *MockLoginAttemptTrackerMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttemptTracker) Lock(ctx context.Context, key string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, key, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptTrackerMockRecorder) Lock(ctx, key, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttemptTracker)(nil).Lock), ctx, key, duration)
+}
+
+// LockedFor mocks base method.
+func (m *MockLoginAttemptTracker) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedFor", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedFor indicates an expected call of LockedFor.
+func (mr *MockLoginAttemptTrackerMockRecorder) LockedFor(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedFor", reflect.TypeOf((*MockLoginAttemptTracker)(nil).LockedFor), ctx, key)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginAttemptTracker) RecordFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginAttemptTrackerMockRecorder) RecordFailure(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginAttemptTracker)(nil).RecordFailure), ctx, key, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptTracker) Reset(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptTrackerMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptTracker)(nil).Reset), ctx, key)
+}
+
+// MockCustomerNotifier is a mock of CustomerNotifier interface.
+type MockCustomerNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerNotifierMockRecorder is the mock recorder for MockCustomerNotifier.
+type MockCustomerNotifierMockRecorder struct {
+	mock *MockCustomerNotifier
+}
+
+// NewMockCustomerNotifier creates a new mock instance.
+func NewMockCustomerNotifier(ctrl *gomock.Controller) *MockCustomerNotifier {
+	mock := &MockCustomerNotifier{ctrl: ctrl}
+	mock.recorder = &MockCustomerNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerNotifier) EXPECT() *MockCustomerNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyLoginLocked mocks base method.
+func (m *MockCustomerNotifier) NotifyLoginLocked(customerID string, lockedUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyLoginLocked", customerID, lockedUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyLoginLocked indicates an expected call of NotifyLoginLocked.
+func (mr *MockCustomerNotifierMockRecorder) NotifyLoginLocked(customerID, lockedUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyLoginLocked", reflect.TypeOf((*MockCustomerNotifier)(nil).NotifyLoginLocked), customerID, lockedUntil)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/otp.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/otp.go -destination=test/mock/otp_mock.go -package=mock OTPStore,OTPSender
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOTPStore is a mock of OTPStore interface.
+type MockOTPStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOTPStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOTPStoreMockRecorder is the mock recorder for MockOTPStore.
+type MockOTPStoreMockRecorder struct {
+	mock *MockOTPStore
+}
+
+// NewMockOTPStore creates a new mock instance.
+func NewMockOTPStore(ctrl *gomock.Controller) *MockOTPStore {
+	mock := &MockOTPStore{ctrl: ctrl}
+	mock.recorder = &MockOTPStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOTPStore) EXPECT() *MockOTPStoreMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockOTPStore) Consume(ctx context.Context, purpose, subject, codeHash string, maxAttempts int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, purpose, subject, codeHash, maxAttempts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockOTPStoreMockRecorder) Consume(ctx, purpose, subject, codeHash, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockOTPStore)(nil).Consume), ctx, purpose, subject, codeHash, maxAttempts)
+}
+
+// Delete mocks base method.
+func (m *MockOTPStore) Delete(ctx context.Context, purpose, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, purpose, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOTPStoreMockRecorder) Delete(ctx, purpose, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOTPStore)(nil).Delete), ctx, purpose, subject)
+}
+
+// Save mocks base method.
+func (m *MockOTPStore) Save(ctx context.Context, purpose, subject string, challenge *domain.OTPChallenge, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, purpose, subject, challenge, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockOTPStoreMockRecorder) Save(ctx, purpose, subject, challenge, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOTPStore)(nil).Save), ctx, purpose, subject, challenge, ttl)
+}
+
+// StartCooldown mocks base method.
+func (m *MockOTPStore) StartCooldown(ctx context.Context, purpose, subject string, cooldown time.Duration) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartCooldown", ctx, purpose, subject, cooldown)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartCooldown indicates an expected call of StartCooldown.
+func (mr *MockOTPStoreMockRecorder) StartCooldown(ctx, purpose, subject, cooldown any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartCooldown", reflect.TypeOf((*MockOTPStore)(nil).StartCooldown), ctx, purpose, subject, cooldown)
+}
+
+// MockOTPSender is a mock of OTPSender interface.
+type MockOTPSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockOTPSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockOTPSenderMockRecorder is the mock recorder for MockOTPSender.
+type MockOTPSenderMockRecorder struct {
+	mock *MockOTPSender
+}
+
+// NewMockOTPSender creates a new mock instance.
+func NewMockOTPSender(ctrl *gomock.Controller) *MockOTPSender {
+	mock := &MockOTPSender{ctrl: ctrl}
+	mock.recorder = &MockOTPSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOTPSender) EXPECT() *MockOTPSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockOTPSender) Send(destination, purpose, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", destination, purpose, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockOTPSenderMockRecorder) Send(destination, purpose, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockOTPSender)(nil).Send), destination, purpose, code)
+}
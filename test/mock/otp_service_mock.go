@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/otp_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/otp_service.go -destination=test/mock/otp_service_mock.go -package=mock OTPService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOTPService is a mock of OTPService interface.
+type MockOTPService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOTPServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOTPServiceMockRecorder is the mock recorder for MockOTPService.
+type MockOTPServiceMockRecorder struct {
+	mock *MockOTPService
+}
+
+// NewMockOTPService creates a new mock instance.
+func NewMockOTPService(ctrl *gomock.Controller) *MockOTPService {
+	mock := &MockOTPService{ctrl: ctrl}
+	mock.recorder = &MockOTPServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOTPService) EXPECT() *MockOTPServiceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockOTPService) Send(ctx context.Context, purpose, subject, destination, payload string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, purpose, subject, destination, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockOTPServiceMockRecorder) Send(ctx, purpose, subject, destination, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockOTPService)(nil).Send), ctx, purpose, subject, destination, payload)
+}
+
+// Verify mocks base method.
+func (m *MockOTPService) Verify(ctx context.Context, purpose, subject, code string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, purpose, subject, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockOTPServiceMockRecorder) Verify(ctx, purpose, subject, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockOTPService)(nil).Verify), ctx, purpose, subject, code)
+}
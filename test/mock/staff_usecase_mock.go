@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/staff_usecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/usecase/staff_usecase.go -destination=test/mock/staff_usecase_mock.go -package=mock StaffUseCase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	model "xyz-multifinance-api/internal/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStaffUseCase is a mock of StaffUseCase interface.
+type MockStaffUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaffUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockStaffUseCaseMockRecorder is the mock recorder for MockStaffUseCase.
+type MockStaffUseCaseMockRecorder struct {
+	mock *MockStaffUseCase
+}
+
+// NewMockStaffUseCase creates a new mock instance.
+func NewMockStaffUseCase(ctrl *gomock.Controller) *MockStaffUseCase {
+	mock := &MockStaffUseCase{ctrl: ctrl}
+	mock.recorder = &MockStaffUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStaffUseCase) EXPECT() *MockStaffUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ActivateMFA mocks base method.
+func (m *MockStaffUseCase) ActivateMFA(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateMFA", ctx, staffID, req)
+	ret0, _ := ret[0].(*model.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateMFA indicates an expected call of ActivateMFA.
+func (mr *MockStaffUseCaseMockRecorder) ActivateMFA(ctx, staffID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateMFA", reflect.TypeOf((*MockStaffUseCase)(nil).ActivateMFA), ctx, staffID, req)
+}
+
+// CreateStaff mocks base method.
+func (m *MockStaffUseCase) CreateStaff(ctx context.Context, req *model.CreateStaffRequest) (*model.StaffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStaff", ctx, req)
+	ret0, _ := ret[0].(*model.StaffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStaff indicates an expected call of CreateStaff.
+func (mr *MockStaffUseCaseMockRecorder) CreateStaff(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStaff", reflect.TypeOf((*MockStaffUseCase)(nil).CreateStaff), ctx, req)
+}
+
+// EnrollMFA mocks base method.
+func (m *MockStaffUseCase) EnrollMFA(ctx context.Context, staffID string) (*model.MFAEnrollmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollMFA", ctx, staffID)
+	ret0, _ := ret[0].(*model.MFAEnrollmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollMFA indicates an expected call of EnrollMFA.
+func (mr *MockStaffUseCaseMockRecorder) EnrollMFA(ctx, staffID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollMFA", reflect.TypeOf((*MockStaffUseCase)(nil).EnrollMFA), ctx, staffID)
+}
+
+// GetStaff mocks base method.
+func (m *MockStaffUseCase) GetStaff(ctx context.Context, id string) (*model.StaffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaff", ctx, id)
+	ret0, _ := ret[0].(*model.StaffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaff indicates an expected call of GetStaff.
+func (mr *MockStaffUseCaseMockRecorder) GetStaff(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaff", reflect.TypeOf((*MockStaffUseCase)(nil).GetStaff), ctx, id)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockStaffUseCase) RegenerateRecoveryCodes(ctx context.Context, staffID string, req *model.MFACodeRequest) (*model.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, staffID, req)
+	ret0, _ := ret[0].(*model.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockStaffUseCaseMockRecorder) RegenerateRecoveryCodes(ctx, staffID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockStaffUseCase)(nil).RegenerateRecoveryCodes), ctx, staffID, req)
+}
+
+// ResetMFA mocks base method.
+func (m *MockStaffUseCase) ResetMFA(ctx context.Context, staffID string) (*model.StaffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", ctx, staffID)
+	ret0, _ := ret[0].(*model.StaffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockStaffUseCaseMockRecorder) ResetMFA(ctx, staffID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockStaffUseCase)(nil).ResetMFA), ctx, staffID)
+}
+
+// SetStaffEnabled mocks base method.
+func (m *MockStaffUseCase) SetStaffEnabled(ctx context.Context, id string, req *model.UpdateStaffStatusRequest) (*model.StaffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStaffEnabled", ctx, id, req)
+	ret0, _ := ret[0].(*model.StaffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStaffEnabled indicates an expected call of SetStaffEnabled.
+func (mr *MockStaffUseCaseMockRecorder) SetStaffEnabled(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStaffEnabled", reflect.TypeOf((*MockStaffUseCase)(nil).SetStaffEnabled), ctx, id, req)
+}
@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/staff.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/staff.go -destination=test/mock/staff_user_repository_mock.go -package=mock StaffUserRepository,StaffRecoveryCodeRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStaffUserRepository is a mock of StaffUserRepository interface.
+type MockStaffUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaffUserRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStaffUserRepositoryMockRecorder is the mock recorder for MockStaffUserRepository.
+type MockStaffUserRepositoryMockRecorder struct {
+	mock *MockStaffUserRepository
+}
+
+// NewMockStaffUserRepository creates a new mock instance.
+func NewMockStaffUserRepository(ctrl *gomock.Controller) *MockStaffUserRepository {
+	mock := &MockStaffUserRepository{ctrl: ctrl}
+	mock.recorder = &MockStaffUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStaffUserRepository) EXPECT() *MockStaffUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ActivateMFA mocks base method.
+func (m *MockStaffUserRepository) ActivateMFA(ctx context.Context, id, secret string, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateMFA", ctx, id, secret, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateMFA indicates an expected call of ActivateMFA.
+func (mr *MockStaffUserRepositoryMockRecorder) ActivateMFA(ctx, id, secret, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateMFA", reflect.TypeOf((*MockStaffUserRepository)(nil).ActivateMFA), ctx, id, secret, at)
+}
+
+// ClaimMFAStep mocks base method.
+func (m *MockStaffUserRepository) ClaimMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMFAStep", ctx, id, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMFAStep indicates an expected call of ClaimMFAStep.
+func (mr *MockStaffUserRepositoryMockRecorder) ClaimMFAStep(ctx, id, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMFAStep", reflect.TypeOf((*MockStaffUserRepository)(nil).ClaimMFAStep), ctx, id, step)
+}
+
+// Create mocks base method.
+func (m *MockStaffUserRepository) Create(ctx context.Context, staff *domain.StaffUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, staff)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStaffUserRepositoryMockRecorder) Create(ctx, staff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStaffUserRepository)(nil).Create), ctx, staff)
+}
+
+// EnrollMFA mocks base method.
+func (m *MockStaffUserRepository) EnrollMFA(ctx context.Context, id, secret string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollMFA", ctx, id, secret)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollMFA indicates an expected call of EnrollMFA.
+func (mr *MockStaffUserRepositoryMockRecorder) EnrollMFA(ctx, id, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollMFA", reflect.TypeOf((*MockStaffUserRepository)(nil).EnrollMFA), ctx, id, secret)
+}
+
+// FindByID mocks base method.
+func (m *MockStaffUserRepository) FindByID(ctx context.Context, id string) (*domain.StaffUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.StaffUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockStaffUserRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockStaffUserRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByLogin mocks base method.
+func (m *MockStaffUserRepository) FindByLogin(ctx context.Context, login string) (*domain.StaffUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByLogin", ctx, login)
+	ret0, _ := ret[0].(*domain.StaffUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByLogin indicates an expected call of FindByLogin.
+func (mr *MockStaffUserRepositoryMockRecorder) FindByLogin(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByLogin", reflect.TypeOf((*MockStaffUserRepository)(nil).FindByLogin), ctx, login)
+}
+
+// RecordLogin mocks base method.
+func (m *MockStaffUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLogin", ctx, id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLogin indicates an expected call of RecordLogin.
+func (mr *MockStaffUserRepositoryMockRecorder) RecordLogin(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLogin", reflect.TypeOf((*MockStaffUserRepository)(nil).RecordLogin), ctx, id, at)
+}
+
+// ResetMFA mocks base method.
+func (m *MockStaffUserRepository) ResetMFA(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockStaffUserRepositoryMockRecorder) ResetMFA(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockStaffUserRepository)(nil).ResetMFA), ctx, id)
+}
+
+// SetEnabled mocks base method.
+func (m *MockStaffUserRepository) SetEnabled(ctx context.Context, id string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEnabled", ctx, id, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEnabled indicates an expected call of SetEnabled.
+func (mr *MockStaffUserRepositoryMockRecorder) SetEnabled(ctx, id, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEnabled", reflect.TypeOf((*MockStaffUserRepository)(nil).SetEnabled), ctx, id, enabled)
+}
+
+// MockStaffRecoveryCodeRepository is a mock of StaffRecoveryCodeRepository interface.
+type MockStaffRecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaffRecoveryCodeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStaffRecoveryCodeRepositoryMockRecorder is the mock recorder for MockStaffRecoveryCodeRepository.
+type MockStaffRecoveryCodeRepositoryMockRecorder struct {
+	mock *MockStaffRecoveryCodeRepository
+}
+
+// NewMockStaffRecoveryCodeRepository creates a new mock instance.
+func NewMockStaffRecoveryCodeRepository(ctrl *gomock.Controller) *MockStaffRecoveryCodeRepository {
+	mock := &MockStaffRecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockStaffRecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStaffRecoveryCodeRepository) EXPECT() *MockStaffRecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockStaffRecoveryCodeRepository) Consume(ctx context.Context, staffID, codeHash string, usedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, staffID, codeHash, usedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockStaffRecoveryCodeRepositoryMockRecorder) Consume(ctx, staffID, codeHash, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockStaffRecoveryCodeRepository)(nil).Consume), ctx, staffID, codeHash, usedAt)
+}
+
+// Replace mocks base method.
+func (m *MockStaffRecoveryCodeRepository) Replace(ctx context.Context, staffID string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, staffID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockStaffRecoveryCodeRepositoryMockRecorder) Replace(ctx, staffID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockStaffRecoveryCodeRepository)(nil).Replace), ctx, staffID, codeHashes)
+}
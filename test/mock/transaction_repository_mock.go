@@ -10,7 +10,9 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 	domain "xyz-multifinance-api/internal/domain"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,46 +42,61 @@ func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecor
 	return m.recorder
 }
 
+// CountActiveByCustomerID mocks base method.
+func (m *MockTransactionRepository) CountActiveByCustomerID(ctx context.Context, customerID string, asOf time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveByCustomerID", ctx, customerID, asOf)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveByCustomerID indicates an expected call of CountActiveByCustomerID.
+func (mr *MockTransactionRepositoryMockRecorder) CountActiveByCustomerID(ctx, customerID, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveByCustomerID", reflect.TypeOf((*MockTransactionRepository)(nil).CountActiveByCustomerID), ctx, customerID, asOf)
+}
+
 // CreateTransaction mocks base method.
-func (m *MockTransactionRepository) CreateTransaction(transaction *domain.Transaction) error {
+func (m *MockTransactionRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", transaction)
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, transaction)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockTransactionRepositoryMockRecorder) CreateTransaction(transaction any) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) CreateTransaction(ctx, transaction any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).CreateTransaction), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).CreateTransaction), ctx, transaction)
 }
 
 // GetTransactionByContractNumber mocks base method.
-func (m *MockTransactionRepository) GetTransactionByContractNumber(contractNumber string) (*domain.Transaction, error) {
+func (m *MockTransactionRepository) GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*domain.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByContractNumber", contractNumber)
+	ret := m.ctrl.Call(m, "GetTransactionByContractNumber", ctx, contractNumber)
 	ret0, _ := ret[0].(*domain.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionByContractNumber indicates an expected call of GetTransactionByContractNumber.
-func (mr *MockTransactionRepositoryMockRecorder) GetTransactionByContractNumber(contractNumber any) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionByContractNumber(ctx, contractNumber any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByContractNumber", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionByContractNumber), contractNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByContractNumber", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionByContractNumber), ctx, contractNumber)
 }
 
 // GetTransactionsByCustomerID mocks base method.
-func (m *MockTransactionRepository) GetTransactionsByCustomerID(customerID string) ([]domain.Transaction, error) {
+func (m *MockTransactionRepository) GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]domain.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByCustomerID", customerID)
+	ret := m.ctrl.Call(m, "GetTransactionsByCustomerID", ctx, customerID)
 	ret0, _ := ret[0].([]domain.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionsByCustomerID indicates an expected call of GetTransactionsByCustomerID.
-func (mr *MockTransactionRepositoryMockRecorder) GetTransactionsByCustomerID(customerID any) *gomock.Call {
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionsByCustomerID(ctx, customerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByCustomerID", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionsByCustomerID), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByCustomerID", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionsByCustomerID), ctx, customerID)
 }
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "xyz-multifinance-api/internal/model"
 
@@ -41,46 +42,46 @@ func (m *MockTransactionUseCase) EXPECT() *MockTransactionUseCaseMockRecorder {
 }
 
 // CreateTransaction mocks base method.
-func (m *MockTransactionUseCase) CreateTransaction(req *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
+func (m *MockTransactionUseCase) CreateTransaction(ctx context.Context, req *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", req)
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, req)
 	ret0, _ := ret[0].(*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockTransactionUseCaseMockRecorder) CreateTransaction(req any) *gomock.Call {
+func (mr *MockTransactionUseCaseMockRecorder) CreateTransaction(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionUseCase)(nil).CreateTransaction), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionUseCase)(nil).CreateTransaction), ctx, req)
 }
 
 // GetTransactionByContractNumber mocks base method.
-func (m *MockTransactionUseCase) GetTransactionByContractNumber(contractNumber string) (*model.TransactionResponse, error) {
+func (m *MockTransactionUseCase) GetTransactionByContractNumber(ctx context.Context, contractNumber string) (*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByContractNumber", contractNumber)
+	ret := m.ctrl.Call(m, "GetTransactionByContractNumber", ctx, contractNumber)
 	ret0, _ := ret[0].(*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionByContractNumber indicates an expected call of GetTransactionByContractNumber.
-func (mr *MockTransactionUseCaseMockRecorder) GetTransactionByContractNumber(contractNumber any) *gomock.Call {
+func (mr *MockTransactionUseCaseMockRecorder) GetTransactionByContractNumber(ctx, contractNumber any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByContractNumber", reflect.TypeOf((*MockTransactionUseCase)(nil).GetTransactionByContractNumber), contractNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByContractNumber", reflect.TypeOf((*MockTransactionUseCase)(nil).GetTransactionByContractNumber), ctx, contractNumber)
 }
 
 // GetTransactionsByCustomerID mocks base method.
-func (m *MockTransactionUseCase) GetTransactionsByCustomerID(customerID string) ([]model.TransactionResponse, error) {
+func (m *MockTransactionUseCase) GetTransactionsByCustomerID(ctx context.Context, customerID string) ([]model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByCustomerID", customerID)
+	ret := m.ctrl.Call(m, "GetTransactionsByCustomerID", ctx, customerID)
 	ret0, _ := ret[0].([]model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionsByCustomerID indicates an expected call of GetTransactionsByCustomerID.
-func (mr *MockTransactionUseCaseMockRecorder) GetTransactionsByCustomerID(customerID any) *gomock.Call {
+func (mr *MockTransactionUseCaseMockRecorder) GetTransactionsByCustomerID(ctx, customerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByCustomerID", reflect.TypeOf((*MockTransactionUseCase)(nil).GetTransactionsByCustomerID), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByCustomerID", reflect.TypeOf((*MockTransactionUseCase)(nil).GetTransactionsByCustomerID), ctx, customerID)
 }
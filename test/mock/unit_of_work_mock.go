@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/unit_of_work.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/unit_of_work.go -destination=test/mock/unit_of_work_mock.go -package=mock UnitOfWork,TxRepositories
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "xyz-multifinance-api/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTxRepositories is a mock of TxRepositories interface.
+type MockTxRepositories struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxRepositoriesMockRecorder
+	isgomock struct{}
+}
+
+// MockTxRepositoriesMockRecorder is the mock recorder for MockTxRepositories.
+type MockTxRepositoriesMockRecorder struct {
+	mock *MockTxRepositories
+}
+
+// NewMockTxRepositories creates a new mock instance.
+func NewMockTxRepositories(ctrl *gomock.Controller) *MockTxRepositories {
+	mock := &MockTxRepositories{ctrl: ctrl}
+	mock.recorder = &MockTxRepositoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxRepositories) EXPECT() *MockTxRepositoriesMockRecorder {
+	return m.recorder
+}
+
+// CreditLimits mocks base method.
+func (m *MockTxRepositories) CreditLimits() domain.CreditLimitRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditLimits")
+	ret0, _ := ret[0].(domain.CreditLimitRepository)
+	return ret0
+}
+
+// CreditLimits indicates an expected call of CreditLimits.
+func (mr *MockTxRepositoriesMockRecorder) CreditLimits() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditLimits", reflect.TypeOf((*MockTxRepositories)(nil).CreditLimits))
+}
+
+// Customers mocks base method.
+func (m *MockTxRepositories) Customers() domain.CustomerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Customers")
+	ret0, _ := ret[0].(domain.CustomerRepository)
+	return ret0
+}
+
+// Customers indicates an expected call of Customers.
+func (mr *MockTxRepositoriesMockRecorder) Customers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Customers", reflect.TypeOf((*MockTxRepositories)(nil).Customers))
+}
+
+// ErasureCertificates mocks base method.
+func (m *MockTxRepositories) ErasureCertificates() domain.ErasureCertificateRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ErasureCertificates")
+	ret0, _ := ret[0].(domain.ErasureCertificateRepository)
+	return ret0
+}
+
+// ErasureCertificates indicates an expected call of ErasureCertificates.
+func (mr *MockTxRepositoriesMockRecorder) ErasureCertificates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErasureCertificates", reflect.TypeOf((*MockTxRepositories)(nil).ErasureCertificates))
+}
+
+// Transactions mocks base method.
+func (m *MockTxRepositories) Transactions() domain.TransactionRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transactions")
+	ret0, _ := ret[0].(domain.TransactionRepository)
+	return ret0
+}
+
+// Transactions indicates an expected call of Transactions.
+func (mr *MockTxRepositoriesMockRecorder) Transactions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transactions", reflect.TypeOf((*MockTxRepositories)(nil).Transactions))
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(domain.TxRepositories) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), ctx, fn)
+}